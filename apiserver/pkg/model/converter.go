@@ -150,12 +150,34 @@ func FromCrdToApiJob(job *v1alpha1.RayJob) (pbJob *api.RayJob) {
 		JobStatus:                string(job.Status.JobStatus),
 		JobDeploymentStatus:      string(job.Status.JobDeploymentStatus),
 		Message:                  job.Status.Message,
+		EntrypointResources:      job.Spec.EntrypointResources,
+	}
+
+	if job.Spec.EntrypointNumCpus != nil {
+		pbJob.EntrypointNumCpus = float32(*job.Spec.EntrypointNumCpus)
+	}
+
+	if job.Spec.EntrypointNumGpus != nil {
+		pbJob.EntrypointNumGpus = float32(*job.Spec.EntrypointNumGpus)
 	}
 
 	if job.Spec.RayClusterSpec != nil {
 		pbJob.ClusterSpec = PopulateRayClusterSpec(*job.Spec.RayClusterSpec)
 	}
 
+	pbJob.FailureType = string(job.Status.FailureType)
+	if details := job.Status.JobDetails; details != nil {
+		pbJob.SubmissionId = details.SubmissionId
+		pbJob.DriverJobId = details.DriverJobId
+		pbJob.DriverNodeId = details.DriverNodeId
+		pbJob.DriverNodeIpAddress = details.DriverNodeIPAddress
+		pbJob.ErrorType = details.ErrorType
+		pbJob.JobRuntimeEnv = details.RuntimeEnv
+		if details.DriverExitCode != nil {
+			pbJob.DriverExitCode = *details.DriverExitCode
+		}
+	}
+
 	if job.Spec.TTLSecondsAfterFinished != nil {
 		pbJob.TtlSecondsAfterFinished = *job.Spec.TTLSecondsAfterFinished
 	}
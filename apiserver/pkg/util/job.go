@@ -44,6 +44,16 @@ func NewRayJob(apiJob *api.RayJob, computeTemplateMap map[string]*api.ComputeTem
 		},
 	}
 
+	if apiJob.EntrypointNumCpus > 0 {
+		numCpus := float64(apiJob.EntrypointNumCpus)
+		rayJob.Spec.EntrypointNumCpus = &numCpus
+	}
+	if apiJob.EntrypointNumGpus > 0 {
+		numGpus := float64(apiJob.EntrypointNumGpus)
+		rayJob.Spec.EntrypointNumGpus = &numGpus
+	}
+	rayJob.Spec.EntrypointResources = apiJob.EntrypointResources
+
 	return &RayJob{
 		rayJob,
 	}
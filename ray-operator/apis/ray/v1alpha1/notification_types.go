@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NotificationEvent is a state transition of a RayJob or a RayService that is notified to a webhook.
+// +kubebuilder:validation:Enum=JobSucceeded;JobFailed;ServiceRunning;ServiceRestarting;ServiceUpgradeComplete
+type NotificationEvent string
+
+const (
+	// JobSucceeded is notified when the Ray job of a RayJob succeeds.
+	JobSucceeded NotificationEvent = "JobSucceeded"
+	// JobFailed is notified when a RayJob fails and is not retried anymore.
+	JobFailed NotificationEvent = "JobFailed"
+	// ServiceRunning is notified when the Serve applications of a RayService become running and healthy.
+	ServiceRunning NotificationEvent = "ServiceRunning"
+	// ServiceRestarting is notified when a RayService prepares a new RayCluster because the current one is unhealthy.
+	ServiceRestarting NotificationEvent = "ServiceRestarting"
+	// ServiceUpgradeComplete is notified when a RayService switches its traffic to a new RayCluster.
+	ServiceUpgradeComplete NotificationEvent = "ServiceUpgradeComplete"
+)
+
+// NotificationSpec configures the webhook notified of the state transitions of a RayJob or a RayService.
+// Each notification is a CloudEvent sent in structured mode in an HTTP POST.
+type NotificationSpec struct {
+	// URL of the webhook.
+	URL string `json:"url"`
+	// Events are the state transitions that are notified. Defaults to all the transitions of the resource.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+	// SigningSecret selects the key of a Secret, in the namespace of the resource, used to sign the notifications
+	// with HMAC-SHA256. The signature is sent in the X-KubeRay-Signature header as sha256=<hex digest of the body>.
+	// +optional
+	SigningSecret *corev1.SecretKeySelector `json:"signingSecret,omitempty"`
+	// MaxRetries is the number of times a notification is retried, with an exponential backoff, when the webhook
+	// can't be reached or answers with a server error. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+}
@@ -132,6 +132,40 @@ type RayClusterStatus struct {
 	// RayCluster's generation, which is updated on mutation by the API Server.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// BatchScheduling reports the state of the cluster's gang in the batch scheduler, if one is used.
+	// +optional
+	BatchScheduling *BatchSchedulingStatus `json:"batchScheduling,omitempty"`
+}
+
+// BatchSchedulingPhase is the state of a RayCluster's gang as reported by the batch scheduler.
+type BatchSchedulingPhase string
+
+const (
+	// BatchSchedulingPending means the gang has been submitted but not yet admitted by the scheduler.
+	BatchSchedulingPending BatchSchedulingPhase = "Pending"
+	// BatchSchedulingUnschedulable means the scheduler reported that the gang cannot be admitted, e.g. the queue is full.
+	BatchSchedulingUnschedulable BatchSchedulingPhase = "Unschedulable"
+	// BatchSchedulingInqueue means the gang has been admitted and its pods can be created.
+	BatchSchedulingInqueue BatchSchedulingPhase = "Inqueue"
+	// BatchSchedulingRunning means the gang's minimum members are running.
+	BatchSchedulingRunning BatchSchedulingPhase = "Running"
+	// BatchSchedulingUnknown means the scheduler reported a phase KubeRay does not recognize.
+	BatchSchedulingUnknown BatchSchedulingPhase = "Unknown"
+)
+
+// BatchSchedulingStatus describes the scheduler-reported state of the cluster's gang.
+type BatchSchedulingStatus struct {
+	// SchedulerName is the name of the batch scheduler plugin, e.g. volcano.
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// Phase is the scheduler-reported phase of the gang.
+	Phase BatchSchedulingPhase `json:"phase,omitempty"`
+	// Reason is a brief CamelCase reason for the current phase.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message from the scheduler.
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the phase changed.
+	// +nullable
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
 }
 
 // HeadInfo gives info about head
@@ -188,4 +222,7 @@ type EventReason string
 const (
 	RayConfigError         EventReason = "RayConfigError"
 	PodReconciliationError EventReason = "PodReconciliationError"
+	GangPending            EventReason = "GangPending"
+	GangUnschedulable      EventReason = "GangUnschedulable"
+	GangAdmitted           EventReason = "GangAdmitted"
 )
@@ -0,0 +1,104 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how the RayJobs of a RayCronJob are handled when they overlap.
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows RayJobs to run concurrently.
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips the next run if the previous RayJob hasn't finished yet.
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the running RayJob and replaces it with a new one.
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// RayCronJobSpec defines the desired state of RayCronJob
+type RayCronJobSpec struct {
+	// Schedule is the schedule in cron format, e.g. "0 2 * * *".
+	// The @yearly, @monthly, @weekly, @daily and @hourly descriptors are also supported.
+	Schedule string `json:"schedule"`
+	// TimeZone is the name of the time zone of the schedule, e.g. "Europe/Paris". Defaults to the
+	// time zone of the KubeRay operator.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+	// StartingDeadlineSeconds is the deadline in seconds for starting a RayJob if it misses its scheduled
+	// time for any reason. Missed runs are counted as failed ones.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// ConcurrencyPolicy specifies how to treat concurrent runs of the RayJob: Allow (the default),
+	// Forbid or Replace.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// Suspend tells the controller to suspend subsequent runs. It does not apply to RayJobs that
+	// already started.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+	// SuccessfulJobsHistoryLimit is the number of successful finished RayJobs to keep. Defaults to 3.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	// FailedJobsHistoryLimit is the number of failed finished RayJobs to keep. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+	// JobTemplate is the template of the RayJobs created by the RayCronJob.
+	JobTemplate RayJobTemplateSpec `json:"jobTemplate"`
+}
+
+// RayJobTemplateSpec describes the RayJob created by a RayCronJob when it runs.
+type RayJobTemplateSpec struct {
+	// Labels and annotations of the RayJobs created from this template.
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec of the RayJobs created from this template.
+	Spec RayJobSpec `json:"spec"`
+}
+
+// RayCronJobStatus defines the observed state of RayCronJob
+type RayCronJobStatus struct {
+	// Active holds references to the RayJobs that haven't finished yet.
+	// +optional
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// LastScheduleTime is the last time a RayJob was successfully scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastSuccessfulTime is the last time a RayJob finished successfully.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="schedule",type=string,JSONPath=".spec.schedule",priority=0
+//+kubebuilder:printcolumn:name="suspend",type=boolean,JSONPath=".spec.suspend",priority=0
+//+kubebuilder:printcolumn:name="last schedule",type=date,JSONPath=".status.lastScheduleTime",priority=0
+//+kubebuilder:printcolumn:name="age",type=date,JSONPath=".metadata.creationTimestamp",priority=0
+//+genclient
+// RayCronJob is the Schema for the raycronjobs API
+type RayCronJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RayCronJobSpec   `json:"spec,omitempty"`
+	Status RayCronJobStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RayCronJobList contains a list of RayCronJob
+type RayCronJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RayCronJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RayCronJob{}, &RayCronJobList{})
+}
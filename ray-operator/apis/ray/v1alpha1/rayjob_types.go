@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -40,12 +41,178 @@ const (
 	JobDeploymentStatusFailedToGetJobStatus          JobDeploymentStatus = "FailedToGetJobStatus"
 	JobDeploymentStatusComplete                      JobDeploymentStatus = "Complete"
 	JobDeploymentStatusSuspended                     JobDeploymentStatus = "Suspended"
+	JobDeploymentStatusRetrying                      JobDeploymentStatus = "Retrying"
+	JobDeploymentStatusQueued                        JobDeploymentStatus = "Queued"
 )
 
+// JobFailedReason indicates why an attempt of the RayJob failed.
+type JobFailedReason string
+
+const (
+	// AppFailed means the Ray job itself ended FAILED.
+	AppFailed JobFailedReason = "AppFailed"
+	// ClusterFailed means the RayCluster of the attempt could not be got or created.
+	ClusterFailed JobFailedReason = "ClusterFailed"
+	// HeadPodLost means the Ray job disappeared from the RayCluster, e.g. because the head pod was lost.
+	HeadPodLost JobFailedReason = "HeadPodLost"
+	// DeadlineExceeded means the RayJob did not start within startupTimeoutSeconds,
+	// or did not finish within activeDeadlineSeconds.
+	DeadlineExceeded JobFailedReason = "DeadlineExceeded"
+	// DashboardUnreachable means the dashboard of the RayCluster could not be reached for longer than
+	// dashboardUnreachableSecondThreshold while the Ray job was running, and no retries were left. The Ray job
+	// is retried with the HeadPodLost reason otherwise.
+	DashboardUnreachable JobFailedReason = "DashboardUnreachable"
+	// InvalidSpec means the spec of the RayJob is invalid. It is not retried.
+	InvalidSpec JobFailedReason = "InvalidSpec"
+)
+
+// JobFailureType classifies why the Ray job of a RayJob failed.
+type JobFailureType string
+
+const (
+	// UserCodeFailure means the entrypoint of the Ray job failed.
+	UserCodeFailure JobFailureType = "UserCode"
+	// OutOfMemoryFailure means the driver or a task of the Ray job ran out of memory.
+	OutOfMemoryFailure JobFailureType = "OutOfMemory"
+	// RuntimeEnvSetupFailure means the runtime environment of the Ray job could not be set up.
+	RuntimeEnvSetupFailure JobFailureType = "RuntimeEnvSetup"
+	// ClusterLostFailure means the Ray job was lost by the RayCluster, e.g. because the node of its driver
+	// or the head pod was lost.
+	ClusterLostFailure JobFailureType = "ClusterLost"
+)
+
+// DeletionPolicyType is what happens to the resources of a RayJob once its Ray job finishes.
+type DeletionPolicyType string
+
+const (
+	// DeleteCluster deletes the RayCluster of the RayJob.
+	DeleteCluster DeletionPolicyType = "DeleteCluster"
+	// DeleteWorkers scales all the worker groups of the RayCluster to zero and keeps the head.
+	DeleteWorkers DeletionPolicyType = "DeleteWorkers"
+	// DeleteSelf deletes the RayJob, and the RayCluster with it.
+	DeleteSelf DeletionPolicyType = "DeleteSelf"
+	// DeleteNone keeps all the resources of the RayJob.
+	DeleteNone DeletionPolicyType = "DeleteNone"
+)
+
+// DeletionPolicy configures what happens to the resources of a RayJob once its Ray job succeeds or fails.
+type DeletionPolicy struct {
+	// OnSuccess applies once the Ray job SUCCEEDED.
+	OnSuccess DeletionRule `json:"onSuccess"`
+	// OnFailure applies once the Ray job FAILED, after its last attempt.
+	OnFailure DeletionRule `json:"onFailure"`
+}
+
+// DeletionRule is what happens to the resources of a finished RayJob, and when.
+type DeletionRule struct {
+	// +kubebuilder:validation:Enum=DeleteCluster;DeleteWorkers;DeleteSelf;DeleteNone
+	Policy DeletionPolicyType `json:"policy"`
+	// TTLSecondsAfterFinished is how long to wait after the Ray job finished before applying the policy.
+	// Defaults to 0.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// RetryPolicy indicates which failures of a RayJob are retried.
+type RetryPolicy string
+
+const (
+	// RetryOnFailure retries the RayJob on any failure.
+	RetryOnFailure RetryPolicy = "OnFailure"
+	// RetryOnInfraFailure only retries the RayJob on cluster or infrastructure failures, not when the Ray job fails.
+	RetryOnInfraFailure RetryPolicy = "OnInfraFailure"
+)
+
+// JobSubmissionMode indicates how the Ray job is submitted to the RayCluster.
+type JobSubmissionMode string
+
+const (
+	// HTTPMode submits the Ray job from the operator through the Ray dashboard HTTP API.
+	HTTPMode JobSubmissionMode = "HTTPMode"
+	// K8sJobMode submits the Ray job from a Kubernetes Job that runs `ray job submit` against the head service.
+	K8sJobMode JobSubmissionMode = "K8sJobMode"
+)
+
+// DriverLogsSpec configures how the driver logs of the Ray job are persisted once it finishes.
+type DriverLogsSpec struct {
+	// TailLines is the number of lines at the end of the driver logs that are stored in the RayJob status.
+	// Defaults to 50. Set it to 0 to keep no logs in the status.
+	// +optional
+	TailLines *int32 `json:"tailLines,omitempty"`
+	// Sink is where the full driver logs are stored. If it is not set, only the tail is kept.
+	// +optional
+	Sink *DriverLogsSink `json:"sink,omitempty"`
+}
+
+// DriverLogsSink is the destination of the full driver logs. Exactly one of its fields should be set.
+type DriverLogsSink struct {
+	// ConfigMap stores the logs in a ConfigMap in the namespace of the RayJob.
+	// Logs larger than a ConfigMap can hold are truncated from the beginning.
+	// +optional
+	ConfigMap *ConfigMapLogsSink `json:"configMap,omitempty"`
+	// PersistentVolumeClaim stores the logs in a file on a PersistentVolumeClaim that is mounted into the operator.
+	// +optional
+	PersistentVolumeClaim *PVCLogsSink `json:"persistentVolumeClaim,omitempty"`
+	// ObjectStore stores the logs as an object under a prefix in an object store.
+	// +optional
+	ObjectStore *ObjectStoreLogsSink `json:"objectStore,omitempty"`
+}
+
+// ConfigMapLogsSink stores the driver logs in a ConfigMap.
+type ConfigMapLogsSink struct {
+	// Name of the ConfigMap. Defaults to <rayjob name>-driver-logs.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// PVCLogsSink stores the driver logs in a file on a PersistentVolumeClaim.
+type PVCLogsSink struct {
+	// ClaimName is the name of the PersistentVolumeClaim.
+	ClaimName string `json:"claimName"`
+	// Path is the directory on the volume under which the logs are written.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ObjectStoreLogsSink stores the driver logs in an object store.
+type ObjectStoreLogsSink struct {
+	// Prefix is the URL under which the logs are written, e.g. s3://bucket/ray-logs.
+	// The scheme selects the object store implementation.
+	Prefix string `json:"prefix"`
+}
+
+// WorkingDirSource is where the files of the working directory of the Ray job come from.
+// Exactly one of its fields should be set.
+type WorkingDirSource struct {
+	// ConfigMap provides the files of the working directory, one per key.
+	// +optional
+	ConfigMap *ConfigMapWorkingDirSource `json:"configMap,omitempty"`
+	// PersistentVolumeClaim provides the working directory as a directory of a PersistentVolumeClaim.
+	// +optional
+	PersistentVolumeClaim *PVCWorkingDirSource `json:"persistentVolumeClaim,omitempty"`
+}
+
+// ConfigMapWorkingDirSource provides the working directory from a ConfigMap.
+type ConfigMapWorkingDirSource struct {
+	// Name of the ConfigMap, in the namespace of the RayJob.
+	Name string `json:"name"`
+}
+
+// PVCWorkingDirSource provides the working directory from a PersistentVolumeClaim.
+type PVCWorkingDirSource struct {
+	// ClaimName is the name of the PersistentVolumeClaim.
+	ClaimName string `json:"claimName"`
+	// SubPath is the directory on the volume that is used as the working directory. Defaults to the root of the volume.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
 // RayJobSpec defines the desired state of RayJob
 type RayJobSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+	// Entrypoint is required unless ExternalJobId is set.
+	// +optional
 	Entrypoint string `json:"entrypoint"`
 	// Metadata is data to store along with this job.
 	Metadata map[string]string `json:"metadata,omitempty"`
@@ -53,11 +220,39 @@ type RayJobSpec struct {
 	RuntimeEnv string `json:"runtimeEnv,omitempty"`
 	// If jobId is not set, a new jobId will be auto-generated.
 	JobId string `json:"jobId,omitempty"`
+	// ExternalJobId is the submission ID of a Ray job submitted outside of KubeRay to the RayCluster selected by
+	// clusterSelector. The RayJob tracks this Ray job instead of submitting its entrypoint, and never retries it.
+	// +optional
+	ExternalJobId string `json:"externalJobId,omitempty"`
+	// WorkingDirFrom mounts a ConfigMap or a PersistentVolumeClaim as the working_dir of the Ray job.
+	// It is mounted into the submitter pod, which uploads it. It requires K8sJobMode, so RayJobs that submit the
+	// Ray job over HTTP are rejected.
+	// +optional
+	WorkingDirFrom *WorkingDirSource `json:"workingDirFrom,omitempty"`
+	// EntrypointNumCpus is the number of CPUs to reserve for the entrypoint command.
+	// +optional
+	EntrypointNumCpus *float64 `json:"entrypointNumCpus,omitempty"`
+	// EntrypointNumGpus is the number of GPUs to reserve for the entrypoint command.
+	// +optional
+	EntrypointNumGpus *float64 `json:"entrypointNumGpus,omitempty"`
+	// EntrypointResources is a JSON-encoded map of the custom resources to reserve for the entrypoint
+	// command, e.g. '{"worker_node": 1}'.
+	// +optional
+	EntrypointResources string `json:"entrypointResources,omitempty"`
 	// ShutdownAfterJobFinishes will determine whether to delete the ray cluster once rayJob succeed or failed.
 	ShutdownAfterJobFinishes bool `json:"shutdownAfterJobFinishes,omitempty"`
 	// TTLSecondsAfterFinished is the TTL to clean up RayCluster.
 	// It's only working when ShutdownAfterJobFinishes set to true.
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// DashboardUnreachableSecondThreshold is how long the dashboard of the RayCluster may be unreachable while the
+	// Ray job is running before the Ray job is declared lost, and retried or failed. Defaults to 300.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	DashboardUnreachableSecondThreshold *int32 `json:"dashboardUnreachableSecondThreshold,omitempty"`
+	// DeletionPolicy configures what happens to the resources of the RayJob once the Ray job succeeds or fails.
+	// It takes precedence over ShutdownAfterJobFinishes and TTLSecondsAfterFinished.
+	// +optional
+	DeletionPolicy *DeletionPolicy `json:"deletionPolicy,omitempty"`
 	// RayClusterSpec is the cluster template to run the job
 	RayClusterSpec *RayClusterSpec `json:"rayClusterSpec,omitempty"`
 	// clusterSelector is used to select running rayclusters by labels
@@ -68,6 +263,45 @@ type RayJobSpec struct {
 	// If the RayCluster is already created, it will be deleted.
 	// In case of transition to false a new RayCluster will be created.
 	Suspend bool `json:"suspend,omitempty"`
+	// SubmissionMode specifies how the Ray job is submitted to the RayCluster.
+	// Defaults to HTTPMode. In K8sJobMode, a Kubernetes Job submits the Ray job and follows its logs.
+	// +kubebuilder:validation:Enum=HTTPMode;K8sJobMode
+	// +optional
+	SubmissionMode JobSubmissionMode `json:"submissionMode,omitempty"`
+	// SubmitterPodTemplate is the template for the pod of the submitter Kubernetes Job.
+	// It is only used in K8sJobMode. If it is not set, the image of the Ray head container is used.
+	// +optional
+	SubmitterPodTemplate *corev1.PodTemplateSpec `json:"submitterPodTemplate,omitempty"`
+	// DriverLogs configures how the driver logs are persisted once the Ray job finishes,
+	// before the RayCluster is deleted.
+	// +optional
+	DriverLogs *DriverLogsSpec `json:"driverLogs,omitempty"`
+	// BackoffLimit is the number of retries before the RayJob is marked as failed. Each retry runs the
+	// Ray job with a new job ID, on a new RayCluster unless clusterSelector is used. Defaults to 0.
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// RetryPolicy specifies which failures are retried: OnFailure retries all of them,
+	// OnInfraFailure only the failures of the RayCluster. Defaults to OnFailure.
+	// +kubebuilder:validation:Enum=OnFailure;OnInfraFailure
+	// +optional
+	RetryPolicy RetryPolicy `json:"retryPolicy,omitempty"`
+	// ActiveDeadlineSeconds is the duration in seconds, from the start of the Ray job, after which the Ray job
+	// is stopped and the RayJob fails with the DeadlineExceeded reason. It is not retried.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int32 `json:"activeDeadlineSeconds,omitempty"`
+	// StartupTimeoutSeconds is the duration in seconds that the RayJob may wait for its RayCluster to be ready
+	// and for the Ray job to start before it fails with the DeadlineExceeded reason. It is not retried.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StartupTimeoutSeconds *int32 `json:"startupTimeoutSeconds,omitempty"`
+	// Priority orders the RayJobs waiting for a RayCluster shared through clusterSelector, when the RayCluster
+	// limits its concurrent jobs. Higher priorities are submitted first. Defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Notifications configures a webhook notified when the RayJob succeeds, or fails and is not retried anymore.
+	// +optional
+	Notifications *NotificationSpec `json:"notifications,omitempty"`
 }
 
 // RayJobStatus defines the observed state of RayJob
@@ -91,6 +325,82 @@ type RayJobStatus struct {
 	// RayJob's generation, which is updated on mutation by the API Server.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DriverLogs holds the tail of the driver logs and the location of the full logs once the job finishes.
+	// +optional
+	DriverLogs *DriverLogsStatus `json:"driverLogs,omitempty"`
+	// Reason is why the last failed attempt of the RayJob failed.
+	// +optional
+	Reason JobFailedReason `json:"reason,omitempty"`
+	// Failed is the number of failed attempts of the RayJob.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+	// Attempts is the history of the failed attempts of the RayJob, oldest first.
+	// +optional
+	Attempts []RayJobAttempt `json:"attempts,omitempty"`
+	// AttemptStartTime is when the current attempt left the Suspended, Queued or Retrying status.
+	// startupTimeoutSeconds is measured from it.
+	// +optional
+	AttemptStartTime *metav1.Time `json:"attemptStartTime,omitempty"`
+	// QueuePosition is the position of the RayJob in the queue of its RayCluster while it is Queued,
+	// starting from 1 for the next RayJob to be submitted.
+	// +optional
+	QueuePosition int32 `json:"queuePosition,omitempty"`
+	// JobDetails holds the details of the Ray job of the current attempt reported by the Ray Jobs API.
+	// +optional
+	JobDetails *RayJobDetails `json:"jobDetails,omitempty"`
+	// FailureType classifies why the Ray job of the current attempt failed.
+	// +optional
+	FailureType JobFailureType `json:"failureType,omitempty"`
+	// UntrackedJobs are the IDs of the active Ray jobs on the RayCluster of the RayJob that the RayJob does not
+	// track, e.g. because they were submitted out of band. Only RayClusters created by the RayJob are checked.
+	// +optional
+	UntrackedJobs []string `json:"untrackedJobs,omitempty"`
+	// DashboardUnreachableSince is when the dashboard of the RayCluster became unreachable while the Ray job
+	// was running. It is cleared once the dashboard is reachable again.
+	// +optional
+	DashboardUnreachableSince *metav1.Time `json:"dashboardUnreachableSince,omitempty"`
+}
+
+// RayJobDetails describes the Ray job as reported by the Ray Jobs API.
+type RayJobDetails struct {
+	// SubmissionId is the ID the Ray job was submitted with, which is the jobId of the RayJob status.
+	SubmissionId string `json:"submissionId,omitempty"`
+	// DriverJobId is the ID Ray assigned to the job of the driver, e.g. 02000000.
+	DriverJobId string `json:"driverJobId,omitempty"`
+	// DriverNodeId is the ID of the Ray node the driver runs on.
+	DriverNodeId string `json:"driverNodeId,omitempty"`
+	// DriverNodeIPAddress is the IP address of the Ray node the driver runs on.
+	DriverNodeIPAddress string `json:"driverNodeIPAddress,omitempty"`
+	// DriverExitCode is the exit code of the driver once it exited.
+	DriverExitCode *int32 `json:"driverExitCode,omitempty"`
+	// ErrorType is the type of the error the Ray job failed with, e.g. RUNTIME_ENV_SETUP_FAILURE.
+	ErrorType string `json:"errorType,omitempty"`
+	// Entrypoint is the entrypoint the Ray job runs.
+	Entrypoint string `json:"entrypoint,omitempty"`
+	// RuntimeEnv is the JSON-encoded runtime environment the Ray job runs with.
+	RuntimeEnv string `json:"runtimeEnv,omitempty"`
+}
+
+// RayJobAttempt records a failed attempt of a RayJob.
+type RayJobAttempt struct {
+	JobId          string          `json:"jobId,omitempty"`
+	RayClusterName string          `json:"rayClusterName,omitempty"`
+	JobStatus      JobStatus       `json:"jobStatus,omitempty"`
+	Reason         JobFailedReason `json:"reason,omitempty"`
+	FailureType    JobFailureType  `json:"failureType,omitempty"`
+	Message        string          `json:"message,omitempty"`
+	StartTime      *metav1.Time    `json:"startTime,omitempty"`
+	EndTime        *metav1.Time    `json:"endTime,omitempty"`
+}
+
+// DriverLogsStatus describes the driver logs persisted after the Ray job finished.
+type DriverLogsStatus struct {
+	// Tail is the end of the driver logs.
+	Tail string `json:"tail,omitempty"`
+	// Location is where the full driver logs are stored, if a sink is configured.
+	Location string `json:"location,omitempty"`
+	// Message explains why the driver logs could not be persisted, if they could not.
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -19,6 +19,8 @@ const (
 	FailedToUpdateIngress            ServiceStatus = "FailedToUpdateIngress"
 	FailedToUpdateServingPodLabel    ServiceStatus = "FailedToUpdateServingPodLabel"
 	FailedToUpdateService            ServiceStatus = "FailedToUpdateService"
+	InvalidServeConfig               ServiceStatus = "InvalidServeConfig"
+	ShiftingTraffic                  ServiceStatus = "ShiftingTraffic"
 )
 
 // These statuses should match Ray Serve's application statuses
@@ -52,6 +54,251 @@ type RayServiceSpec struct {
 	RayClusterSpec                     RayClusterSpec           `json:"rayClusterConfig,omitempty"`
 	ServiceUnhealthySecondThreshold    *int32                   `json:"serviceUnhealthySecondThreshold,omitempty"`
 	DeploymentUnhealthySecondThreshold *int32                   `json:"deploymentUnhealthySecondThreshold,omitempty"`
+	// ServeApplications are the Serve applications deployed with the Serve applications API, each one with its
+	// own route prefix. It can't be combined with serveConfig or serveConfigV2.
+	// +optional
+	ServeApplications []ServeApplicationSpec `json:"serveApplications,omitempty"`
+	// ServeConfigV2 is a Serve config file in YAML, as accepted by `serve deploy`, deployed with the Serve
+	// applications API. It can't be combined with serveConfig or serveApplications.
+	// +optional
+	ServeConfigV2 string `json:"serveConfigV2,omitempty"`
+	// Notifications configures a webhook notified when the RayService becomes running, restarts its RayCluster
+	// or completes an upgrade.
+	// +optional
+	Notifications *NotificationSpec `json:"notifications,omitempty"`
+	// UpgradeStrategy configures how the traffic moves from the active RayCluster to a new one. Defaults to
+	// switching all the traffic at once.
+	// +optional
+	UpgradeStrategy *RayServiceUpgradeStrategy `json:"upgradeStrategy,omitempty"`
+	// RevisionHistoryLimit is the number of revisions of the RayCluster and Serve configs kept to roll back to.
+	// Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+	// AutoRollback rolls the RayService back to its last healthy revision when an update doesn't become healthy
+	// in time.
+	// +optional
+	AutoRollback *AutoRollbackSpec `json:"autoRollback,omitempty"`
+	// PendingClusterTimeoutSeconds is the time a pending RayCluster has to become ready, including its restarts.
+	// After it, the pending RayCluster is deleted and the active one keeps serving until the spec changes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PendingClusterTimeoutSeconds *int32 `json:"pendingClusterTimeoutSeconds,omitempty"`
+	// ServeProxyHealthCheck configures the health checks of the Serve HTTP proxies, which decide the pods the serve
+	// Service routes the traffic to.
+	// +optional
+	ServeProxyHealthCheck *ServeProxyHealthCheckSpec `json:"serveProxyHealthCheck,omitempty"`
+	// ApplicationProbes are HTTP requests sent to the Serve applications of a RayCluster once Serve reports them
+	// healthy. A pending RayCluster only becomes active when its last probes succeeded, and a RayCluster is
+	// restarted when a probe reaches its failure threshold.
+	// +optional
+	ApplicationProbes []ServeApplicationProbe `json:"applicationProbes,omitempty"`
+	// ServeGRPC exposes the gRPC proxy of Ray Serve through the serve Service, next to the HTTP proxy.
+	// +optional
+	ServeGRPC *ServeGRPCSpec `json:"serveGRPC,omitempty"`
+	// ServeIngress exposes the serve Service outside the Kubernetes cluster with an Ingress, which follows the
+	// active RayCluster.
+	// +optional
+	ServeIngress *ServeIngressSpec `json:"serveIngress,omitempty"`
+}
+
+// ServeIngressSpec configures the Ingress routing the user traffic to the serve Service of a RayService. With a
+// TrafficShifting upgrade using the Ingress router, it also configures the Ingresses splitting the traffic.
+type ServeIngressSpec struct {
+	// IngressClassName is the class of the Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Host is the host name routed to the serve Service. Defaults to any host.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Paths are the path prefixes routed to the serve Service. Defaults to the route prefixes of serveApplications,
+	// or to /.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+	// TLS terminates the TLS of the hosts with the certificates of Secrets.
+	// +optional
+	TLS []ServeIngressTLS `json:"tls,omitempty"`
+	// Annotations are added to the Ingress, e.g. to configure the ingress controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServeIngressTLS is a TLS certificate of the Ingress of a RayService.
+type ServeIngressTLS struct {
+	// Hosts are the host names of the certificate. Defaults to the host of the Ingress.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// SecretName is the name of the Secret holding the certificate and its key.
+	SecretName string `json:"secretName"`
+}
+
+// ServeGRPCSpec configures the gRPC proxy of Ray Serve. It listens on the head container port named serve-grpc,
+// or 9000, and receives the traffic on the same pods as the HTTP proxy.
+type ServeGRPCSpec struct {
+	// ServicerFunctions are the import paths of the functions adding the gRPC servicers of the applications to the
+	// gRPC server, e.g. user_defined_protos_pb2_grpc.add_UserDefinedServiceServicer_to_server. They are only used
+	// with serveApplications. With serveConfigV2, set grpc_options in the config instead.
+	// +optional
+	ServicerFunctions []string `json:"servicerFunctions,omitempty"`
+	// Route exposes the gRPC port of the serve Service outside the Kubernetes cluster.
+	// +optional
+	Route *ServeGRPCRouteSpec `json:"route,omitempty"`
+}
+
+// ServeGRPCRouteSpec configures the Ingress or the GRPCRoute of the gRPC port of the serve Service.
+type ServeGRPCRouteSpec struct {
+	// Router routes the gRPC traffic. The Ingress router needs the NGINX ingress controller, and the Gateway router
+	// creates a GRPCRoute of the Gateway API. Defaults to Ingress.
+	// +optional
+	Router TrafficRouter `json:"router,omitempty"`
+	// IngressClassName is the class of the Ingress of the Ingress router.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Host is the host name routed to the gRPC port. Defaults to any host.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Gateway is the Gateway the GRPCRoute of the Gateway router is attached to.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// ServeApplicationProbe is an HTTP request sent to the Serve HTTP proxy on the head of a RayCluster to check the
+// responses of a Serve application.
+type ServeApplicationProbe struct {
+	// Name of the probe, unique within the RayService.
+	Name string `json:"name"`
+	// Path of the request, including the route prefix of the application, e.g. /fruit/healthz.
+	Path string `json:"path"`
+	// Method of the request. Defaults to GET.
+	// +kubebuilder:validation:Enum=GET;POST;PUT;HEAD
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Body of the request.
+	// +optional
+	Body string `json:"body,omitempty"`
+	// ExpectedStatus is the status code of a successful response. Defaults to any 2xx status code.
+	// +optional
+	ExpectedStatus *int32 `json:"expectedStatus,omitempty"`
+	// ExpectedBodyRegex is a regular expression matched by the body of a successful response.
+	// +optional
+	ExpectedBodyRegex string `json:"expectedBodyRegex,omitempty"`
+	// PeriodSeconds is the time between two requests. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the timeout of each request. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failed requests for the RayCluster to be unhealthy. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// ServeProxyHealthCheckSpec configures the health checks of the Serve HTTP proxies, like the probes of the kubelet.
+type ServeProxyHealthCheckSpec struct {
+	// TimeoutSeconds is the timeout of each health check. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// SuccessThreshold is the number of consecutive successful health checks for a pod to receive traffic.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+	// FailureThreshold is the number of consecutive failed health checks for a pod to stop receiving traffic.
+	// Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// AutoRollbackSpec configures the automatic rollback of a RayService to its last healthy revision.
+type AutoRollbackSpec struct {
+	// DeadlineSeconds is the time an update has to become healthy before the RayService is rolled back.
+	// +kubebuilder:validation:Minimum=1
+	DeadlineSeconds int32 `json:"deadlineSeconds"`
+}
+
+// RevisionOutcome is the outcome of a revision of a RayService.
+type RevisionOutcome string
+
+const (
+	// RevisionPending is the outcome of a revision that hasn't become healthy yet.
+	RevisionPending RevisionOutcome = "Pending"
+	// RevisionHealthy is the outcome of a revision that served the traffic of the RayService while healthy.
+	RevisionHealthy RevisionOutcome = "Healthy"
+	// RevisionFailed is the outcome of a revision that didn't become healthy before the auto rollback deadline.
+	RevisionFailed RevisionOutcome = "Failed"
+)
+
+// UpgradeFailedReason is the reason of a RayService whose pending RayCluster didn't become ready before the pending
+// cluster timeout.
+const UpgradeFailedReason = "UpgradeFailed"
+
+// RayServiceUpgradeType is the way a RayService moves its traffic to a new RayCluster.
+// +kubebuilder:validation:Enum=Switch;TrafficShifting
+type RayServiceUpgradeType string
+
+const (
+	// SwitchUpgrade switches the serve Service to the new RayCluster once it is ready.
+	SwitchUpgrade RayServiceUpgradeType = "Switch"
+	// TrafficShiftingUpgrade moves the traffic to the new RayCluster in weighted steps.
+	TrafficShiftingUpgrade RayServiceUpgradeType = "TrafficShifting"
+)
+
+// RayServiceUpgradeStrategy configures how a RayService moves its traffic to a new RayCluster.
+type RayServiceUpgradeStrategy struct {
+	// Type of the upgrade. Defaults to Switch.
+	// +optional
+	Type RayServiceUpgradeType `json:"type,omitempty"`
+	// TrafficShifting configures the TrafficShifting upgrade.
+	// +optional
+	TrafficShifting *TrafficShiftingSpec `json:"trafficShifting,omitempty"`
+}
+
+// TrafficRouter routes the weighted traffic between the RayClusters of a RayService.
+// +kubebuilder:validation:Enum=Ingress;Gateway
+type TrafficRouter string
+
+const (
+	// IngressRouter routes the traffic with an Ingress and a canary Ingress of the NGINX ingress controller.
+	IngressRouter TrafficRouter = "Ingress"
+	// GatewayRouter routes the traffic with an HTTPRoute of the Gateway API.
+	GatewayRouter TrafficRouter = "Gateway"
+)
+
+// TrafficShiftingSpec configures the steps in which the traffic moves to a new RayCluster.
+type TrafficShiftingSpec struct {
+	// Steps are the percentages of the traffic routed to the new RayCluster, in increasing order, before it
+	// receives all the traffic. Defaults to [10, 50].
+	// +optional
+	Steps []int32 `json:"steps,omitempty"`
+	// StepIntervalSeconds is the time spent at each step, while the new RayCluster stays healthy. Defaults to 60.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StepIntervalSeconds *int32 `json:"stepIntervalSeconds,omitempty"`
+	// Router routes the weighted traffic. Defaults to Ingress.
+	// +optional
+	Router TrafficRouter `json:"router,omitempty"`
+	// IngressClassName is the class of the Ingresses of the Ingress router.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Host is the host name routed to the RayService. Defaults to any host.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Gateway is the Gateway the HTTPRoute of the Gateway router is attached to.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference references a Gateway of the Gateway API.
+type GatewayReference struct {
+	Name string `json:"name"`
+	// Namespace of the Gateway. Defaults to the namespace of the RayService.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type ServeDeploymentGraphSpec struct {
@@ -61,6 +308,27 @@ type ServeDeploymentGraphSpec struct {
 	Port             int               `json:"port,omitempty"`
 }
 
+// ServeApplicationSpec defines a Serve application deployed with the Serve applications API.
+type ServeApplicationSpec struct {
+	// Name of the application, unique within the RayService.
+	Name string `json:"name"`
+	// ImportPath of the application, e.g. fruit.deployment_graph.
+	ImportPath string `json:"importPath"`
+	// RoutePrefix is the HTTP route prefix of the application. Defaults to / in Ray, so it must be set when
+	// there are several applications.
+	// +optional
+	RoutePrefix string `json:"routePrefix,omitempty"`
+	// RuntimeEnv is the runtime environment of the application in YAML.
+	// +optional
+	RuntimeEnv string `json:"runtimeEnv,omitempty"`
+	// Args are the arguments, in YAML, passed to the application builder of ImportPath.
+	// +optional
+	Args string `json:"args,omitempty"`
+	// Deployments override the options of the deployments of the application.
+	// +optional
+	ServeConfigSpecs []ServeConfigSpec `json:"deployments,omitempty"`
+}
+
 // ServeConfigSpec defines the desired state of RayService
 // Reference to http://rayserve.org
 type ServeConfigSpec struct {
@@ -100,6 +368,38 @@ type RayServiceStatuses struct {
 	// RayService's generation, which is updated on mutation by the API Server.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTrafficShiftTime is the last time the traffic moved to the pending RayCluster in a TrafficShifting upgrade.
+	// +optional
+	LastTrafficShiftTime *metav1.Time `json:"lastTrafficShiftTime,omitempty"`
+	// CurrentRevision is the name of the ControllerRevision of the last healthy config of the RayService.
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+	// UpdateRevision is the name of the ControllerRevision of the config the RayService is reconciled to.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+	// UpdateRevisionTime is the time the RayService started to be reconciled to UpdateRevision.
+	// +optional
+	UpdateRevisionTime *metav1.Time `json:"updateRevisionTime,omitempty"`
+	// RollbackRevision is the name of the ControllerRevision the RayService is rolled back to. It is deployed
+	// instead of the spec until the spec changes.
+	// +optional
+	RollbackRevision string `json:"rollbackRevision,omitempty"`
+	// RollbackGeneration is the generation of the RayService when it was rolled back to RollbackRevision.
+	// +optional
+	RollbackGeneration int64 `json:"rollbackGeneration,omitempty"`
+	// PendingClusterStartTime is the time the RayService started to prepare its pending RayCluster.
+	// +optional
+	PendingClusterStartTime *metav1.Time `json:"pendingClusterStartTime,omitempty"`
+	// Reason is why the RayService doesn't run its spec, e.g. UpgradeFailed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message about Reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// UpgradeFailedGeneration is the generation of the RayService whose upgrade failed. The active RayCluster keeps
+	// serving until the spec changes.
+	// +optional
+	UpgradeFailedGeneration int64 `json:"upgradeFailedGeneration,omitempty"`
 }
 
 type RayServiceStatus struct {
@@ -109,6 +409,27 @@ type RayServiceStatus struct {
 	DashboardStatus   DashboardStatus         `json:"dashboardStatus,omitempty"`
 	RayClusterName    string                  `json:"rayClusterName,omitempty"`
 	RayClusterStatus  RayClusterStatus        `json:"rayClusterStatus,omitempty"`
+	// Applications are the statuses of the Serve applications, keyed by application name, when the Serve
+	// applications API is used. ApplicationStatus and ServeStatuses are then not set.
+	// +optional
+	Applications map[string]AppStatus `json:"applicationStatuses,omitempty"`
+	// TrafficWeight is the percentage of the traffic routed to the RayCluster by a TrafficShifting upgrade strategy.
+	// +optional
+	TrafficWeight *int32 `json:"trafficWeight,omitempty"`
+	// ApplicationProbes are the results of the application probes on the RayCluster, keyed by probe name.
+	// +optional
+	ApplicationProbes map[string]ApplicationProbeStatus `json:"applicationProbeStatuses,omitempty"`
+}
+
+// ApplicationProbeStatus is the result of an application probe on a RayCluster.
+type ApplicationProbeStatus struct {
+	// Succeeded is whether the last request of the probe succeeded.
+	Succeeded bool `json:"succeeded,omitempty"`
+	// ConsecutiveFailures is the number of consecutive failed requests of the probe.
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+	// Message explains the failure of the last request.
+	Message       string       `json:"message,omitempty"`
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
 // DashboardStatus defines the current states of Ray Dashboard
@@ -127,6 +448,10 @@ type AppStatus struct {
 	// Keep track of how long the service is healthy.
 	// Update when Serve deployment is healthy or first time convert to unhealthy from healthy.
 	HealthLastUpdateTime *metav1.Time `json:"healthLastUpdateTime,omitempty"`
+	// Deployments are the statuses of the Serve deployments of the application, keyed by deployment name.
+	// They are only set in the applicationStatuses of the Serve applications API.
+	// +optional
+	Deployments map[string]ServeDeploymentStatus `json:"serveDeploymentStatuses,omitempty"`
 }
 
 // ServeDeploymentStatus defines the current state of a Serve deployment
@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RayWorkerGroupSpec defines the desired state of RayWorkerGroup
+type RayWorkerGroupSpec struct {
+	// RayClusterName is the name of the RayCluster of the worker group, in the namespace of the RayWorkerGroup.
+	RayClusterName string `json:"rayClusterName"`
+	// GroupName is the name of the worker group in the RayCluster.
+	GroupName string `json:"groupName"`
+	// Replicas is the desired number of workers of the group, written to the replicas of the worker group after
+	// being clamped by its minReplicas and maxReplicas. Defaults to the replicas of the worker group.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// RayWorkerGroupStatus defines the observed state of RayWorkerGroup
+type RayWorkerGroupStatus struct {
+	// Replicas is the number of worker Pods of the group.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// DesiredReplicas is the number of workers of the group, after the replicas of the spec were clamped by the
+	// minReplicas and maxReplicas of the worker group.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// Selector is the label selector of the worker Pods of the group, used by the HorizontalPodAutoscaler.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+	// ObservedGeneration is the most recent generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Reason is why the worker group isn't scaled, e.g. AutoscalerEnabled.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable message about Reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+//+kubebuilder:printcolumn:name="cluster",type=string,JSONPath=".spec.rayClusterName",priority=0
+//+kubebuilder:printcolumn:name="group",type=string,JSONPath=".spec.groupName",priority=0
+//+kubebuilder:printcolumn:name="desired",type=integer,JSONPath=".status.desiredReplicas",priority=0
+//+kubebuilder:printcolumn:name="replicas",type=integer,JSONPath=".status.replicas",priority=0
+//+kubebuilder:printcolumn:name="age",type=date,JSONPath=".metadata.creationTimestamp",priority=0
+//+genclient
+//+genclient:method=GetScale,verb=get,subresource=scale,result=k8s.io/api/autoscaling/v1.Scale
+//+genclient:method=UpdateScale,verb=update,subresource=scale,input=k8s.io/api/autoscaling/v1.Scale,result=k8s.io/api/autoscaling/v1.Scale
+// RayWorkerGroup is the Schema for the rayworkergroups API. It exposes the replicas of a worker group of a
+// RayCluster through the scale subresource, so that kubectl scale and the HorizontalPodAutoscaler can drive them.
+type RayWorkerGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RayWorkerGroupSpec   `json:"spec,omitempty"`
+	Status RayWorkerGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RayWorkerGroupList contains a list of RayWorkerGroup
+type RayWorkerGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RayWorkerGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RayWorkerGroup{}, &RayWorkerGroupList{})
+}
@@ -21,6 +21,13 @@ func (in *AppStatus) DeepCopyInto(out *AppStatus) {
 		in, out := &in.HealthLastUpdateTime, &out.HealthLastUpdateTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make(map[string]ServeDeploymentStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppStatus.
@@ -33,6 +40,40 @@ func (in *AppStatus) DeepCopy() *AppStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationProbeStatus) DeepCopyInto(out *ApplicationProbeStatus) {
+	*out = *in
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationProbeStatus.
+func (in *ApplicationProbeStatus) DeepCopy() *ApplicationProbeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationProbeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollbackSpec) DeepCopyInto(out *AutoRollbackSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollbackSpec.
+func (in *AutoRollbackSpec) DeepCopy() *AutoRollbackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollbackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalerOptions) DeepCopyInto(out *AutoscalerOptions) {
 	*out = *in
@@ -92,6 +133,55 @@ func (in *AutoscalerOptions) DeepCopy() *AutoscalerOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BatchSchedulingStatus) DeepCopyInto(out *BatchSchedulingStatus) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchSchedulingStatus.
+func (in *BatchSchedulingStatus) DeepCopy() *BatchSchedulingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BatchSchedulingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapLogsSink) DeepCopyInto(out *ConfigMapLogsSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapLogsSink.
+func (in *ConfigMapLogsSink) DeepCopy() *ConfigMapLogsSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapLogsSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapWorkingDirSource) DeepCopyInto(out *ConfigMapWorkingDirSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapWorkingDirSource.
+func (in *ConfigMapWorkingDirSource) DeepCopy() *ConfigMapWorkingDirSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapWorkingDirSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashboardStatus) DeepCopyInto(out *DashboardStatus) {
 	*out = *in
@@ -115,9 +205,136 @@ func (in *DashboardStatus) DeepCopy() *DashboardStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionPolicy) DeepCopyInto(out *DeletionPolicy) {
+	*out = *in
+	in.OnSuccess.DeepCopyInto(&out.OnSuccess)
+	in.OnFailure.DeepCopyInto(&out.OnFailure)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionPolicy.
+func (in *DeletionPolicy) DeepCopy() *DeletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionRule) DeepCopyInto(out *DeletionRule) {
+	*out = *in
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionRule.
+func (in *DeletionRule) DeepCopy() *DeletionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverLogsSink) DeepCopyInto(out *DriverLogsSink) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapLogsSink)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PVCLogsSink)
+		**out = **in
+	}
+	if in.ObjectStore != nil {
+		in, out := &in.ObjectStore, &out.ObjectStore
+		*out = new(ObjectStoreLogsSink)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverLogsSink.
+func (in *DriverLogsSink) DeepCopy() *DriverLogsSink {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverLogsSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverLogsSpec) DeepCopyInto(out *DriverLogsSpec) {
+	*out = *in
+	if in.TailLines != nil {
+		in, out := &in.TailLines, &out.TailLines
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Sink != nil {
+		in, out := &in.Sink, &out.Sink
+		*out = new(DriverLogsSink)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverLogsSpec.
+func (in *DriverLogsSpec) DeepCopy() *DriverLogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverLogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriverLogsStatus) DeepCopyInto(out *DriverLogsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriverLogsStatus.
+func (in *DriverLogsStatus) DeepCopy() *DriverLogsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriverLogsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HeadGroupSpec) DeepCopyInto(out *HeadGroupSpec) {
 	*out = *in
+	if in.HeadService != nil {
+		in, out := &in.HeadService, &out.HeadService
+		*out = new(v1.Service)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EnableIngress != nil {
 		in, out := &in.EnableIngress, &out.EnableIngress
 		*out = new(bool)
@@ -163,6 +380,81 @@ func (in *HeadInfo) DeepCopy() *HeadInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSpec) DeepCopyInto(out *NotificationSpec) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.SigningSecret != nil {
+		in, out := &in.SigningSecret, &out.SigningSecret
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSpec.
+func (in *NotificationSpec) DeepCopy() *NotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreLogsSink) DeepCopyInto(out *ObjectStoreLogsSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreLogsSink.
+func (in *ObjectStoreLogsSink) DeepCopy() *ObjectStoreLogsSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreLogsSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCLogsSink) DeepCopyInto(out *PVCLogsSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCLogsSink.
+func (in *PVCLogsSink) DeepCopy() *PVCLogsSink {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCLogsSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCWorkingDirSource) DeepCopyInto(out *PVCWorkingDirSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCWorkingDirSource.
+func (in *PVCWorkingDirSource) DeepCopy() *PVCWorkingDirSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCWorkingDirSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RayActorOptionSpec) DeepCopyInto(out *RayActorOptionSpec) {
 	*out = *in
@@ -312,6 +604,11 @@ func (in *RayClusterStatus) DeepCopyInto(out *RayClusterStatus) {
 		}
 	}
 	out.Head = in.Head
+	if in.BatchScheduling != nil {
+		in, out := &in.BatchScheduling, &out.BatchScheduling
+		*out = new(BatchSchedulingStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayClusterStatus.
@@ -325,7 +622,7 @@ func (in *RayClusterStatus) DeepCopy() *RayClusterStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RayJob) DeepCopyInto(out *RayJob) {
+func (in *RayCronJob) DeepCopyInto(out *RayCronJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -333,18 +630,18 @@ func (in *RayJob) DeepCopyInto(out *RayJob) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJob.
-func (in *RayJob) DeepCopy() *RayJob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayCronJob.
+func (in *RayCronJob) DeepCopy() *RayCronJob {
 	if in == nil {
 		return nil
 	}
-	out := new(RayJob)
+	out := new(RayCronJob)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RayJob) DeepCopyObject() runtime.Object {
+func (in *RayCronJob) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -352,31 +649,31 @@ func (in *RayJob) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RayJobList) DeepCopyInto(out *RayJobList) {
+func (in *RayCronJobList) DeepCopyInto(out *RayCronJobList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RayJob, len(*in))
+		*out = make([]RayCronJob, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobList.
-func (in *RayJobList) DeepCopy() *RayJobList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayCronJobList.
+func (in *RayCronJobList) DeepCopy() *RayCronJobList {
 	if in == nil {
 		return nil
 	}
-	out := new(RayJobList)
+	out := new(RayCronJobList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RayJobList) DeepCopyObject() runtime.Object {
+func (in *RayCronJobList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -384,70 +681,71 @@ func (in *RayJobList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RayJobSpec) DeepCopyInto(out *RayJobSpec) {
+func (in *RayCronJobSpec) DeepCopyInto(out *RayCronJobSpec) {
 	*out = *in
-	if in.Metadata != nil {
-		in, out := &in.Metadata, &out.Metadata
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
 	}
-	if in.TTLSecondsAfterFinished != nil {
-		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
-		*out = new(int32)
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
 		**out = **in
 	}
-	if in.RayClusterSpec != nil {
-		in, out := &in.RayClusterSpec, &out.RayClusterSpec
-		*out = new(RayClusterSpec)
-		(*in).DeepCopyInto(*out)
+	if in.SuccessfulJobsHistoryLimit != nil {
+		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
 	}
-	if in.ClusterSelector != nil {
-		in, out := &in.ClusterSelector, &out.ClusterSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
 	}
+	in.JobTemplate.DeepCopyInto(&out.JobTemplate)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobSpec.
-func (in *RayJobSpec) DeepCopy() *RayJobSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayCronJobSpec.
+func (in *RayCronJobSpec) DeepCopy() *RayCronJobSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RayJobSpec)
+	out := new(RayCronJobSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RayJobStatus) DeepCopyInto(out *RayJobStatus) {
+func (in *RayCronJobStatus) DeepCopyInto(out *RayCronJobStatus) {
 	*out = *in
-	if in.StartTime != nil {
-		in, out := &in.StartTime, &out.StartTime
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
 		*out = (*in).DeepCopy()
 	}
-	if in.EndTime != nil {
-		in, out := &in.EndTime, &out.EndTime
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
 		*out = (*in).DeepCopy()
 	}
-	in.RayClusterStatus.DeepCopyInto(&out.RayClusterStatus)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobStatus.
-func (in *RayJobStatus) DeepCopy() *RayJobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayCronJobStatus.
+func (in *RayCronJobStatus) DeepCopy() *RayCronJobStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RayJobStatus)
+	out := new(RayCronJobStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RayService) DeepCopyInto(out *RayService) {
+func (in *RayJob) DeepCopyInto(out *RayJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -455,8 +753,275 @@ func (in *RayService) DeepCopyInto(out *RayService) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayService.
-func (in *RayService) DeepCopy() *RayService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJob.
+func (in *RayJob) DeepCopy() *RayJob {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RayJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobAttempt) DeepCopyInto(out *RayJobAttempt) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobAttempt.
+func (in *RayJobAttempt) DeepCopy() *RayJobAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobDetails) DeepCopyInto(out *RayJobDetails) {
+	*out = *in
+	if in.DriverExitCode != nil {
+		in, out := &in.DriverExitCode, &out.DriverExitCode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobDetails.
+func (in *RayJobDetails) DeepCopy() *RayJobDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobList) DeepCopyInto(out *RayJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RayJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobList.
+func (in *RayJobList) DeepCopy() *RayJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RayJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobSpec) DeepCopyInto(out *RayJobSpec) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WorkingDirFrom != nil {
+		in, out := &in.WorkingDirFrom, &out.WorkingDirFrom
+		*out = new(WorkingDirSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EntrypointNumCpus != nil {
+		in, out := &in.EntrypointNumCpus, &out.EntrypointNumCpus
+		*out = new(float64)
+		**out = **in
+	}
+	if in.EntrypointNumGpus != nil {
+		in, out := &in.EntrypointNumGpus, &out.EntrypointNumGpus
+		*out = new(float64)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DashboardUnreachableSecondThreshold != nil {
+		in, out := &in.DashboardUnreachableSecondThreshold, &out.DashboardUnreachableSecondThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(DeletionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RayClusterSpec != nil {
+		in, out := &in.RayClusterSpec, &out.RayClusterSpec
+		*out = new(RayClusterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SubmitterPodTemplate != nil {
+		in, out := &in.SubmitterPodTemplate, &out.SubmitterPodTemplate
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriverLogs != nil {
+		in, out := &in.DriverLogs, &out.DriverLogs
+		*out = new(DriverLogsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartupTimeoutSeconds != nil {
+		in, out := &in.StartupTimeoutSeconds, &out.StartupTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobSpec.
+func (in *RayJobSpec) DeepCopy() *RayJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobStatus) DeepCopyInto(out *RayJobStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	in.RayClusterStatus.DeepCopyInto(&out.RayClusterStatus)
+	if in.DriverLogs != nil {
+		in, out := &in.DriverLogs, &out.DriverLogs
+		*out = new(DriverLogsStatus)
+		**out = **in
+	}
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = make([]RayJobAttempt, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AttemptStartTime != nil {
+		in, out := &in.AttemptStartTime, &out.AttemptStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.JobDetails != nil {
+		in, out := &in.JobDetails, &out.JobDetails
+		*out = new(RayJobDetails)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UntrackedJobs != nil {
+		in, out := &in.UntrackedJobs, &out.UntrackedJobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DashboardUnreachableSince != nil {
+		in, out := &in.DashboardUnreachableSince, &out.DashboardUnreachableSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobStatus.
+func (in *RayJobStatus) DeepCopy() *RayJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayJobTemplateSpec) DeepCopyInto(out *RayJobTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayJobTemplateSpec.
+func (in *RayJobTemplateSpec) DeepCopy() *RayJobTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RayJobTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayService) DeepCopyInto(out *RayService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayService.
+func (in *RayService) DeepCopy() *RayService {
 	if in == nil {
 		return nil
 	}
@@ -520,6 +1085,60 @@ func (in *RayServiceSpec) DeepCopyInto(out *RayServiceSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ServeApplications != nil {
+		in, out := &in.ServeApplications, &out.ServeApplications
+		*out = make([]ServeApplicationSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(RayServiceUpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(AutoRollbackSpec)
+		**out = **in
+	}
+	if in.PendingClusterTimeoutSeconds != nil {
+		in, out := &in.PendingClusterTimeoutSeconds, &out.PendingClusterTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ServeProxyHealthCheck != nil {
+		in, out := &in.ServeProxyHealthCheck, &out.ServeProxyHealthCheck
+		*out = new(ServeProxyHealthCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationProbes != nil {
+		in, out := &in.ApplicationProbes, &out.ApplicationProbes
+		*out = make([]ServeApplicationProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServeGRPC != nil {
+		in, out := &in.ServeGRPC, &out.ServeGRPC
+		*out = new(ServeGRPCSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServeIngress != nil {
+		in, out := &in.ServeIngress, &out.ServeIngress
+		*out = new(ServeIngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayServiceSpec.
@@ -545,6 +1164,25 @@ func (in *RayServiceStatus) DeepCopyInto(out *RayServiceStatus) {
 	}
 	in.DashboardStatus.DeepCopyInto(&out.DashboardStatus)
 	in.RayClusterStatus.DeepCopyInto(&out.RayClusterStatus)
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make(map[string]AppStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TrafficWeight != nil {
+		in, out := &in.TrafficWeight, &out.TrafficWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ApplicationProbes != nil {
+		in, out := &in.ApplicationProbes, &out.ApplicationProbes
+		*out = make(map[string]ApplicationProbeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayServiceStatus.
@@ -562,6 +1200,18 @@ func (in *RayServiceStatuses) DeepCopyInto(out *RayServiceStatuses) {
 	*out = *in
 	in.ActiveServiceStatus.DeepCopyInto(&out.ActiveServiceStatus)
 	in.PendingServiceStatus.DeepCopyInto(&out.PendingServiceStatus)
+	if in.LastTrafficShiftTime != nil {
+		in, out := &in.LastTrafficShiftTime, &out.LastTrafficShiftTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdateRevisionTime != nil {
+		in, out := &in.UpdateRevisionTime, &out.UpdateRevisionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingClusterStartTime != nil {
+		in, out := &in.PendingClusterStartTime, &out.PendingClusterStartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayServiceStatuses.
@@ -574,6 +1224,120 @@ func (in *RayServiceStatuses) DeepCopy() *RayServiceStatuses {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayServiceUpgradeStrategy) DeepCopyInto(out *RayServiceUpgradeStrategy) {
+	*out = *in
+	if in.TrafficShifting != nil {
+		in, out := &in.TrafficShifting, &out.TrafficShifting
+		*out = new(TrafficShiftingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayServiceUpgradeStrategy.
+func (in *RayServiceUpgradeStrategy) DeepCopy() *RayServiceUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RayServiceUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayWorkerGroup) DeepCopyInto(out *RayWorkerGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayWorkerGroup.
+func (in *RayWorkerGroup) DeepCopy() *RayWorkerGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(RayWorkerGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RayWorkerGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayWorkerGroupList) DeepCopyInto(out *RayWorkerGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RayWorkerGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayWorkerGroupList.
+func (in *RayWorkerGroupList) DeepCopy() *RayWorkerGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(RayWorkerGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RayWorkerGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayWorkerGroupSpec) DeepCopyInto(out *RayWorkerGroupSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayWorkerGroupSpec.
+func (in *RayWorkerGroupSpec) DeepCopy() *RayWorkerGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RayWorkerGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RayWorkerGroupStatus) DeepCopyInto(out *RayWorkerGroupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RayWorkerGroupStatus.
+func (in *RayWorkerGroupStatus) DeepCopy() *RayWorkerGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RayWorkerGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleStrategy) DeepCopyInto(out *ScaleStrategy) {
 	*out = *in
@@ -594,6 +1358,63 @@ func (in *ScaleStrategy) DeepCopy() *ScaleStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeApplicationProbe) DeepCopyInto(out *ServeApplicationProbe) {
+	*out = *in
+	if in.ExpectedStatus != nil {
+		in, out := &in.ExpectedStatus, &out.ExpectedStatus
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeApplicationProbe.
+func (in *ServeApplicationProbe) DeepCopy() *ServeApplicationProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeApplicationProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeApplicationSpec) DeepCopyInto(out *ServeApplicationSpec) {
+	*out = *in
+	if in.ServeConfigSpecs != nil {
+		in, out := &in.ServeConfigSpecs, &out.ServeConfigSpecs
+		*out = make([]ServeConfigSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeApplicationSpec.
+func (in *ServeApplicationSpec) DeepCopy() *ServeApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServeConfigSpec) DeepCopyInto(out *ServeConfigSpec) {
 	*out = *in
@@ -685,6 +1506,180 @@ func (in *ServeDeploymentStatus) DeepCopy() *ServeDeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeGRPCRouteSpec) DeepCopyInto(out *ServeGRPCRouteSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeGRPCRouteSpec.
+func (in *ServeGRPCRouteSpec) DeepCopy() *ServeGRPCRouteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeGRPCRouteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeGRPCSpec) DeepCopyInto(out *ServeGRPCSpec) {
+	*out = *in
+	if in.ServicerFunctions != nil {
+		in, out := &in.ServicerFunctions, &out.ServicerFunctions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(ServeGRPCRouteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeGRPCSpec.
+func (in *ServeGRPCSpec) DeepCopy() *ServeGRPCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeGRPCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeIngressSpec) DeepCopyInto(out *ServeIngressSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = make([]ServeIngressTLS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeIngressSpec.
+func (in *ServeIngressSpec) DeepCopy() *ServeIngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeIngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeIngressTLS) DeepCopyInto(out *ServeIngressTLS) {
+	*out = *in
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeIngressTLS.
+func (in *ServeIngressTLS) DeepCopy() *ServeIngressTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeIngressTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServeProxyHealthCheckSpec) DeepCopyInto(out *ServeProxyHealthCheckSpec) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServeProxyHealthCheckSpec.
+func (in *ServeProxyHealthCheckSpec) DeepCopy() *ServeProxyHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServeProxyHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficShiftingSpec) DeepCopyInto(out *TrafficShiftingSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.StepIntervalSeconds != nil {
+		in, out := &in.StepIntervalSeconds, &out.StepIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficShiftingSpec.
+func (in *TrafficShiftingSpec) DeepCopy() *TrafficShiftingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficShiftingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkerGroupSpec) DeepCopyInto(out *WorkerGroupSpec) {
 	*out = *in
@@ -723,3 +1718,28 @@ func (in *WorkerGroupSpec) DeepCopy() *WorkerGroupSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkingDirSource) DeepCopyInto(out *WorkingDirSource) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapWorkingDirSource)
+		**out = **in
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(PVCWorkingDirSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkingDirSource.
+func (in *WorkingDirSource) DeepCopy() *WorkingDirSource {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkingDirSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -21,6 +21,14 @@ type BatchScheduler interface {
 	// AddMetadataToPod enriches Pod specs with metadata necessary to tie them to the scheduler.
 	// For example, setting labels for queues / priority, and setting schedulerName.
 	AddMetadataToPod(app *rayiov1alpha1.RayCluster, pod *v1.Pod)
+
+	// CleanupOnDeletion releases the scheduler-specific resources of the RayCluster when it is deleted.
+	// For most batch schedulers, this results in the deletion of the PodGroup.
+	CleanupOnDeletion(app *rayiov1alpha1.RayCluster) error
+
+	// GetSchedulingStatus reports the state of the RayCluster's gang in the batch scheduler.
+	// A nil status means the scheduler does not gate pod creation.
+	GetSchedulingStatus(app *rayiov1alpha1.RayCluster) (*rayiov1alpha1.BatchSchedulingStatus, error)
 }
 
 // BatchSchedulerFactory handles initial setup of the scheduler plugin by registering the
@@ -56,6 +64,14 @@ func (d *DefaultBatchScheduler) DoBatchSchedulingOnSubmission(app *rayiov1alpha1
 func (d *DefaultBatchScheduler) AddMetadataToPod(app *rayiov1alpha1.RayCluster, pod *v1.Pod) {
 }
 
+func (d *DefaultBatchScheduler) CleanupOnDeletion(app *rayiov1alpha1.RayCluster) error {
+	return nil
+}
+
+func (d *DefaultBatchScheduler) GetSchedulingStatus(app *rayiov1alpha1.RayCluster) (*rayiov1alpha1.BatchSchedulingStatus, error) {
+	return nil, nil
+}
+
 func (df *DefaultBatchSchedulerFactory) New(config *rest.Config) (BatchScheduler, error) {
 	return &DefaultBatchScheduler{}, nil
 }
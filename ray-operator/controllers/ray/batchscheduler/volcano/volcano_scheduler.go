@@ -148,6 +148,62 @@ func (v *VolcanoBatchScheduler) AddMetadataToPod(app *rayiov1alpha1.RayCluster,
 	pod.Spec.SchedulerName = v.Name()
 }
 
+func (v *VolcanoBatchScheduler) CleanupOnDeletion(app *rayiov1alpha1.RayCluster) error {
+	podGroupName := v.getAppPodGroupName(app)
+	if err := v.volcanoClient.SchedulingV1beta1().PodGroups(app.Namespace).Delete(
+		context.TODO(), podGroupName, metav1.DeleteOptions{},
+	); err != nil && !errors.IsNotFound(err) {
+		v.log.Error(err, "Pod group DELETE error!", "podGroup", podGroupName)
+		return err
+	}
+	return nil
+}
+
+func (v *VolcanoBatchScheduler) GetSchedulingStatus(app *rayiov1alpha1.RayCluster) (*rayiov1alpha1.BatchSchedulingStatus, error) {
+	podGroupName := v.getAppPodGroupName(app)
+	pg, err := v.volcanoClient.SchedulingV1beta1().PodGroups(app.Namespace).Get(context.TODO(), podGroupName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return podGroupSchedulingStatus(pg), nil
+}
+
+// podGroupSchedulingStatus maps the phase and conditions of a PodGroup to a BatchSchedulingStatus.
+// A PodGroup that is still Pending has not been admitted by its queue; if Volcano also reports it
+// as unschedulable (for example, because the queue is full), the gang is Unschedulable.
+func podGroupSchedulingStatus(pg *v1beta1.PodGroup) *rayiov1alpha1.BatchSchedulingStatus {
+	status := &rayiov1alpha1.BatchSchedulingStatus{
+		SchedulerName: GetPluginName(),
+	}
+
+	var unschedulable *v1beta1.PodGroupCondition
+	for i := range pg.Status.Conditions {
+		cond := &pg.Status.Conditions[i]
+		if cond.Type == v1beta1.PodGroupUnschedulableType && cond.Status == corev1.ConditionTrue {
+			unschedulable = cond
+		}
+	}
+	if unschedulable != nil {
+		status.Reason = unschedulable.Reason
+		status.Message = unschedulable.Message
+	}
+
+	switch pg.Status.Phase {
+	case v1beta1.PodGroupPending, "":
+		status.Phase = rayiov1alpha1.BatchSchedulingPending
+		if unschedulable != nil {
+			status.Phase = rayiov1alpha1.BatchSchedulingUnschedulable
+		}
+	case v1beta1.PodGroupInqueue:
+		status.Phase = rayiov1alpha1.BatchSchedulingInqueue
+	case v1beta1.PodGroupRunning:
+		status.Phase = rayiov1alpha1.BatchSchedulingRunning
+	default:
+		status.Phase = rayiov1alpha1.BatchSchedulingUnknown
+	}
+	return status
+}
+
 func (vf *VolcanoBatchSchedulerFactory) New(config *rest.Config) (schedulerinterface.BatchScheduler, error) {
 	vkClient, err := volcanoclient.NewForConfig(config)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
+	"volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
 func TestCreatePodGroup(t *testing.T) {
@@ -95,3 +96,54 @@ func TestCreatePodGroup(t *testing.T) {
 	// 2 GPUs total
 	a.Equal("2", pg.Spec.MinResources.Name("nvidia.com/gpu", resource.BinarySI).String())
 }
+
+func TestPodGroupSchedulingStatus(t *testing.T) {
+	a := assert.New(t)
+
+	unschedulable := v1beta1.PodGroupCondition{
+		Type:    v1beta1.PodGroupUnschedulableType,
+		Status:  corev1.ConditionTrue,
+		Reason:  v1beta1.NotEnoughResourcesReason,
+		Message: "queue resource quota insufficient",
+	}
+
+	tests := map[string]struct {
+		phase      v1beta1.PodGroupPhase
+		conditions []v1beta1.PodGroupCondition
+		expected   rayiov1alpha1.BatchSchedulingPhase
+		reason     string
+	}{
+		"pending": {
+			phase:    v1beta1.PodGroupPending,
+			expected: rayiov1alpha1.BatchSchedulingPending,
+		},
+		"pending and unschedulable": {
+			phase:      v1beta1.PodGroupPending,
+			conditions: []v1beta1.PodGroupCondition{unschedulable},
+			expected:   rayiov1alpha1.BatchSchedulingUnschedulable,
+			reason:     v1beta1.NotEnoughResourcesReason,
+		},
+		"inqueue with unschedulable pods is still admitted": {
+			phase:      v1beta1.PodGroupInqueue,
+			conditions: []v1beta1.PodGroupCondition{unschedulable},
+			expected:   rayiov1alpha1.BatchSchedulingInqueue,
+			reason:     v1beta1.NotEnoughResourcesReason,
+		},
+		"running": {
+			phase:    v1beta1.PodGroupRunning,
+			expected: rayiov1alpha1.BatchSchedulingRunning,
+		},
+		"unknown": {
+			phase:    v1beta1.PodGroupUnknown,
+			expected: rayiov1alpha1.BatchSchedulingUnknown,
+		},
+	}
+
+	for name, tc := range tests {
+		pg := &v1beta1.PodGroup{Status: v1beta1.PodGroupStatus{Phase: tc.phase, Conditions: tc.conditions}}
+		status := podGroupSchedulingStatus(pg)
+		a.Equal(GetPluginName(), status.SchedulerName, name)
+		a.Equal(tc.expected, status.Phase, name)
+		a.Equal(tc.reason, status.Reason, name)
+	}
+}
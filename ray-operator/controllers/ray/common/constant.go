@@ -11,6 +11,9 @@ const (
 	RayClusterDashboardServiceLabelKey = "ray.io/cluster-dashboard"
 	RayClusterServingServiceLabelKey   = "ray.io/serve"
 	RayServiceClusterHashKey           = "ray.io/cluster-hash"
+	RayServiceServeConfigHashKey       = "ray.io/serve-config-hash"
+	RayJobSubmitterLabelKey            = "ray.io/job-submitter"
+	RayCronJobLabelKey                 = "ray.io/cronjob"
 
 	// Batch scheduling labels
 	// TODO(tgaddair): consider making these part of the CRD
@@ -22,6 +25,19 @@ const (
 	RayExternalStorageNSAnnotationKey = "ray.io/external-storage-namespace"
 	RayNodeHealthStateAnnotationKey   = "ray.io/health-state"
 
+	// The maximum number of RayJobs running at the same time on a RayCluster shared through clusterSelector
+	RayClusterMaxConcurrentJobsAnnotationKey = "ray.io/max-concurrent-jobs"
+
+	// The time a RayJob created by a RayCronJob was scheduled at
+	RayCronJobScheduledTimeAnnotationKey = "ray.io/cronjob-scheduled-time"
+
+	// The time a dangling RayCluster of a RayService is deleted at
+	RayClusterDeletionTimestampAnnotationKey = "ray.io/scheduled-deletion-time"
+
+	// The revision a RayService is requested to roll back to, and the outcome of a RayService revision
+	RayServiceRollbackAnnotationKey        = "ray.io/rollback-to"
+	RayServiceRevisionOutcomeAnnotationKey = "ray.io/revision-outcome"
+
 	// Pod health state values
 	PodUnhealthy = "Unhealthy"
 
@@ -47,6 +63,7 @@ const (
 	DefaultMetricsPort              = 8080
 	DefaultDashboardAgentListenPort = 52365
 	DefaultServingPort              = 8000
+	DefaultServingGRPCPort          = 9000
 
 	DefaultClientPortName               = "client"
 	DefaultRedisPortName                = "redis"
@@ -54,9 +71,12 @@ const (
 	DefaultMetricsName                  = "metrics"
 	DefaultDashboardAgentListenPortName = "dashboard-agent"
 	DefaultServingPortName              = "serve"
+	DefaultServingGRPCPortName          = "serve-grpc"
 
 	// The default AppProtocol for Kubernetes service
 	DefaultServiceAppProtocol = "tcp"
+	// The AppProtocol of the gRPC port of the serve service
+	GRPCServiceAppProtocol = "grpc"
 
 	// The default application name
 	ApplicationName = "kuberay"
@@ -87,6 +107,8 @@ const (
 	RAY_USAGE_STATS_KUBERAY_IN_USE          = "RAY_USAGE_STATS_KUBERAY_IN_USE"
 	RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV  = "RAYCLUSTER_DEFAULT_REQUEUE_SECONDS_ENV"
 	RAYCLUSTER_DEFAULT_REQUEUE_SECONDS      = 300
+	RAY_DASHBOARD_ADDRESS                   = "RAY_DASHBOARD_ADDRESS"
+	RAY_JOB_SUBMISSION_ID                   = "RAY_JOB_SUBMISSION_ID"
 
 	// Ray core default configurations
 	DefaultRedisPassword                 = "5241590000000000"
@@ -116,12 +138,49 @@ const (
 
 	// Finalizers for RayJob
 	RayJobStopJobFinalizer = "ray.io/rayjob-finalizer"
+
+	// Finalizer for RayCluster managed by a batch scheduler
+	RayClusterBatchSchedulerFinalizer = "ray.io/batch-scheduler-finalizer"
+
+	// Ray job submitter (K8sJobMode) defaults
+	SubmitterContainerName       = "ray-job-submitter"
+	DefaultSubmitterBackoffLimit = 2
+
+	// Volume of the working directory of a RayJob with workingDirFrom
+	RayJobWorkingDirVolumeName = "rayjob-working-dir"
+
+	// RayCronJob defaults, the same as for Kubernetes CronJobs
+	DefaultSuccessfulJobsHistoryLimit = 3
+	DefaultFailedJobsHistoryLimit     = 1
+
+	// RayService TrafficShifting upgrade defaults
+	DefaultTrafficShiftingStepIntervalSeconds = 60
+
+	// The number of revisions kept by a RayService, the same as for Kubernetes Deployments
+	DefaultRevisionHistoryLimit = 10
+
+	// Serve HTTP proxy health check defaults, the same as for the probes of the kubelet
+	DefaultServeProxyHealthCheckTimeoutSeconds   = 1
+	DefaultServeProxyHealthCheckSuccessThreshold = 1
+	DefaultServeProxyHealthCheckFailureThreshold = 3
+
+	// RayService application probe defaults
+	DefaultApplicationProbePeriodSeconds    = 10
+	DefaultApplicationProbeTimeoutSeconds   = 1
+	DefaultApplicationProbeFailureThreshold = 3
 )
 
+// DefaultTrafficShiftingSteps are the percentages of the traffic routed to the pending RayCluster of a RayService
+// when the TrafficShifting upgrade sets no steps.
+var DefaultTrafficShiftingSteps = []int32{10, 50}
+
 type ServiceType string
 
 const (
 	HeadService    ServiceType = "headService"
 	AgentService   ServiceType = "agentService"
 	ServingService ServiceType = "serveService"
+	// ClusterServingService is the serve service of a single RayCluster of a RayService, used by the traffic
+	// router of a TrafficShifting upgrade.
+	ClusterServingService ServiceType = "clusterServeService"
 )
@@ -11,7 +11,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+const (
+	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+	// Annotations of the NGINX ingress controller that route a weighted share of the traffic to a canary Ingress.
+	NginxCanaryAnnotationKey       = "nginx.ingress.kubernetes.io/canary"
+	NginxCanaryWeightAnnotationKey = "nginx.ingress.kubernetes.io/canary-weight"
+	// Annotation of the NGINX ingress controller that proxies the traffic to the backend with gRPC.
+	NginxBackendProtocolAnnotationKey = "nginx.ingress.kubernetes.io/backend-protocol"
+)
 
 // BuildIngressForHeadService Builds the ingress for head service dashboard.
 // This is used to expose dashboard for external traffic.
@@ -109,3 +117,167 @@ func BuildIngressForRayService(service rayiov1alpha1.RayService, cluster rayiov1
 
 	return ingress, nil
 }
+
+// BuildServeIngressForRayService builds an Ingress routing the traffic of a RayService with a TrafficShifting upgrade
+// to the serve service of the cluster. The canary Ingress receives weight percent of the traffic of the main one.
+func BuildServeIngressForRayService(service rayiov1alpha1.RayService, cluster rayiov1alpha1.RayCluster, canary bool, weight int32) (*networkingv1.Ingress, error) {
+	ingress := buildServeIngress(service, utils.GenerateServeIngressName(service.Name, canary), utils.GenerateServeServiceName(cluster.Name), getServePort(cluster))
+	ingress.Labels[RayClusterLabelKey] = cluster.Name
+
+	trafficShifting := GetTrafficShiftingSpec(service)
+	if trafficShifting.IngressClassName != nil {
+		ingress.Spec.IngressClassName = trafficShifting.IngressClassName
+	}
+	if trafficShifting.Host != "" {
+		ingress.Spec.Rules[0].Host = trafficShifting.Host
+	}
+	if canary {
+		ingress.Annotations[NginxCanaryAnnotationKey] = "true"
+		ingress.Annotations[NginxCanaryWeightAnnotationKey] = fmt.Sprint(weight)
+	}
+
+	return ingress, nil
+}
+
+// BuildIngressForServeService builds the Ingress of the serveIngress of a RayService. It routes the traffic to the
+// serve service of the RayService, which follows the active cluster.
+func BuildIngressForServeService(service rayiov1alpha1.RayService, cluster rayiov1alpha1.RayCluster) (*networkingv1.Ingress, error) {
+	if service.Spec.ServeIngress == nil {
+		return nil, fmt.Errorf("RayService %s/%s has no serveIngress", service.Namespace, service.Name)
+	}
+	return buildServeIngress(service, utils.GenerateServeIngressName(service.Name, false), utils.GenerateServeServiceName(service.Name), getServePort(cluster)), nil
+}
+
+// buildServeIngress builds an Ingress routing the serveIngress paths of the RayService to the port of a serve service.
+func buildServeIngress(service rayiov1alpha1.RayService, name string, serviceName string, port int32) *networkingv1.Ingress {
+	serveIngress := rayiov1alpha1.ServeIngressSpec{}
+	if service.Spec.ServeIngress != nil {
+		serveIngress = *service.Spec.ServeIngress.DeepCopy()
+	}
+
+	annotations := map[string]string{}
+	for key, value := range serveIngress.Annotations {
+		annotations[key] = value
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	var paths []networkingv1.HTTPIngressPath
+	for _, path := range getServeIngressPaths(service) {
+		paths = append(paths, networkingv1.HTTPIngressPath{
+			Path:     path,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: serviceName,
+					Port: networkingv1.ServiceBackendPort{
+						Number: port,
+					},
+				},
+			},
+		})
+	}
+
+	var tls []networkingv1.IngressTLS
+	for _, certificate := range serveIngress.TLS {
+		hosts := certificate.Hosts
+		if len(hosts) == 0 && serveIngress.Host != "" {
+			hosts = []string{serveIngress.Host}
+		}
+		tls = append(tls, networkingv1.IngressTLS{Hosts: hosts, SecretName: certificate.SecretName})
+	}
+
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: service.Namespace,
+			Labels: map[string]string{
+				RayServiceLabelKey: service.Name,
+			},
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: serveIngress.IngressClassName,
+			TLS:              tls,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: serveIngress.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: paths,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getServeIngressPaths returns the path prefixes routed to the serve service: the paths of the serveIngress, or the
+// route prefixes of the Serve applications, or /.
+func getServeIngressPaths(service rayiov1alpha1.RayService) []string {
+	if service.Spec.ServeIngress != nil && len(service.Spec.ServeIngress.Paths) > 0 {
+		return service.Spec.ServeIngress.Paths
+	}
+	var paths []string
+	for _, app := range service.Spec.ServeApplications {
+		// An application without a route prefix is served on /, which covers all the others.
+		if app.RoutePrefix == "" || app.RoutePrefix == "/" {
+			return []string{"/"}
+		}
+		paths = append(paths, app.RoutePrefix)
+	}
+	if len(paths) == 0 {
+		return []string{"/"}
+	}
+	return paths
+}
+
+// BuildServeGRPCIngressForRayService builds an Ingress routing gRPC traffic to the gRPC port of the serve service of
+// the RayService, which follows the active cluster.
+func BuildServeGRPCIngressForRayService(service rayiov1alpha1.RayService, cluster rayiov1alpha1.RayCluster) (*networkingv1.Ingress, error) {
+	if service.Spec.ServeGRPC == nil || service.Spec.ServeGRPC.Route == nil {
+		return nil, fmt.Errorf("RayService %s/%s has no serveGRPC route", service.Namespace, service.Name)
+	}
+	route := service.Spec.ServeGRPC.Route
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.GenerateServeGRPCIngressName(service.Name),
+			Namespace: service.Namespace,
+			Labels: map[string]string{
+				RayServiceLabelKey: service.Name,
+			},
+			Annotations: map[string]string{
+				NginxBackendProtocolAnnotationKey: "GRPC",
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: route.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: route.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: utils.GenerateServeServiceName(service.Name),
+											Port: networkingv1.ServiceBackendPort{
+												Number: GetServeGRPCPort(cluster),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return ingress, nil
+}
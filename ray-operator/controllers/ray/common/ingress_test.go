@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -133,3 +134,114 @@ func TestBuildIngressForHeadService(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildServeIngressForRayService(t *testing.T) {
+	ingressClassName := "nginx"
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: rayiov1alpha1.RayServiceSpec{
+			UpgradeStrategy: &rayiov1alpha1.RayServiceUpgradeStrategy{
+				Type: rayiov1alpha1.TrafficShiftingUpgrade,
+				TrafficShifting: &rayiov1alpha1.TrafficShiftingSpec{
+					IngressClassName: &ingressClassName,
+					Host:             "fruit.example.com",
+				},
+			},
+		},
+	}
+
+	ingress, err := BuildServeIngressForRayService(service, *instanceWithIngressEnabled, false, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.GenerateServeIngressName(service.Name, false), ingress.Name)
+	assert.Equal(t, ingressClassName, *ingress.Spec.IngressClassName)
+	assert.Empty(t, ingress.Annotations[NginxCanaryAnnotationKey])
+	rule := ingress.Spec.Rules[0]
+	assert.Equal(t, "fruit.example.com", rule.Host)
+	backend := rule.IngressRuleValue.HTTP.Paths[0].Backend.Service
+	assert.Equal(t, utils.GenerateServeServiceName(instanceWithIngressEnabled.Name), backend.Name)
+	assert.Equal(t, int32(DefaultServingPort), backend.Port.Number)
+
+	canaryIngress, err := BuildServeIngressForRayService(service, *instanceWithIngressEnabled, true, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.GenerateServeIngressName(service.Name, true), canaryIngress.Name)
+	assert.Equal(t, "true", canaryIngress.Annotations[NginxCanaryAnnotationKey])
+	assert.Equal(t, "10", canaryIngress.Annotations[NginxCanaryWeightAnnotationKey])
+}
+
+func TestBuildServeGRPCIngressForRayService(t *testing.T) {
+	ingressClassName := "nginx"
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec:       rayiov1alpha1.RayServiceSpec{ServeGRPC: &rayiov1alpha1.ServeGRPCSpec{}},
+	}
+
+	// The Ingress needs a route.
+	_, err := BuildServeGRPCIngressForRayService(service, *instanceWithIngressEnabled)
+	assert.NotNil(t, err)
+
+	service.Spec.ServeGRPC.Route = &rayiov1alpha1.ServeGRPCRouteSpec{IngressClassName: &ingressClassName, Host: "grpc.example.com"}
+	ingress, err := BuildServeGRPCIngressForRayService(service, *instanceWithIngressEnabled)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.GenerateServeGRPCIngressName(service.Name), ingress.Name)
+	assert.Equal(t, ingressClassName, *ingress.Spec.IngressClassName)
+	assert.Equal(t, "GRPC", ingress.Annotations[NginxBackendProtocolAnnotationKey])
+	rule := ingress.Spec.Rules[0]
+	assert.Equal(t, "grpc.example.com", rule.Host)
+	backend := rule.IngressRuleValue.HTTP.Paths[0].Backend.Service
+	assert.Equal(t, utils.GenerateServeServiceName(service.Name), backend.Name)
+	assert.Equal(t, int32(DefaultServingGRPCPort), backend.Port.Number)
+}
+
+func TestBuildIngressForServeService(t *testing.T) {
+	ingressClassName := "nginx"
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: rayiov1alpha1.RayServiceSpec{
+			ServeApplications: []rayiov1alpha1.ServeApplicationSpec{
+				{Name: "fruit", ImportPath: "fruit.deployment_graph", RoutePrefix: "/fruit"},
+				{Name: "math", ImportPath: "conditional_dag.serve_dag", RoutePrefix: "/calc"},
+			},
+		},
+	}
+
+	// The Ingress needs a serveIngress.
+	_, err := BuildIngressForServeService(service, *instanceWithIngressEnabled)
+	assert.NotNil(t, err)
+
+	service.Spec.ServeIngress = &rayiov1alpha1.ServeIngressSpec{
+		IngressClassName: &ingressClassName,
+		Host:             "fruit.example.com",
+		TLS:              []rayiov1alpha1.ServeIngressTLS{{SecretName: "fruit-tls"}},
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+	}
+	ingress, err := BuildIngressForServeService(service, *instanceWithIngressEnabled)
+	assert.Nil(t, err)
+	assert.Equal(t, utils.GenerateServeIngressName(service.Name, false), ingress.Name)
+	assert.Equal(t, ingressClassName, *ingress.Spec.IngressClassName)
+	assert.Equal(t, "true", ingress.Annotations["nginx.ingress.kubernetes.io/ssl-redirect"])
+	assert.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"fruit.example.com"}, SecretName: "fruit-tls"}}, ingress.Spec.TLS)
+	rule := ingress.Spec.Rules[0]
+	assert.Equal(t, "fruit.example.com", rule.Host)
+
+	// The paths default to the route prefixes of the applications.
+	paths := rule.IngressRuleValue.HTTP.Paths
+	assert.Equal(t, 2, len(paths))
+	assert.Equal(t, "/fruit", paths[0].Path)
+	assert.Equal(t, "/calc", paths[1].Path)
+	for _, path := range paths {
+		assert.Equal(t, utils.GenerateServeServiceName(service.Name), path.Backend.Service.Name)
+		assert.Equal(t, int32(DefaultServingPort), path.Backend.Service.Port.Number)
+	}
+
+	// An application served on / covers all the paths.
+	service.Spec.ServeApplications[1].RoutePrefix = ""
+	ingress, err = BuildIngressForServeService(service, *instanceWithIngressEnabled)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ingress.Spec.Rules[0].HTTP.Paths))
+	assert.Equal(t, "/", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+
+	service.Spec.ServeIngress.Paths = []string{"/fruit"}
+	ingress, err = BuildIngressForServeService(service, *instanceWithIngressEnabled)
+	assert.Nil(t, err)
+	assert.Equal(t, "/fruit", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+}
@@ -0,0 +1,206 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+// IsK8sJobMode returns whether the Ray job is submitted by a Kubernetes Job.
+func IsK8sJobMode(rayJob *rayiov1alpha1.RayJob) bool {
+	return rayJob.Spec.SubmissionMode == rayiov1alpha1.K8sJobMode
+}
+
+// GetDefaultSubmitterTemplate creates the pod template of the submitter Kubernetes Job. The submitter
+// uses the image of the Ray head container, so that the Ray CLI matches the version of the cluster.
+func GetDefaultSubmitterTemplate(rayCluster *rayiov1alpha1.RayCluster) corev1.PodTemplateSpec {
+	headSpec := rayCluster.Spec.HeadGroupSpec.Template.Spec
+	image := headSpec.Containers[utils.FindRayContainerIndex(headSpec)].Image
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  SubmitterContainerName,
+					Image: image,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("200Mi"),
+						},
+					},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyNever,
+		},
+	}
+}
+
+// GetK8sJobCommand builds the shell command of the submitter. It submits the Ray job without waiting,
+// unless a job with the same submission ID already exists (e.g. the submitter pod was restarted), and then
+// follows the driver logs so that they land in the logs of the submitter pod.
+func GetK8sJobCommand(rayJob *rayiov1alpha1.RayJob) ([]string, error) {
+	address := "http://" + rayJob.Status.DashboardURL
+	jobId := rayJob.Status.JobId
+
+	submit := []string{"ray", "job", "submit", "--address", address, "--no-wait", "--submission-id", shellQuote(jobId)}
+	req, err := utils.ConvertRayJobToReq(rayJob)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.RuntimeEnv) > 0 {
+		runtimeEnvJson, err := json.Marshal(req.RuntimeEnv)
+		if err != nil {
+			return nil, err
+		}
+		submit = append(submit, "--runtime-env-json", shellQuote(string(runtimeEnvJson)))
+	}
+	if len(req.Metadata) > 0 {
+		metadataJson, err := json.Marshal(req.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		submit = append(submit, "--metadata-json", shellQuote(string(metadataJson)))
+	}
+	if req.EntrypointNumCpus != nil {
+		submit = append(submit, "--entrypoint-num-cpus", strconv.FormatFloat(*req.EntrypointNumCpus, 'f', -1, 64))
+	}
+	if req.EntrypointNumGpus != nil {
+		submit = append(submit, "--entrypoint-num-gpus", strconv.FormatFloat(*req.EntrypointNumGpus, 'f', -1, 64))
+	}
+	if len(req.EntrypointResources) > 0 {
+		resourcesJson, err := json.Marshal(req.EntrypointResources)
+		if err != nil {
+			return nil, err
+		}
+		submit = append(submit, "--entrypoint-resources", shellQuote(string(resourcesJson)))
+	}
+	submit = append(submit, "--", shellQuote(rayJob.Spec.Entrypoint))
+
+	status := fmt.Sprintf("ray job status --address %s %s >/dev/null 2>&1", address, shellQuote(jobId))
+	logs := fmt.Sprintf("ray job logs --address %s --follow %s", address, shellQuote(jobId))
+	cmd := fmt.Sprintf("if ! %s; then %s; fi && %s", status, strings.Join(submit, " "), logs)
+	return []string{"/bin/sh", "-c", cmd}, nil
+}
+
+// BuildSubmitterJob builds the Kubernetes Job that submits the Ray job to the RayCluster.
+func BuildSubmitterJob(rayJob *rayiov1alpha1.RayJob, rayCluster *rayiov1alpha1.RayCluster) (*batchv1.Job, error) {
+	var template corev1.PodTemplateSpec
+	if rayJob.Spec.SubmitterPodTemplate != nil {
+		template = *rayJob.Spec.SubmitterPodTemplate.DeepCopy()
+	} else {
+		template = GetDefaultSubmitterTemplate(rayCluster)
+	}
+	if len(template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("the submitter pod template of RayJob %s/%s has no containers", rayJob.Namespace, rayJob.Name)
+	}
+	if template.Spec.RestartPolicy == "" {
+		template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	container := &template.Spec.Containers[0]
+	if len(container.Command) == 0 {
+		cmd, err := GetK8sJobCommand(rayJob)
+		if err != nil {
+			return nil, err
+		}
+		container.Command = cmd
+	}
+	if err := AddWorkingDirVolume(&template.Spec, container, rayJob); err != nil {
+		return nil, err
+	}
+	if !envVarExists(RAY_DASHBOARD_ADDRESS, container.Env) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: RAY_DASHBOARD_ADDRESS, Value: rayJob.Status.DashboardURL})
+	}
+	if !envVarExists(RAY_JOB_SUBMISSION_ID, container.Env) {
+		container.Env = append(container.Env, corev1.EnvVar{Name: RAY_JOB_SUBMISSION_ID, Value: rayJob.Status.JobId})
+	}
+
+	labels := map[string]string{
+		RayJobSubmitterLabelKey:     rayJob.Name,
+		KubernetesCreatedByLabelKey: ComponentName,
+	}
+	if template.Labels == nil {
+		template.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		template.Labels[k] = v
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      rayJob.Name,
+			Namespace: rayJob.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32Ptr(DefaultSubmitterBackoffLimit),
+			Template:     template,
+		},
+	}, nil
+}
+
+// shellQuote quotes s so that it is passed to the command as a single argument by /bin/sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// GetWorkingDirVolume returns the volume and the volume mount of the working directory of the RayJob,
+// or nil if workingDirFrom is not set.
+func GetWorkingDirVolume(rayJob *rayiov1alpha1.RayJob) (*corev1.Volume, *corev1.VolumeMount, error) {
+	source := rayJob.Spec.WorkingDirFrom
+	if source == nil {
+		return nil, nil, nil
+	}
+	if (source.ConfigMap == nil) == (source.PersistentVolumeClaim == nil) {
+		return nil, nil, fmt.Errorf("exactly one of configMap and persistentVolumeClaim must be set in the workingDirFrom of RayJob %s/%s", rayJob.Namespace, rayJob.Name)
+	}
+
+	volume := &corev1.Volume{Name: RayJobWorkingDirVolumeName}
+	mount := &corev1.VolumeMount{
+		Name:      RayJobWorkingDirVolumeName,
+		MountPath: utils.RayJobWorkingDirMountPath,
+		ReadOnly:  true,
+	}
+	if source.ConfigMap != nil {
+		volume.VolumeSource.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: source.ConfigMap.Name},
+		}
+	} else {
+		volume.VolumeSource.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: source.PersistentVolumeClaim.ClaimName,
+			ReadOnly:  true,
+		}
+		mount.SubPath = source.PersistentVolumeClaim.SubPath
+	}
+	return volume, mount, nil
+}
+
+// AddWorkingDirVolume mounts the working directory of the RayJob into the given container of the pod,
+// if workingDirFrom is set.
+func AddWorkingDirVolume(podSpec *corev1.PodSpec, container *corev1.Container, rayJob *rayiov1alpha1.RayJob) error {
+	volume, mount, err := GetWorkingDirVolume(rayJob)
+	if err != nil || volume == nil {
+		return err
+	}
+	if !checkIfVolumeExists(&corev1.Pod{Spec: *podSpec}, volume.Name) {
+		podSpec.Volumes = append(podSpec.Volumes, *volume)
+	}
+	if !checkIfVolumeMounted(container, nil, mount.MountPath) {
+		container.VolumeMounts = append(container.VolumeMounts, *mount)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package common
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testRayJob = &rayiov1alpha1.RayJob{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "rayjob-sample",
+		Namespace: "default",
+	},
+	Spec: rayiov1alpha1.RayJobSpec{
+		Entrypoint:     "python /home/ray/samples/sample_code.py",
+		Metadata:       map[string]string{"owner": "o'brien"},
+		RuntimeEnv:     base64.StdEncoding.EncodeToString([]byte(`{"pip":["requests==2.26.0"]}`)),
+		SubmissionMode: rayiov1alpha1.K8sJobMode,
+	},
+	Status: rayiov1alpha1.RayJobStatus{
+		JobId:        "rayjob-sample-abcde",
+		DashboardURL: "rayjob-sample-raycluster-head-svc.default.svc.cluster.local:8265",
+	},
+}
+
+func TestGetK8sJobCommand(t *testing.T) {
+	cmd, err := GetK8sJobCommand(testRayJob)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, cmd[:2])
+
+	address := "--address http://rayjob-sample-raycluster-head-svc.default.svc.cluster.local:8265"
+	expected := "if ! ray job status " + address + " 'rayjob-sample-abcde' >/dev/null 2>&1; " +
+		"then ray job submit " + address + " --no-wait --submission-id 'rayjob-sample-abcde'" +
+		` --runtime-env-json '{"pip":["requests==2.26.0"]}'` +
+		` --metadata-json '{"owner":"o'\''brien"}'` +
+		" -- 'python /home/ray/samples/sample_code.py'; fi && " +
+		"ray job logs " + address + " --follow 'rayjob-sample-abcde'"
+	assert.Equal(t, expected, cmd[2])
+
+	rayJob := testRayJob.DeepCopy()
+	numCpus, numGpus := 0.5, 1.0
+	rayJob.Spec.EntrypointNumCpus = &numCpus
+	rayJob.Spec.EntrypointNumGpus = &numGpus
+	rayJob.Spec.EntrypointResources = `{"worker_node": 1}`
+	cmd, err = GetK8sJobCommand(rayJob)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(cmd[2], ` --entrypoint-num-cpus 0.5 --entrypoint-num-gpus 1 --entrypoint-resources '{"worker_node":1}' -- 'python`))
+
+	// A compound entrypoint is submitted as a whole, instead of being split by the shell of the submitter.
+	rayJob = testRayJob.DeepCopy()
+	rayJob.Spec.Entrypoint = "python a.py && python b.py"
+	cmd, err = GetK8sJobCommand(rayJob)
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(cmd[2], " -- 'python a.py && python b.py'; fi && "))
+}
+
+func TestBuildSubmitterJob(t *testing.T) {
+	cluster := instanceWithWrongSvc.DeepCopy()
+
+	// The default submitter uses the image of the Ray head container.
+	job, err := BuildSubmitterJob(testRayJob, cluster)
+	assert.Nil(t, err)
+	assert.Equal(t, testRayJob.Name, job.Name)
+	assert.Equal(t, testRayJob.Namespace, job.Namespace)
+	assert.Equal(t, testRayJob.Name, job.Spec.Template.Labels[RayJobSubmitterLabelKey])
+	assert.Equal(t, int32(DefaultSubmitterBackoffLimit), *job.Spec.BackoffLimit)
+	assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, SubmitterContainerName, container.Name)
+	assert.Equal(t, "rayproject/autoscaler", container.Image)
+	assert.True(t, strings.Contains(container.Command[2], "ray job submit"))
+	assert.True(t, envVarExists(RAY_DASHBOARD_ADDRESS, container.Env))
+	assert.True(t, envVarExists(RAY_JOB_SUBMISSION_ID, container.Env))
+
+	// A custom submitter template keeps its own command.
+	rayJob := testRayJob.DeepCopy()
+	rayJob.Spec.SubmitterPodTemplate = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:    "custom-submitter",
+					Image:   "rayproject/ray:2.4.0",
+					Command: []string{"/bin/sh", "-c", "ray job submit --address http://$RAY_DASHBOARD_ADDRESS -- echo hi"},
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+		},
+	}
+	job, err = BuildSubmitterJob(rayJob, cluster)
+	assert.Nil(t, err)
+	assert.Equal(t, corev1.RestartPolicyOnFailure, job.Spec.Template.Spec.RestartPolicy)
+	assert.Equal(t, rayJob.Spec.SubmitterPodTemplate.Spec.Containers[0].Command, job.Spec.Template.Spec.Containers[0].Command)
+	assert.True(t, envVarExists(RAY_DASHBOARD_ADDRESS, job.Spec.Template.Spec.Containers[0].Env))
+	// The RayJob itself must not be mutated.
+	assert.Empty(t, rayJob.Spec.SubmitterPodTemplate.Spec.Containers[0].Env)
+
+	// A submitter template without containers is rejected.
+	rayJob.Spec.SubmitterPodTemplate = &corev1.PodTemplateSpec{}
+	_, err = BuildSubmitterJob(rayJob, cluster)
+	assert.NotNil(t, err)
+}
+
+func TestGetWorkingDirVolume(t *testing.T) {
+	rayJob := testRayJob.DeepCopy()
+	volume, mount, err := GetWorkingDirVolume(rayJob)
+	assert.Nil(t, err)
+	assert.Nil(t, volume)
+	assert.Nil(t, mount)
+
+	rayJob.Spec.WorkingDirFrom = &rayiov1alpha1.WorkingDirSource{
+		ConfigMap: &rayiov1alpha1.ConfigMapWorkingDirSource{Name: "job-scripts"},
+	}
+	volume, mount, err = GetWorkingDirVolume(rayJob)
+	assert.Nil(t, err)
+	assert.Equal(t, "job-scripts", volume.ConfigMap.Name)
+	assert.Equal(t, RayJobWorkingDirVolumeName, mount.Name)
+	assert.Equal(t, utils.RayJobWorkingDirMountPath, mount.MountPath)
+	assert.True(t, mount.ReadOnly)
+
+	rayJob.Spec.WorkingDirFrom = &rayiov1alpha1.WorkingDirSource{
+		PersistentVolumeClaim: &rayiov1alpha1.PVCWorkingDirSource{ClaimName: "shared", SubPath: "jobs/train"},
+	}
+	volume, mount, err = GetWorkingDirVolume(rayJob)
+	assert.Nil(t, err)
+	assert.Equal(t, "shared", volume.PersistentVolumeClaim.ClaimName)
+	assert.True(t, volume.PersistentVolumeClaim.ReadOnly)
+	assert.Equal(t, "jobs/train", mount.SubPath)
+
+	// Exactly one source must be set.
+	rayJob.Spec.WorkingDirFrom.ConfigMap = &rayiov1alpha1.ConfigMapWorkingDirSource{Name: "job-scripts"}
+	_, _, err = GetWorkingDirVolume(rayJob)
+	assert.NotNil(t, err)
+	rayJob.Spec.WorkingDirFrom = &rayiov1alpha1.WorkingDirSource{}
+	_, _, err = GetWorkingDirVolume(rayJob)
+	assert.NotNil(t, err)
+}
+
+func TestBuildSubmitterJobWithWorkingDir(t *testing.T) {
+	rayJob := testRayJob.DeepCopy()
+	rayJob.Spec.WorkingDirFrom = &rayiov1alpha1.WorkingDirSource{
+		ConfigMap: &rayiov1alpha1.ConfigMapWorkingDirSource{Name: "job-scripts"},
+	}
+	job, err := BuildSubmitterJob(rayJob, instanceWithWrongSvc.DeepCopy())
+	assert.Nil(t, err)
+
+	// The submitter uploads the working directory from its own file system.
+	podSpec := job.Spec.Template.Spec
+	assert.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, RayJobWorkingDirVolumeName, podSpec.Volumes[0].Name)
+	assert.Equal(t, utils.RayJobWorkingDirMountPath, podSpec.Containers[0].VolumeMounts[0].MountPath)
+	assert.True(t, strings.Contains(podSpec.Containers[0].Command[2], `"working_dir":"`+utils.RayJobWorkingDirMountPath+`"`))
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+// HTTPRouteGroupVersionKind is the HTTPRoute of the Gateway API.
+var HTTPRouteGroupVersionKind = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1beta1", Kind: "HTTPRoute"}
+
+// GRPCRouteGroupVersionKind is the GRPCRoute of the Gateway API.
+var GRPCRouteGroupVersionKind = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "GRPCRoute"}
+
+// BuildHTTPRouteForRayService builds the HTTPRoute splitting the traffic of a RayService with a TrafficShifting
+// upgrade between the serve services of the active cluster and of the pending one, which receives pendingWeight
+// percent of the traffic. The pending cluster is nil outside of upgrades. The HTTPRoute is unstructured because
+// the Gateway API is an optional add-on of the Kubernetes cluster.
+func BuildHTTPRouteForRayService(service rayiov1alpha1.RayService, active rayiov1alpha1.RayCluster, pending *rayiov1alpha1.RayCluster, pendingWeight int32) (*unstructured.Unstructured, error) {
+	trafficShifting := GetTrafficShiftingSpec(service)
+	if trafficShifting.Gateway == nil {
+		return nil, fmt.Errorf("the Gateway router of RayService %s/%s needs a gateway", service.Namespace, service.Name)
+	}
+	parentRef := map[string]interface{}{"name": trafficShifting.Gateway.Name}
+	if trafficShifting.Gateway.Namespace != "" {
+		parentRef["namespace"] = trafficShifting.Gateway.Namespace
+	}
+
+	backendRefs := []interface{}{backendRef(active, 100-pendingWeight)}
+	if pending != nil {
+		backendRefs = append(backendRefs, backendRef(*pending, pendingWeight))
+	}
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{"backendRefs": backendRefs},
+		},
+	}
+	if trafficShifting.Host != "" {
+		spec["hostnames"] = []interface{}{trafficShifting.Host}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(HTTPRouteGroupVersionKind)
+	route.SetName(utils.GenerateServeRouteName(service.Name))
+	route.SetNamespace(service.Namespace)
+	route.SetLabels(map[string]string{RayServiceLabelKey: service.Name})
+
+	return route, nil
+}
+
+// BuildGRPCRouteForRayService builds the GRPCRoute routing gRPC traffic to the gRPC port of the serve service of the
+// RayService, which follows the active cluster.
+func BuildGRPCRouteForRayService(service rayiov1alpha1.RayService, cluster rayiov1alpha1.RayCluster) (*unstructured.Unstructured, error) {
+	if service.Spec.ServeGRPC == nil || service.Spec.ServeGRPC.Route == nil {
+		return nil, fmt.Errorf("RayService %s/%s has no serveGRPC route", service.Namespace, service.Name)
+	}
+	grpcRoute := service.Spec.ServeGRPC.Route
+	if grpcRoute.Gateway == nil {
+		return nil, fmt.Errorf("the gRPC Gateway router of RayService %s/%s needs a gateway", service.Namespace, service.Name)
+	}
+	parentRef := map[string]interface{}{"name": grpcRoute.Gateway.Name}
+	if grpcRoute.Gateway.Namespace != "" {
+		parentRef["namespace"] = grpcRoute.Gateway.Namespace
+	}
+
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": utils.GenerateServeServiceName(service.Name),
+						"port": int64(GetServeGRPCPort(cluster)),
+					},
+				},
+			},
+		},
+	}
+	if grpcRoute.Host != "" {
+		spec["hostnames"] = []interface{}{grpcRoute.Host}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(GRPCRouteGroupVersionKind)
+	route.SetName(utils.GenerateServeGRPCRouteName(service.Name))
+	route.SetNamespace(service.Namespace)
+	route.SetLabels(map[string]string{RayServiceLabelKey: service.Name})
+
+	return route, nil
+}
+
+func backendRef(cluster rayiov1alpha1.RayCluster, weight int32) map[string]interface{} {
+	return map[string]interface{}{
+		"name":   utils.GenerateServeServiceName(cluster.Name),
+		"port":   int64(getServePort(cluster)),
+		"weight": int64(weight),
+	}
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	rayiov1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+)
+
+func TestBuildHTTPRouteForRayService(t *testing.T) {
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: rayiov1alpha1.RayServiceSpec{
+			UpgradeStrategy: &rayiov1alpha1.RayServiceUpgradeStrategy{
+				Type:            rayiov1alpha1.TrafficShiftingUpgrade,
+				TrafficShifting: &rayiov1alpha1.TrafficShiftingSpec{Router: rayiov1alpha1.GatewayRouter},
+			},
+		},
+	}
+	active := *instanceWithIngressEnabled
+	pending := *instanceWithIngressEnabled.DeepCopy()
+	pending.Name = "raycluster-pending"
+
+	// The Gateway router needs a gateway.
+	_, err := BuildHTTPRouteForRayService(service, active, nil, 0)
+	assert.NotNil(t, err)
+
+	service.Spec.UpgradeStrategy.TrafficShifting.Gateway = &rayiov1alpha1.GatewayReference{Name: "gateway", Namespace: "infra"}
+	route, err := BuildHTTPRouteForRayService(service, active, &pending, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, HTTPRouteGroupVersionKind, route.GroupVersionKind())
+	assert.Equal(t, utils.GenerateServeRouteName(service.Name), route.GetName())
+
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "gateway", "namespace": "infra"}}, parentRefs)
+	_, found, _ := unstructured.NestedSlice(route.Object, "spec", "hostnames")
+	assert.False(t, found)
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	backendRefs := rules[0].(map[string]interface{})["backendRefs"].([]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": utils.GenerateServeServiceName(active.Name), "port": int64(DefaultServingPort), "weight": int64(90)},
+		map[string]interface{}{"name": utils.GenerateServeServiceName(pending.Name), "port": int64(DefaultServingPort), "weight": int64(10)},
+	}, backendRefs)
+}
+
+func TestBuildGRPCRouteForRayService(t *testing.T) {
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: rayiov1alpha1.RayServiceSpec{
+			ServeGRPC: &rayiov1alpha1.ServeGRPCSpec{
+				Route: &rayiov1alpha1.ServeGRPCRouteSpec{Router: rayiov1alpha1.GatewayRouter, Host: "grpc.example.com"},
+			},
+		},
+	}
+
+	// The Gateway router needs a gateway.
+	_, err := BuildGRPCRouteForRayService(service, *instanceWithIngressEnabled)
+	assert.NotNil(t, err)
+
+	service.Spec.ServeGRPC.Route.Gateway = &rayiov1alpha1.GatewayReference{Name: "gateway"}
+	route, err := BuildGRPCRouteForRayService(service, *instanceWithIngressEnabled)
+	assert.Nil(t, err)
+	assert.Equal(t, GRPCRouteGroupVersionKind, route.GroupVersionKind())
+	assert.Equal(t, utils.GenerateServeGRPCRouteName(service.Name), route.GetName())
+
+	parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "gateway"}}, parentRefs)
+	hostnames, _, _ := unstructured.NestedSlice(route.Object, "spec", "hostnames")
+	assert.Equal(t, []interface{}{"grpc.example.com"}, hostnames)
+
+	rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+	backendRefs := rules[0].(map[string]interface{})["backendRefs"].([]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": utils.GenerateServeServiceName(service.Name), "port": int64(DefaultServingGRPCPort)},
+	}, backendRefs)
+}
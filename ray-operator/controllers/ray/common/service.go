@@ -205,10 +205,70 @@ func BuildServeServiceForRayService(rayService rayiov1alpha1.RayService, rayClus
 			break
 		}
 	}
+	// The gRPC proxy runs next to the HTTP proxy, so the pods labelled by the HTTP proxy health checks serve both.
+	if rayService.Spec.ServeGRPC != nil {
+		appProtocol := GRPCServiceAppProtocol
+		svcPort := corev1.ServicePort{Name: DefaultServingGRPCPortName, Port: GetServeGRPCPort(rayCluster), AppProtocol: &appProtocol}
+		service.Spec.Ports = append(service.Spec.Ports, svcPort)
+	}
+
+	return service, nil
+}
+
+// BuildServeServiceForRayCluster builds the serve service of a single RayCluster of a RayService. The traffic router
+// of a TrafficShifting upgrade splits the traffic between the serve services of the active and pending RayClusters.
+func BuildServeServiceForRayCluster(rayService rayiov1alpha1.RayService, rayCluster rayiov1alpha1.RayCluster) (*corev1.Service, error) {
+	service, err := BuildServeServiceForRayService(rayService, rayCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	service.ObjectMeta.Name = utils.GenerateServeServiceName(rayCluster.Name)
+	service.ObjectMeta.Labels = map[string]string{
+		RayServiceLabelKey: rayService.Name,
+		RayClusterLabelKey: rayCluster.Name,
+	}
+	// The router is the entry point of the traffic.
+	service.Spec.Type = corev1.ServiceTypeClusterIP
 
 	return service, nil
 }
 
+// getServePort returns the serving port of the cluster.
+func getServePort(cluster rayiov1alpha1.RayCluster) int32 {
+	if port, ok := getServicePorts(cluster)[DefaultServingPortName]; ok {
+		return port
+	}
+	return DefaultServingPort
+}
+
+// GetServeGRPCPort returns the port of the Serve gRPC proxy of the cluster.
+func GetServeGRPCPort(cluster rayiov1alpha1.RayCluster) int32 {
+	if port, ok := getServicePorts(cluster)[DefaultServingGRPCPortName]; ok {
+		return port
+	}
+	return DefaultServingGRPCPort
+}
+
+// GetTrafficShiftingSpec returns the TrafficShifting upgrade configuration of the RayService with its defaults.
+func GetTrafficShiftingSpec(rayService rayiov1alpha1.RayService) rayiov1alpha1.TrafficShiftingSpec {
+	spec := rayiov1alpha1.TrafficShiftingSpec{}
+	if rayService.Spec.UpgradeStrategy != nil && rayService.Spec.UpgradeStrategy.TrafficShifting != nil {
+		spec = *rayService.Spec.UpgradeStrategy.TrafficShifting.DeepCopy()
+	}
+	if len(spec.Steps) == 0 {
+		spec.Steps = DefaultTrafficShiftingSteps
+	}
+	if spec.StepIntervalSeconds == nil {
+		interval := int32(DefaultTrafficShiftingStepIntervalSeconds)
+		spec.StepIntervalSeconds = &interval
+	}
+	if spec.Router == "" {
+		spec.Router = rayiov1alpha1.IngressRouter
+	}
+	return spec
+}
+
 // BuildDashboardService Builds the service for dashboard agent and head node.
 func BuildDashboardService(cluster rayiov1alpha1.RayCluster) (*corev1.Service, error) {
 	labels := map[string]string{
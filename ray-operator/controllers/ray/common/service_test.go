@@ -390,3 +390,22 @@ func TestBuildServiceForHeadPodPortsOrder(t *testing.T) {
 		assert.Equal(t, ports1[i].Name, ports2[i].Name)
 	}
 }
+
+func TestBuildServeServiceForRayServiceWithGRPC(t *testing.T) {
+	service := rayiov1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+	}
+	svc, err := BuildServeServiceForRayService(service, *instanceWithWrongSvc)
+	assert.Nil(t, err)
+	for _, port := range svc.Spec.Ports {
+		assert.NotEqual(t, DefaultServingGRPCPortName, port.Name)
+	}
+
+	service.Spec.ServeGRPC = &rayiov1alpha1.ServeGRPCSpec{}
+	svc, err = BuildServeServiceForRayService(service, *instanceWithWrongSvc)
+	assert.Nil(t, err)
+	grpcPort := svc.Spec.Ports[len(svc.Spec.Ports)-1]
+	assert.Equal(t, DefaultServingGRPCPortName, grpcPort.Name)
+	assert.Equal(t, int32(DefaultServingGRPCPort), grpcPort.Port)
+	assert.Equal(t, GRPCServiceAppProtocol, *grpcPort.AppProtocol)
+}
@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{"* * * * *", "0 2 * * *", "*/15 9-17 * * MON-FRI", "0 0 1,15 * *", "30 4 1 jan,jul ?", "@daily", "@Hourly", "5/10 * * * 7"} {
+		_, err := Parse(spec)
+		assert.Nil(t, err, spec)
+	}
+	for _, spec := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "* * * * funday", "@every 5m"} {
+		_, err := Parse(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestNext(t *testing.T) {
+	base := time.Date(2023, time.March, 15, 10, 7, 30, 0, time.UTC) // A Wednesday.
+	tests := []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"* * * * *", base, time.Date(2023, time.March, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 2 * * *", base, time.Date(2023, time.March, 16, 2, 0, 0, 0, time.UTC)},
+		{"@hourly", base, time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", base, time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", base, time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2023, time.March, 17, 10, 0, 0, 0, time.UTC), time.Date(2023, time.March, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2023, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", base, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// When both day fields are restricted, either of them matches.
+		{"0 0 1 * FRI", base, time.Date(2023, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		// A time that matches the schedule is not returned again.
+		{"0 10 * * *", time.Date(2023, time.March, 15, 10, 0, 0, 0, time.UTC), time.Date(2023, time.March, 16, 10, 0, 0, 0, time.UTC)},
+		// An impossible date is never reached.
+		{"0 0 30 2 *", base, time.Time{}},
+	}
+	for _, test := range tests {
+		schedule, err := Parse(test.spec)
+		assert.Nil(t, err, test.spec)
+		assert.Equal(t, test.expected, schedule.Next(test.from), test.spec)
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	schedule, err := Parse("0 2 * * *")
+	assert.Nil(t, err)
+
+	// The schedule is evaluated in the location of the given time.
+	from := time.Date(2023, time.January, 10, 12, 0, 0, 0, loc)
+	assert.Equal(t, time.Date(2023, time.January, 11, 2, 0, 0, 0, loc), schedule.Next(from))
+	assert.Equal(t, time.Date(2023, time.January, 11, 7, 0, 0, 0, time.UTC), schedule.Next(from).UTC())
+
+	// 2:00 does not exist when daylight saving time starts, so the run of that day is skipped.
+	from = time.Date(2023, time.March, 11, 12, 0, 0, 0, loc)
+	assert.Equal(t, time.Date(2023, time.March, 13, 2, 0, 0, 0, loc), schedule.Next(from))
+
+	// 1:30 happens twice when daylight saving time ends, like with Kubernetes CronJobs.
+	schedule, err = Parse("30 1 * * *")
+	assert.Nil(t, err)
+	first := schedule.Next(time.Date(2023, time.November, 4, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2023, time.November, 5, 5, 30, 0, 0, time.UTC), first.UTC())
+	second := schedule.Next(first)
+	assert.Equal(t, time.Date(2023, time.November, 5, 6, 30, 0, 0, time.UTC), second.UTC())
+	assert.Equal(t, time.Date(2023, time.November, 6, 1, 30, 0, 0, loc), schedule.Next(second))
+}
@@ -0,0 +1,77 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+const (
+	// ConfigMapLogsKey is the key of the driver logs in the ConfigMap.
+	ConfigMapLogsKey = "driver.log"
+	// MaxConfigMapLogsBytes keeps the ConfigMap below the 1MiB limit of Kubernetes objects.
+	MaxConfigMapLogsBytes = 1000 * 1000
+)
+
+// ConfigMapSink stores the driver logs in a ConfigMap owned by the RayJob.
+type ConfigMapSink struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+	// Name of the ConfigMap. Defaults to <rayjob name>-driver-logs.
+	Name string
+}
+
+var _ Sink = (*ConfigMapSink)(nil)
+
+func (s *ConfigMapSink) Write(ctx context.Context, rayJob *rayv1alpha1.RayJob, logs string) (string, error) {
+	name := s.Name
+	if name == "" {
+		name = rayJob.Name + "-driver-logs"
+	}
+	if len(logs) > MaxConfigMapLogsBytes {
+		logs = logs[len(logs)-MaxConfigMapLogsBytes:]
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: rayJob.Namespace,
+			Annotations: map[string]string{
+				"ray.io/job-id": rayJob.Status.JobId,
+			},
+		},
+		Data: map[string]string{ConfigMapLogsKey: logs},
+	}
+	if err := ctrl.SetControllerReference(rayJob, configMap, s.Scheme); err != nil {
+		return "", err
+	}
+
+	location := fmt.Sprintf("configmap/%s/%s", rayJob.Namespace, name)
+	if err := s.Client.Create(ctx, configMap); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+		// Only overwrite the ConfigMap of the RayJob, never one that happens to have the same name.
+		existing := &corev1.ConfigMap{}
+		if err := s.Client.Get(ctx, client.ObjectKeyFromObject(configMap), existing); err != nil {
+			return "", err
+		}
+		if !metav1.IsControlledBy(existing, rayJob) {
+			return "", fmt.Errorf("ConfigMap %s already exists and is not owned by RayJob %s", name, rayJob.Name)
+		}
+		existing.Annotations = configMap.Annotations
+		existing.Data = configMap.Data
+		if err := s.Client.Update(ctx, existing); err != nil {
+			return "", err
+		}
+	}
+	return location, nil
+}
@@ -0,0 +1,49 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// FileSink stores the driver logs in files under a directory of the local filesystem,
+// e.g. the mount point of a PersistentVolumeClaim.
+type FileSink struct {
+	Root string
+}
+
+var _ Sink = (*FileSink)(nil)
+
+func (s *FileSink) Write(ctx context.Context, rayJob *rayv1alpha1.RayJob, logs string) (string, error) {
+	name := filepath.Join(s.Root, filepath.FromSlash(LogObjectName(rayJob)))
+	if err := writeFile(name, []byte(logs)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// FileObjectStore is an ObjectStore backed by the local filesystem, mostly useful for tests. Buckets are
+// directories, so a prefix such as file:///tmp/ray-logs writes under /tmp/ray-logs. It is not registered
+// by default, since it lets RayJobs write anywhere the operator can.
+type FileObjectStore struct{}
+
+var _ ObjectStore = (*FileObjectStore)(nil)
+
+func (s *FileObjectStore) PutObject(ctx context.Context, bucket string, key string, data []byte) error {
+	name := filepath.Join(bucket, filepath.FromSlash(key))
+	if !strings.HasPrefix(name, filepath.Clean(bucket)+string(filepath.Separator)) {
+		return fmt.Errorf("object key %q escapes bucket %q", key, bucket)
+	}
+	return writeFile(name, data)
+}
+
+func writeFile(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
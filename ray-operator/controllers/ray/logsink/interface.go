@@ -0,0 +1,120 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// DefaultTailLines is the number of lines of the driver logs kept in the RayJob status by default.
+const DefaultTailLines = 50
+
+// MaxTailBytes bounds the size of the tail kept in the RayJob status, whatever the number of lines.
+const MaxTailBytes = 8 * 1024
+
+// PVCMountRoot is the directory under which the PersistentVolumeClaims used as log sinks are mounted
+// into the operator, each one in a sub-directory named after the claim.
+var PVCMountRoot = "/var/run/kuberay/driver-logs"
+
+// Sink stores the full driver logs of a Ray job.
+type Sink interface {
+	// Write stores the driver logs of the Ray job and returns where they can be found.
+	Write(ctx context.Context, rayJob *rayv1alpha1.RayJob, logs string) (string, error)
+}
+
+// ObjectStore writes objects to an object store such as S3 or GCS.
+type ObjectStore interface {
+	// PutObject writes data to the object named key in bucket.
+	PutObject(ctx context.Context, bucket string, key string, data []byte) error
+}
+
+var (
+	objectStoresLock sync.RWMutex
+	objectStores     = map[string]ObjectStore{}
+)
+
+// RegisterObjectStore makes an ObjectStore available for ObjectStore sinks whose prefix has the given URL scheme.
+// No object store is registered by default.
+func RegisterObjectStore(scheme string, store ObjectStore) {
+	objectStoresLock.Lock()
+	defer objectStoresLock.Unlock()
+	objectStores[scheme] = store
+}
+
+func getObjectStore(scheme string) (ObjectStore, error) {
+	objectStoresLock.RLock()
+	defer objectStoresLock.RUnlock()
+	store, ok := objectStores[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no object store is registered for scheme %q", scheme)
+	}
+	return store, nil
+}
+
+// NewSink returns the Sink configured by spec, or nil if no sink is configured.
+func NewSink(cli client.Client, scheme *runtime.Scheme, spec *rayv1alpha1.DriverLogsSink) (Sink, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	switch {
+	case spec.ConfigMap != nil:
+		return &ConfigMapSink{Client: cli, Scheme: scheme, Name: spec.ConfigMap.Name}, nil
+	case spec.PersistentVolumeClaim != nil:
+		if spec.PersistentVolumeClaim.ClaimName == "" {
+			return nil, fmt.Errorf("claimName of the PersistentVolumeClaim log sink is empty")
+		}
+		claimRoot := filepath.Join(PVCMountRoot, spec.PersistentVolumeClaim.ClaimName)
+		root := filepath.Join(claimRoot, spec.PersistentVolumeClaim.Path)
+		// The RayJob must not be able to write outside of the mount point of its claim.
+		if filepath.Dir(claimRoot) != filepath.Clean(PVCMountRoot) || (root != claimRoot && !strings.HasPrefix(root, claimRoot+string(filepath.Separator))) {
+			return nil, fmt.Errorf("invalid PersistentVolumeClaim log sink %s:%s", spec.PersistentVolumeClaim.ClaimName, spec.PersistentVolumeClaim.Path)
+		}
+		return &FileSink{Root: root}, nil
+	case spec.ObjectStore != nil:
+		prefix, err := url.Parse(spec.ObjectStore.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object store prefix %q: %v", spec.ObjectStore.Prefix, err)
+		}
+		store, err := getObjectStore(prefix.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		return &ObjectStoreSink{Store: store, Prefix: prefix}, nil
+	}
+	return nil, nil
+}
+
+// LogObjectName returns the path of the driver logs of the Ray job relative to the root of a sink.
+func LogObjectName(rayJob *rayv1alpha1.RayJob) string {
+	return path.Join(rayJob.Namespace, rayJob.Name, rayJob.Status.JobId+".log")
+}
+
+// Tail returns at most the last lines of logs, bounded by MaxTailBytes.
+func Tail(logs string, lines int) string {
+	if lines <= 0 {
+		return ""
+	}
+	logs = strings.TrimRight(logs, "\n")
+	idx := len(logs)
+	for i := 0; i < lines && idx > 0; i++ {
+		idx = strings.LastIndex(logs[:idx], "\n")
+		if idx < 0 {
+			idx = 0
+			break
+		}
+	}
+	tail := strings.TrimPrefix(logs[idx:], "\n")
+	if len(tail) > MaxTailBytes {
+		tail = tail[len(tail)-MaxTailBytes:]
+	}
+	return tail
+}
@@ -0,0 +1,137 @@
+package logsink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+var rayJob = &rayv1alpha1.RayJob{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "rayjob-sample",
+		Namespace: "default",
+		UID:       "1",
+	},
+	Status: rayv1alpha1.RayJobStatus{
+		JobId: "rayjob-sample-abcde",
+	},
+}
+
+func TestTail(t *testing.T) {
+	logs := "line1\nline2\nline3\n"
+	assert.Equal(t, "", Tail(logs, 0))
+	assert.Equal(t, "line3", Tail(logs, 1))
+	assert.Equal(t, "line2\nline3", Tail(logs, 2))
+	assert.Equal(t, "line1\nline2\nline3", Tail(logs, 10))
+	assert.Equal(t, "", Tail("", 10))
+
+	long := strings.Repeat("x", 2*MaxTailBytes)
+	assert.Len(t, Tail(long, 1), MaxTailBytes)
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink(nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, sink)
+
+	sink, err = NewSink(nil, nil, &rayv1alpha1.DriverLogsSink{
+		PersistentVolumeClaim: &rayv1alpha1.PVCLogsSink{ClaimName: "logs", Path: "ray/jobs"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(PVCMountRoot, "logs", "ray", "jobs"), sink.(*FileSink).Root)
+
+	// The path must stay on the mount point of the claim.
+	_, err = NewSink(nil, nil, &rayv1alpha1.DriverLogsSink{
+		PersistentVolumeClaim: &rayv1alpha1.PVCLogsSink{ClaimName: "logs", Path: "../other"},
+	})
+	assert.NotNil(t, err)
+	_, err = NewSink(nil, nil, &rayv1alpha1.DriverLogsSink{
+		PersistentVolumeClaim: &rayv1alpha1.PVCLogsSink{ClaimName: ".."},
+	})
+	assert.NotNil(t, err)
+
+	_, err = NewSink(nil, nil, &rayv1alpha1.DriverLogsSink{
+		ObjectStore: &rayv1alpha1.ObjectStoreLogsSink{Prefix: "unregistered://bucket/logs"},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestFileSink(t *testing.T) {
+	root := t.TempDir()
+	sink := &FileSink{Root: root}
+	location, err := sink.Write(context.TODO(), rayJob, "hello\n")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "default", "rayjob-sample", "rayjob-sample-abcde.log"), location)
+
+	data, err := os.ReadFile(location)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello\n", string(data))
+}
+
+func TestObjectStoreSink(t *testing.T) {
+	RegisterObjectStore("file", &FileObjectStore{})
+	defer func() {
+		objectStoresLock.Lock()
+		delete(objectStores, "file")
+		objectStoresLock.Unlock()
+	}()
+
+	root := t.TempDir()
+	sink, err := NewSink(nil, nil, &rayv1alpha1.DriverLogsSink{
+		ObjectStore: &rayv1alpha1.ObjectStoreLogsSink{Prefix: "file://" + root},
+	})
+	assert.Nil(t, err)
+
+	location, err := sink.Write(context.TODO(), rayJob, "hello\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(root, "default", "rayjob-sample", "rayjob-sample-abcde.log")), location)
+
+	data, err := os.ReadFile(filepath.Join(root, "default", "rayjob-sample", "rayjob-sample-abcde.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, "hello\n", string(data))
+}
+
+func TestConfigMapSink(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).Build()
+
+	sink, err := NewSink(fakeClient, newScheme, &rayv1alpha1.DriverLogsSink{ConfigMap: &rayv1alpha1.ConfigMapLogsSink{}})
+	assert.Nil(t, err)
+
+	location, err := sink.Write(context.TODO(), rayJob, "hello\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "configmap/default/rayjob-sample-driver-logs", location)
+
+	// Writing again overwrites the logs.
+	_, err = sink.Write(context.TODO(), rayJob, strings.Repeat("x", MaxConfigMapLogsBytes+10))
+	assert.Nil(t, err)
+
+	configMap := &corev1.ConfigMap{}
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "rayjob-sample-driver-logs"}, configMap)
+	assert.Nil(t, err)
+	assert.Len(t, configMap.Data[ConfigMapLogsKey], MaxConfigMapLogsBytes)
+	assert.Equal(t, rayJob.Name, configMap.OwnerReferences[0].Name)
+
+	// A ConfigMap that is not owned by the RayJob is not overwritten.
+	other := rayJob.DeepCopy()
+	other.Name = "rayjob-other"
+	other.UID = "2"
+	_, err = (&ConfigMapSink{Client: fakeClient, Scheme: newScheme, Name: "rayjob-sample-driver-logs"}).Write(context.TODO(), other, "other\n")
+	assert.NotNil(t, err)
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "rayjob-sample-driver-logs"}, configMap)
+	assert.Nil(t, err)
+	assert.Len(t, configMap.Data[ConfigMapLogsKey], MaxConfigMapLogsBytes)
+}
@@ -0,0 +1,37 @@
+package logsink
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+// ObjectStoreSink stores the driver logs as objects under a prefix of an object store.
+type ObjectStoreSink struct {
+	Store ObjectStore
+	// Prefix is the URL under which the logs are written, e.g. s3://bucket/ray-logs.
+	Prefix *url.URL
+}
+
+var _ Sink = (*ObjectStoreSink)(nil)
+
+func (s *ObjectStoreSink) Write(ctx context.Context, rayJob *rayv1alpha1.RayJob, logs string) (string, error) {
+	bucket := s.Prefix.Host
+	prefix := s.Prefix.Path
+	if s.Prefix.Scheme == "file" {
+		// file:///tmp/ray-logs has no host: the whole path is the directory.
+		bucket, prefix = s.Prefix.Path, ""
+	}
+	key := path.Join(strings.TrimPrefix(prefix, "/"), LogObjectName(rayJob))
+	if err := s.Store.PutObject(ctx, bucket, key, []byte(logs)); err != nil {
+		return "", err
+	}
+	location := url.URL{Scheme: s.Prefix.Scheme, Host: s.Prefix.Host, Path: path.Join("/", prefix, LogObjectName(rayJob))}
+	if s.Prefix.Scheme == "file" {
+		location.Path = path.Join(bucket, key)
+	}
+	return location.String(), nil
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+const (
+	// CloudEventsSpecVersion is the version of the CloudEvents specification the notifications follow.
+	CloudEventsSpecVersion = "1.0"
+	// EventTypePrefix prefixes the notification event, e.g. io.ray.kuberay.JobSucceeded, in the CloudEvent type.
+	EventTypePrefix = "io.ray.kuberay."
+	// DefaultMaxRetries is the number of times a notification is retried unless maxRetries is set.
+	DefaultMaxRetries = 3
+)
+
+// Notifier delivers the notifications of a RayJob or a RayService.
+type Notifier interface {
+	// Notify delivers the event, retrying until it succeeds or the retries are exhausted.
+	Notify(ctx context.Context, event Event) error
+}
+
+// Event is a CloudEvent in its structured JSON format.
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            metav1.Time `json:"time"`
+	DataContentType string      `json:"datacontenttype,omitempty"`
+	Data            interface{} `json:"data,omitempty"`
+}
+
+// JobData is the data of the notifications of a RayJob.
+type JobData struct {
+	JobId               string                          `json:"jobId,omitempty"`
+	JobStatus           rayv1alpha1.JobStatus           `json:"jobStatus,omitempty"`
+	JobDeploymentStatus rayv1alpha1.JobDeploymentStatus `json:"jobDeploymentStatus,omitempty"`
+	RayClusterName      string                          `json:"rayClusterName,omitempty"`
+	Message             string                          `json:"message,omitempty"`
+	Reason              rayv1alpha1.JobFailedReason     `json:"reason,omitempty"`
+	FailureType         rayv1alpha1.JobFailureType      `json:"failureType,omitempty"`
+	Failed              int32                           `json:"failed,omitempty"`
+	StartTime           *metav1.Time                    `json:"startTime,omitempty"`
+	EndTime             *metav1.Time                    `json:"endTime,omitempty"`
+}
+
+// ServiceData is the data of the notifications of a RayService.
+type ServiceData struct {
+	ServiceStatus         rayv1alpha1.ServiceStatus `json:"serviceStatus,omitempty"`
+	ActiveRayClusterName  string                    `json:"activeRayClusterName,omitempty"`
+	PendingRayClusterName string                    `json:"pendingRayClusterName,omitempty"`
+	// PreviousRayClusterName is the RayCluster that served the traffic before an upgrade.
+	PreviousRayClusterName string `json:"previousRayClusterName,omitempty"`
+}
+
+// NewJobData returns the data of a notification about rayJob.
+func NewJobData(rayJob *rayv1alpha1.RayJob) JobData {
+	return JobData{
+		JobId:               rayJob.Status.JobId,
+		JobStatus:           rayJob.Status.JobStatus,
+		JobDeploymentStatus: rayJob.Status.JobDeploymentStatus,
+		RayClusterName:      rayJob.Status.RayClusterName,
+		Message:             rayJob.Status.Message,
+		Reason:              rayJob.Status.Reason,
+		FailureType:         rayJob.Status.FailureType,
+		Failed:              rayJob.Status.Failed,
+		StartTime:           rayJob.Status.StartTime,
+		EndTime:             rayJob.Status.EndTime,
+	}
+}
+
+// NewEvent returns the CloudEvent notifying eventType for obj, a resource of the ray.io API such as rayjobs.
+// key identifies the occurrence of eventType, e.g. the job ID of a RayJob, so that a notification sent again after a
+// failed status update has the same ID and receivers can drop the duplicate.
+func NewEvent(resource string, obj metav1.Object, eventType rayv1alpha1.NotificationEvent, key string, data interface{}) Event {
+	return Event{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              fmt.Sprintf("%s-%s-%s", obj.GetUID(), eventType, key),
+		Source:          fmt.Sprintf("/apis/%s/namespaces/%s/%s/%s", rayv1alpha1.GroupVersion, obj.GetNamespace(), resource, obj.GetName()),
+		Type:            EventTypePrefix + string(eventType),
+		Subject:         obj.GetName(),
+		Time:            metav1.NewTime(time.Now().UTC()),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// Enabled returns whether spec subscribes to eventType.
+func Enabled(spec *rayv1alpha1.NotificationSpec, eventType rayv1alpha1.NotificationEvent) bool {
+	if spec == nil || spec.URL == "" {
+		return false
+	}
+	if len(spec.Events) == 0 {
+		return true
+	}
+	for _, e := range spec.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewNotifier returns the Notifier configured by spec for a resource in namespace, or nil if spec is nil.
+// The signing key is read from its Secret when the Notifier is created.
+func NewNotifier(ctx context.Context, cli client.Reader, namespace string, spec *rayv1alpha1.NotificationSpec) (Notifier, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	webhook := &Webhook{URL: spec.URL, MaxRetries: DefaultMaxRetries}
+	if spec.MaxRetries != nil {
+		webhook.MaxRetries = int(*spec.MaxRetries)
+	}
+	if spec.SigningSecret != nil {
+		secret := &corev1.Secret{}
+		if err := cli.Get(ctx, types.NamespacedName{Namespace: namespace, Name: spec.SigningSecret.Name}, secret); err != nil {
+			return nil, fmt.Errorf("failed to get the signing Secret %s: %v", spec.SigningSecret.Name, err)
+		}
+		key, ok := secret.Data[spec.SigningSecret.Key]
+		if !ok || len(key) == 0 {
+			return nil, fmt.Errorf("the signing Secret %s has no key %q", spec.SigningSecret.Name, spec.SigningSecret.Key)
+		}
+		webhook.Key = key
+	}
+	return webhook, nil
+}
+
+// Send notifies eventType for obj in the background if spec subscribes to it, so that a slow or unreachable
+// webhook never blocks a reconciliation. Notifications are best-effort: failures are reported as
+// NotificationFailed events on obj. The notification is delivered with ctx, so ctx must outlive the reconciliation,
+// like the context passed to Reconcile, which the manager cancels when it stops.
+func Send(ctx context.Context, cli client.Reader, recorder record.EventRecorder, spec *rayv1alpha1.NotificationSpec,
+	resource string, obj client.Object, eventType rayv1alpha1.NotificationEvent, key string, data interface{},
+) {
+	if !Enabled(spec, eventType) {
+		return
+	}
+	notifier, err := NewNotifier(ctx, cli, obj.GetNamespace(), spec)
+	if err != nil {
+		recorder.Eventf(obj, corev1.EventTypeWarning, "NotificationFailed", "Failed to notify %s: %v", eventType, err)
+		return
+	}
+	event := NewEvent(resource, obj, eventType, key, data)
+	// The caller keeps updating obj while the notification is delivered.
+	obj = obj.DeepCopyObject().(client.Object)
+	url := spec.URL
+	go func() {
+		if err := notifier.Notify(ctx, event); err != nil {
+			recorder.Eventf(obj, corev1.EventTypeWarning, "NotificationFailed", "Failed to notify %s to %s: %v", eventType, url, err)
+			return
+		}
+		recorder.Eventf(obj, corev1.EventTypeNormal, "Notified", "Notified %s to %s", eventType, url)
+	}()
+}
@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+)
+
+var rayJob = &rayv1alpha1.RayJob{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "rayjob-sample",
+		Namespace: "default",
+		UID:       "1",
+	},
+	Status: rayv1alpha1.RayJobStatus{
+		JobId:     "rayjob-sample-abcde",
+		JobStatus: rayv1alpha1.JobStatusSucceeded,
+	},
+}
+
+func init() {
+	InitialBackoff = time.Millisecond
+}
+
+func TestEnabled(t *testing.T) {
+	assert.False(t, Enabled(nil, rayv1alpha1.JobSucceeded))
+	assert.False(t, Enabled(&rayv1alpha1.NotificationSpec{}, rayv1alpha1.JobSucceeded))
+
+	spec := &rayv1alpha1.NotificationSpec{URL: "http://example.com"}
+	assert.True(t, Enabled(spec, rayv1alpha1.JobSucceeded))
+	assert.True(t, Enabled(spec, rayv1alpha1.ServiceRunning))
+
+	spec.Events = []rayv1alpha1.NotificationEvent{rayv1alpha1.JobFailed}
+	assert.False(t, Enabled(spec, rayv1alpha1.JobSucceeded))
+	assert.True(t, Enabled(spec, rayv1alpha1.JobFailed))
+}
+
+func TestWebhookNotify(t *testing.T) {
+	key := []byte("secret")
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, ContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, Sign(key, body), r.Header.Get(SignatureHeader))
+		assert.Nil(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Key: key}
+	event := NewEvent("rayjobs", rayJob, rayv1alpha1.JobSucceeded, "rayjob-sample-abcde", NewJobData(rayJob))
+	assert.Nil(t, webhook.Notify(context.Background(), event))
+
+	assert.Equal(t, CloudEventsSpecVersion, received.SpecVersion)
+	assert.Equal(t, "io.ray.kuberay.JobSucceeded", received.Type)
+	assert.Equal(t, "/apis/ray.io/v1alpha1/namespaces/default/rayjobs/rayjob-sample", received.Source)
+	assert.Equal(t, "rayjob-sample", received.Subject)
+	assert.Equal(t, "1-JobSucceeded-rayjob-sample-abcde", received.ID)
+	data := received.Data.(map[string]interface{})
+	assert.Equal(t, "rayjob-sample-abcde", data["jobId"])
+	assert.Equal(t, string(rayv1alpha1.JobStatusSucceeded), data["jobStatus"])
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	event := NewEvent("rayjobs", rayJob, rayv1alpha1.JobSucceeded, "rayjob-sample-abcde", nil)
+
+	// The notification succeeds on the third attempt.
+	webhook := &Webhook{URL: server.URL, MaxRetries: 3}
+	assert.Nil(t, webhook.Notify(context.Background(), event))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	// The retries are exhausted before the webhook recovers.
+	atomic.StoreInt32(&attempts, 0)
+	webhook.MaxRetries = 1
+	assert.NotNil(t, webhook.Notify(context.Background(), event))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestWebhookPermanentError(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, MaxRetries: 3}
+	assert.NotNil(t, webhook.Notify(context.Background(), NewEvent("rayjobs", rayJob, rayv1alpha1.JobSucceeded, "rayjob-sample-abcde", nil)))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestNewNotifier(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "default"},
+		Data:       map[string][]byte{"key": []byte("secret")},
+	}
+	cli := clientFake.NewClientBuilder().WithRuntimeObjects(secret).Build()
+	ctx := context.Background()
+
+	notifier, err := NewNotifier(ctx, cli, "default", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, notifier)
+
+	maxRetries := int32(5)
+	spec := &rayv1alpha1.NotificationSpec{URL: "http://example.com", MaxRetries: &maxRetries}
+	notifier, err = NewNotifier(ctx, cli, "default", spec)
+	assert.Nil(t, err)
+	assert.Equal(t, &Webhook{URL: "http://example.com", MaxRetries: 5}, notifier)
+
+	spec.SigningSecret = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"}, Key: "key"}
+	notifier, err = NewNotifier(ctx, cli, "default", spec)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("secret"), notifier.(*Webhook).Key)
+
+	spec.SigningSecret.Key = "missing"
+	_, err = NewNotifier(ctx, cli, "default", spec)
+	assert.NotNil(t, err)
+
+	_, err = NewNotifier(ctx, cli, "other", spec)
+	assert.NotNil(t, err)
+}
+
+func TestSend(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+	cli := clientFake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	recorder := record.NewFakeRecorder(10)
+	spec := &rayv1alpha1.NotificationSpec{URL: server.URL, Events: []rayv1alpha1.NotificationEvent{rayv1alpha1.JobSucceeded}}
+
+	// Events the spec doesn't subscribe to are not sent.
+	Send(context.Background(), cli, recorder, spec, "rayjobs", rayJob, rayv1alpha1.JobFailed, rayJob.Status.JobId, NewJobData(rayJob))
+	assert.Len(t, recorder.Events, 0)
+
+	Send(context.Background(), cli, recorder, spec, "rayjobs", rayJob, rayv1alpha1.JobSucceeded, rayJob.Status.JobId, NewJobData(rayJob))
+	select {
+	case event := <-received:
+		assert.Equal(t, "io.ray.kuberay.JobSucceeded", event.Type)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the notification was not received")
+	}
+	select {
+	case event := <-recorder.Events:
+		assert.Contains(t, event, "Notified")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the Notified event was not recorded")
+	}
+}
+
+func TestSendCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	cli := clientFake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	recorder := record.NewFakeRecorder(10)
+	maxRetries := int32(1000)
+	spec := &rayv1alpha1.NotificationSpec{URL: server.URL, MaxRetries: &maxRetries}
+
+	// The retries stop once the context is cancelled, e.g. when the manager stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	Send(ctx, cli, recorder, spec, "rayjobs", rayJob, rayv1alpha1.JobSucceeded, rayJob.Status.JobId, NewJobData(rayJob))
+	cancel()
+	select {
+	case event := <-recorder.Events:
+		assert.Contains(t, event, "NotificationFailed")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the notification was not cancelled")
+	}
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	// ContentType is the content type of a CloudEvent in structured mode.
+	ContentType = "application/cloudevents+json; charset=UTF-8"
+	// SignatureHeader holds the HMAC-SHA256 signature of the body as sha256=<hex digest>.
+	SignatureHeader = "X-KubeRay-Signature"
+)
+
+var (
+	// InitialBackoff is the delay before the first retry of a notification. It doubles after every retry.
+	InitialBackoff = 1 * time.Second
+	// RequestTimeout bounds each attempt to deliver a notification.
+	RequestTimeout = 10 * time.Second
+)
+
+// Webhook delivers the notifications in HTTP POSTs to a URL.
+type Webhook struct {
+	URL string
+	// Key signs the notifications with HMAC-SHA256 if it is set.
+	Key []byte
+	// MaxRetries is the number of times a notification is retried after the first attempt.
+	MaxRetries int
+	// Client defaults to an http.Client with RequestTimeout.
+	Client *http.Client
+}
+
+var _ Notifier = (*Webhook)(nil)
+
+// permanentError is a failure to deliver a notification that retrying won't fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (w *Webhook) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	backoff := InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err = w.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*permanentError); ok || attempt >= w.MaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) error {
+	cli := w.Client
+	if cli == nil {
+		cli = &http.Client{Timeout: RequestTimeout}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err: err}
+	}
+	req.Header.Set("Content-Type", ContentType)
+	if len(w.Key) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.Key, body))
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("webhook answered with status %s", resp.Status)
+	// Server errors and throttling are transient, other client errors are not.
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return &permanentError{err: err}
+}
+
+// Sign returns the value of the SignatureHeader for body signed with key.
+func Sign(key []byte, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
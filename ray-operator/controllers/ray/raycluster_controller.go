@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler"
+	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 
@@ -202,9 +203,19 @@ func (r *RayClusterReconciler) rayClusterReconcile(request ctrl.Request, instanc
 	r.Log.Info("reconciling RayCluster", "cluster name", request.Name)
 
 	if instance.DeletionTimestamp != nil && !instance.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(instance, common.RayClusterBatchSchedulerFinalizer) {
+			return ctrl.Result{}, r.cleanupBatchScheduling(instance)
+		}
 		r.Log.Info("RayCluster is being deleted, just ignore", "cluster name", request.Name)
 		return ctrl.Result{}, nil
 	}
+	if EnableBatchScheduler && isBatchScheduled(instance) && !controllerutil.ContainsFinalizer(instance, common.RayClusterBatchSchedulerFinalizer) {
+		r.Log.Info("Add a finalizer", "finalizer", common.RayClusterBatchSchedulerFinalizer)
+		controllerutil.AddFinalizer(instance, common.RayClusterBatchSchedulerFinalizer)
+		if err := r.Update(context.TODO(), instance); err != nil {
+			return ctrl.Result{RequeueAfter: DefaultRequeueDuration}, err
+		}
+	}
 	if err := r.reconcileAutoscalerServiceAccount(instance); err != nil {
 		if updateErr := r.updateClusterState(instance, rayiov1alpha1.Failed); updateErr != nil {
 			r.Log.Error(updateErr, "RayCluster update state error", "cluster name", request.Name)
@@ -393,6 +404,14 @@ func (r *RayClusterReconciler) reconcilePods(instance *rayiov1alpha1.RayCluster)
 			if err := scheduler.DoBatchSchedulingOnSubmission(instance); err != nil {
 				return err
 			}
+			admitted, err := r.syncBatchSchedulingStatus(instance, scheduler)
+			if err != nil {
+				return err
+			}
+			if !admitted {
+				r.Log.Info("reconcilePods", "gang is not admitted by the batch scheduler yet, skip creating pods", instance.Name)
+				return nil
+			}
 		} else {
 			return err
 		}
@@ -1137,6 +1156,70 @@ func (r *RayClusterReconciler) reconcileAutoscalerRoleBinding(instance *rayiov1a
 	return nil
 }
 
+// syncBatchSchedulingStatus records the scheduler-reported state of the gang in the RayCluster status and
+// emits an event when its phase changes. It returns whether the gang has been admitted, i.e. whether pods
+// may be created.
+func (r *RayClusterReconciler) syncBatchSchedulingStatus(instance *rayiov1alpha1.RayCluster, scheduler schedulerinterface.BatchScheduler) (bool, error) {
+	status, err := scheduler.GetSchedulingStatus(instance)
+	if err != nil {
+		return false, err
+	}
+	if status == nil {
+		instance.Status.BatchScheduling = nil
+		return true, nil
+	}
+
+	previous := instance.Status.BatchScheduling
+	if previous != nil && previous.Phase == status.Phase {
+		status.LastTransitionTime = previous.LastTransitionTime
+	} else {
+		now := metav1.Now()
+		status.LastTransitionTime = &now
+		switch status.Phase {
+		case rayiov1alpha1.BatchSchedulingPending:
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, string(rayiov1alpha1.GangPending),
+				"Waiting for %s to admit the cluster", status.SchedulerName)
+		case rayiov1alpha1.BatchSchedulingUnschedulable:
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, string(rayiov1alpha1.GangUnschedulable),
+				"%s cannot admit the cluster: %s %s", status.SchedulerName, status.Reason, status.Message)
+		case rayiov1alpha1.BatchSchedulingInqueue, rayiov1alpha1.BatchSchedulingRunning:
+			if previous == nil || !isGangAdmitted(previous) {
+				r.Recorder.Eventf(instance, corev1.EventTypeNormal, string(rayiov1alpha1.GangAdmitted),
+					"%s admitted the cluster", status.SchedulerName)
+			}
+		}
+	}
+	instance.Status.BatchScheduling = status
+	return isGangAdmitted(status), nil
+}
+
+// cleanupBatchScheduling releases the batch scheduler resources of a RayCluster being deleted and removes
+// the finalizer so that the deletion can proceed.
+func (r *RayClusterReconciler) cleanupBatchScheduling(instance *rayiov1alpha1.RayCluster) error {
+	if EnableBatchScheduler {
+		scheduler, err := r.BatchSchedulerMgr.GetSchedulerForCluster(instance)
+		if err != nil {
+			return err
+		}
+		if err := scheduler.CleanupOnDeletion(instance); err != nil {
+			return err
+		}
+	}
+	r.Log.Info("Remove the finalizer", "finalizer", common.RayClusterBatchSchedulerFinalizer)
+	controllerutil.RemoveFinalizer(instance, common.RayClusterBatchSchedulerFinalizer)
+	return r.Update(context.TODO(), instance)
+}
+
+func isBatchScheduled(instance *rayiov1alpha1.RayCluster) bool {
+	_, ok := instance.ObjectMeta.Labels[common.RaySchedulerName]
+	return ok
+}
+
+// isGangAdmitted returns false only while the scheduler is still holding the gang back.
+func isGangAdmitted(status *rayiov1alpha1.BatchSchedulingStatus) bool {
+	return status.Phase != rayiov1alpha1.BatchSchedulingPending && status.Phase != rayiov1alpha1.BatchSchedulingUnschedulable
+}
+
 func (r *RayClusterReconciler) updateClusterState(instance *rayiov1alpha1.RayCluster, clusterState rayiov1alpha1.ClusterState) error {
 	instance.Status.State = clusterState
 	return r.Status().Update(context.Background(), instance)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	schedulerinterface "github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler/interface"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
 
 	. "github.com/onsi/ginkgo"
@@ -964,6 +965,66 @@ func TestReconcile_UpdateClusterReason(t *testing.T) {
 	assert.Equal(t, cluster.Status.Reason, reason, "Cluster reason should be updated")
 }
 
+type fakeGangScheduler struct {
+	schedulerinterface.DefaultBatchScheduler
+	status *rayiov1alpha1.BatchSchedulingStatus
+}
+
+func (f *fakeGangScheduler) GetSchedulingStatus(app *rayiov1alpha1.RayCluster) (*rayiov1alpha1.BatchSchedulingStatus, error) {
+	return f.status.DeepCopy(), nil
+}
+
+func TestSyncBatchSchedulingStatus(t *testing.T) {
+	setupTest(t)
+	defer tearDown(t)
+
+	recorder := record.NewFakeRecorder(10)
+	testRayClusterReconciler := &RayClusterReconciler{
+		Recorder: recorder,
+		Scheme:   scheme.Scheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCluster"),
+	}
+
+	// The default scheduler does not gate pod creation.
+	admitted, err := testRayClusterReconciler.syncBatchSchedulingStatus(testRayCluster, &schedulerinterface.DefaultBatchScheduler{})
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+	assert.Nil(t, testRayCluster.Status.BatchScheduling)
+
+	scheduler := &fakeGangScheduler{status: &rayiov1alpha1.BatchSchedulingStatus{
+		SchedulerName: "fake",
+		Phase:         rayiov1alpha1.BatchSchedulingUnschedulable,
+		Reason:        "NotEnoughResources",
+		Message:       "queue default is full",
+	}}
+	admitted, err = testRayClusterReconciler.syncBatchSchedulingStatus(testRayCluster, scheduler)
+	assert.Nil(t, err)
+	assert.False(t, admitted, "pods must not be created while the gang is unschedulable")
+	assert.Equal(t, rayiov1alpha1.BatchSchedulingUnschedulable, testRayCluster.Status.BatchScheduling.Phase)
+	assert.Equal(t, "NotEnoughResources", testRayCluster.Status.BatchScheduling.Reason)
+	assert.NotNil(t, testRayCluster.Status.BatchScheduling.LastTransitionTime)
+	assert.Contains(t, <-recorder.Events, string(rayiov1alpha1.GangUnschedulable))
+
+	// The same phase again does not emit another event.
+	_, err = testRayClusterReconciler.syncBatchSchedulingStatus(testRayCluster, scheduler)
+	assert.Nil(t, err)
+	assert.Len(t, recorder.Events, 0)
+
+	scheduler.status.Phase = rayiov1alpha1.BatchSchedulingInqueue
+	admitted, err = testRayClusterReconciler.syncBatchSchedulingStatus(testRayCluster, scheduler)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+	assert.Equal(t, rayiov1alpha1.BatchSchedulingInqueue, testRayCluster.Status.BatchScheduling.Phase)
+	assert.Contains(t, <-recorder.Events, string(rayiov1alpha1.GangAdmitted))
+
+	// Moving from Inqueue to Running is not a new admission.
+	scheduler.status.Phase = rayiov1alpha1.BatchSchedulingRunning
+	admitted, err = testRayClusterReconciler.syncBatchSchedulingStatus(testRayCluster, scheduler)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+	assert.Len(t, recorder.Events, 0)
+}
+
 func TestUpdateEndpoints(t *testing.T) {
 	setupTest(t)
 	defer tearDown(t)
@@ -0,0 +1,322 @@
+package ray
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/cron"
+)
+
+// RayCronJobTooManyMissedRuns is the number of missed runs above which a warning is emitted. Only the most
+// recent missed run is started, like with Kubernetes CronJobs.
+const RayCronJobTooManyMissedRuns = 100
+
+// RayCronJobReconciler reconciles a RayCronJob object
+type RayCronJobReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Clock is the source of the current time, so that schedules can be tested with a fake clock.
+	Clock clock.Clock
+}
+
+// NewRayCronJobReconciler returns a new reconcile.Reconciler
+func NewRayCronJobReconciler(mgr manager.Manager) *RayCronJobReconciler {
+	return &RayCronJobReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCronJob"),
+		Recorder: mgr.GetEventRecorderFor("raycronjob-controller"),
+		Clock:    clock.RealClock{},
+	}
+}
+
+// +kubebuilder:rbac:groups=ray.io,resources=raycronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ray.io,resources=raycronjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ray.io,resources=raycronjobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ray.io,resources=rayjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// [WARNING]: There MUST be a newline after kubebuilder markers.
+// Reconcile creates the RayJobs of a RayCronJob on schedule, tracks the RayJobs that are still active and
+// deletes the finished RayJobs beyond the history limits.
+func (r *RayCronJobReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("reconciling RayCronJob", "NamespacedName", request.NamespacedName)
+
+	cronJob := &rayv1alpha1.RayCronJob{}
+	if err := r.Get(ctx, request.NamespacedName, cronJob); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !cronJob.DeletionTimestamp.IsZero() {
+		// The RayJobs are garbage collected through their owner references.
+		return ctrl.Result{}, nil
+	}
+
+	rayJobs, err := r.listRayJobs(ctx, cronJob)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	active, successful, failed := classifyRayJobs(rayJobs)
+	r.syncStatus(cronJob, active, successful)
+	if err := r.cleanupFinishedRayJobs(ctx, cronJob, successful, failed); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if cronJob.Spec.Suspend {
+		r.Log.Info("RayCronJob is suspended", "RayCronJob", request.NamespacedName)
+		return ctrl.Result{}, r.Status().Update(ctx, cronJob)
+	}
+
+	schedule, loc, err := parseSchedule(cronJob)
+	if err != nil {
+		// The schedule has to be fixed by the user, so there is no point in requeueing.
+		r.Recorder.Eventf(cronJob, corev1.EventTypeWarning, "InvalidSchedule", "Invalid schedule %q: %v", cronJob.Spec.Schedule, err)
+		return ctrl.Result{}, r.Status().Update(ctx, cronJob)
+	}
+
+	now := r.Clock.Now()
+	result := ctrl.Result{}
+	if next := schedule.Next(now.In(loc)); !next.IsZero() {
+		// Add a little time so that the reconciliation happens after the scheduled time.
+		result.RequeueAfter = next.Sub(now) + 100*time.Millisecond
+	}
+
+	scheduledTime, missedRuns := mostRecentScheduleTime(cronJob, schedule, loc, now)
+	if scheduledTime == nil {
+		return result, r.Status().Update(ctx, cronJob)
+	}
+	if missedRuns > RayCronJobTooManyMissedRuns {
+		r.Recorder.Eventf(cronJob, corev1.EventTypeWarning, "TooManyMissedTimes",
+			"Too many missed start times: %d. Check clock skew or set startingDeadlineSeconds", missedRuns)
+	}
+	if deadline := cronJob.Spec.StartingDeadlineSeconds; deadline != nil && scheduledTime.Add(time.Duration(*deadline)*time.Second).Before(now) {
+		r.Recorder.Eventf(cronJob, corev1.EventTypeWarning, "MissSchedule", "Missed scheduled time to start a RayJob: %s", scheduledTime.Format(time.RFC1123Z))
+		return result, r.Status().Update(ctx, cronJob)
+	}
+
+	switch cronJob.Spec.ConcurrencyPolicy {
+	case rayv1alpha1.ForbidConcurrent:
+		if len(active) > 0 {
+			r.Log.Info("Not starting a RayJob because a prior one is still running", "RayCronJob", request.NamespacedName)
+			r.Recorder.Eventf(cronJob, corev1.EventTypeNormal, "JobAlreadyActive", "Not starting a RayJob because a prior one is still running")
+			return result, r.Status().Update(ctx, cronJob)
+		}
+	case rayv1alpha1.ReplaceConcurrent:
+		for i := range active {
+			if err := r.Delete(ctx, &active[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(cronJob, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted RayJob %s", active[i].Name)
+		}
+		cronJob.Status.Active = nil
+	}
+
+	rayJob := buildRayJob(cronJob, *scheduledTime)
+	if err := ctrl.SetControllerReference(cronJob, rayJob, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, rayJob); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			r.Recorder.Eventf(cronJob, corev1.EventTypeWarning, "FailedCreate", "Error creating RayJob %s: %v", rayJob.Name, err)
+			return ctrl.Result{}, err
+		}
+		// The RayJob of this run was created by an earlier reconciliation whose status update failed.
+		r.Log.Info("RayJob already exists", "RayCronJob", request.NamespacedName, "RayJob", rayJob.Name)
+	} else {
+		r.Recorder.Eventf(cronJob, corev1.EventTypeNormal, "SuccessfulCreate", "Created RayJob %s", rayJob.Name)
+		cronJob.Status.Active = append(cronJob.Status.Active, rayJobReference(rayJob))
+	}
+	cronJob.Status.LastScheduleTime = &metav1.Time{Time: *scheduledTime}
+	return result, r.Status().Update(ctx, cronJob)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RayCronJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rayv1alpha1.RayCronJob{}).
+		Owns(&rayv1alpha1.RayJob{}).
+		Complete(r)
+}
+
+// listRayJobs returns the RayJobs created by the RayCronJob.
+func (r *RayCronJobReconciler) listRayJobs(ctx context.Context, cronJob *rayv1alpha1.RayCronJob) ([]rayv1alpha1.RayJob, error) {
+	rayJobList := rayv1alpha1.RayJobList{}
+	if err := r.List(ctx, &rayJobList, client.InNamespace(cronJob.Namespace), client.MatchingLabels{common.RayCronJobLabelKey: cronJob.Name}); err != nil {
+		return nil, err
+	}
+	rayJobs := make([]rayv1alpha1.RayJob, 0, len(rayJobList.Items))
+	for _, rayJob := range rayJobList.Items {
+		if metav1.IsControlledBy(&rayJob, cronJob) {
+			rayJobs = append(rayJobs, rayJob)
+		}
+	}
+	return rayJobs, nil
+}
+
+// syncStatus updates the active RayJobs and the last successful time in the status of the RayCronJob.
+func (r *RayCronJobReconciler) syncStatus(cronJob *rayv1alpha1.RayCronJob, active, successful []rayv1alpha1.RayJob) {
+	cronJob.Status.Active = nil
+	for i := range active {
+		cronJob.Status.Active = append(cronJob.Status.Active, rayJobReference(&active[i]))
+	}
+	for _, rayJob := range successful {
+		endTime := rayJob.Status.EndTime
+		if endTime == nil {
+			endTime = &rayJob.CreationTimestamp
+		}
+		if cronJob.Status.LastSuccessfulTime == nil || cronJob.Status.LastSuccessfulTime.Before(endTime) {
+			cronJob.Status.LastSuccessfulTime = endTime.DeepCopy()
+		}
+	}
+}
+
+// cleanupFinishedRayJobs deletes the oldest finished RayJobs beyond the history limits of the RayCronJob.
+func (r *RayCronJobReconciler) cleanupFinishedRayJobs(ctx context.Context, cronJob *rayv1alpha1.RayCronJob, successful, failed []rayv1alpha1.RayJob) error {
+	successfulLimit, failedLimit := int32(common.DefaultSuccessfulJobsHistoryLimit), int32(common.DefaultFailedJobsHistoryLimit)
+	if cronJob.Spec.SuccessfulJobsHistoryLimit != nil {
+		successfulLimit = *cronJob.Spec.SuccessfulJobsHistoryLimit
+	}
+	if cronJob.Spec.FailedJobsHistoryLimit != nil {
+		failedLimit = *cronJob.Spec.FailedJobsHistoryLimit
+	}
+	for _, history := range []struct {
+		rayJobs []rayv1alpha1.RayJob
+		limit   int32
+	}{{successful, successfulLimit}, {failed, failedLimit}} {
+		for _, rayJob := range oldestRayJobsBeyondLimit(history.rayJobs, history.limit) {
+			rayJob := rayJob
+			if err := r.Delete(ctx, &rayJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			r.Recorder.Eventf(cronJob, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted RayJob %s", rayJob.Name)
+		}
+	}
+	return nil
+}
+
+// classifyRayJobs splits the RayJobs of a RayCronJob into active, successful and failed ones.
+func classifyRayJobs(rayJobs []rayv1alpha1.RayJob) (active, successful, failed []rayv1alpha1.RayJob) {
+	for _, rayJob := range rayJobs {
+		switch {
+		case !rayJob.DeletionTimestamp.IsZero():
+			// A RayJob being deleted, e.g. replaced, is neither active nor part of the history.
+		case rayJob.Status.JobStatus == rayv1alpha1.JobStatusSucceeded:
+			successful = append(successful, rayJob)
+		case isRayJobFailed(&rayJob):
+			failed = append(failed, rayJob)
+		default:
+			active = append(active, rayJob)
+		}
+	}
+	return active, successful, failed
+}
+
+// isRayJobFailed returns whether the RayJob was stopped or failed for good, i.e. it is not going to be retried.
+func isRayJobFailed(rayJob *rayv1alpha1.RayJob) bool {
+	return rayJob.Status.JobStatus == rayv1alpha1.JobStatusStopped || isJobFailedForGood(rayJob)
+}
+
+// oldestRayJobsBeyondLimit returns the oldest RayJobs that exceed the history limit.
+func oldestRayJobsBeyondLimit(rayJobs []rayv1alpha1.RayJob, limit int32) []rayv1alpha1.RayJob {
+	if int32(len(rayJobs)) <= limit {
+		return nil
+	}
+	sorted := make([]rayv1alpha1.RayJob, len(rayJobs))
+	copy(sorted, rayJobs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp) ||
+			(sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) && sorted[i].Name < sorted[j].Name)
+	})
+	return sorted[:int32(len(sorted))-limit]
+}
+
+// parseSchedule parses the schedule of the RayCronJob and loads its time zone.
+func parseSchedule(cronJob *rayv1alpha1.RayCronJob) (*cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(cronJob.Spec.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.Local
+	if cronJob.Spec.TimeZone != nil {
+		if loc, err = time.LoadLocation(*cronJob.Spec.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("unknown time zone %q: %w", *cronJob.Spec.TimeZone, err)
+		}
+	}
+	return schedule, loc, nil
+}
+
+// mostRecentScheduleTime returns the most recent scheduled time of the RayCronJob that has not been run yet,
+// if any, along with the number of runs that were missed since the last one.
+func mostRecentScheduleTime(cronJob *rayv1alpha1.RayCronJob, schedule *cron.Schedule, loc *time.Location, now time.Time) (*time.Time, int) {
+	earliestTime := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastScheduleTime != nil {
+		earliestTime = cronJob.Status.LastScheduleTime.Time
+	}
+	if deadline := cronJob.Spec.StartingDeadlineSeconds; deadline != nil {
+		// Runs older than the deadline can't be started anyway.
+		if schedulingDeadline := now.Add(-time.Duration(*deadline) * time.Second); schedulingDeadline.After(earliestTime) {
+			earliestTime = schedulingDeadline
+		}
+	}
+
+	var mostRecent *time.Time
+	missedRuns := 0
+	for t := schedule.Next(earliestTime.In(loc)); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		t := t
+		mostRecent = &t
+		missedRuns++
+	}
+	return mostRecent, missedRuns
+}
+
+// buildRayJob builds the RayJob of the run of the RayCronJob scheduled at the given time. The name of the
+// RayJob is derived from the scheduled time, so that a run can't be created twice.
+func buildRayJob(cronJob *rayv1alpha1.RayCronJob, scheduledTime time.Time) *rayv1alpha1.RayJob {
+	template := cronJob.Spec.JobTemplate.DeepCopy()
+	labels := template.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[common.RayCronJobLabelKey] = cronJob.Name
+	annotations := template.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[common.RayCronJobScheduledTimeAnnotationKey] = scheduledTime.UTC().Format(time.RFC3339)
+
+	return &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", cronJob.Name, scheduledTime.Unix()/60),
+			Namespace:   cronJob.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: template.Spec,
+	}
+}
+
+func rayJobReference(rayJob *rayv1alpha1.RayJob) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: rayv1alpha1.GroupVersion.String(),
+		Kind:       "RayJob",
+		Namespace:  rayJob.Namespace,
+		Name:       rayJob.Name,
+		UID:        rayJob.UID,
+	}
+}
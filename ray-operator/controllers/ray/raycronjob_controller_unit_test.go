@@ -0,0 +1,250 @@
+package ray
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/cron"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestRayCronJob(creationTime time.Time) *rayv1alpha1.RayCronJob {
+	return &rayv1alpha1.RayCronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "raycronjob-sample",
+			Namespace:         "default",
+			UID:               types.UID("raycronjob-sample-uid"),
+			CreationTimestamp: metav1.NewTime(creationTime),
+		},
+		Spec: rayv1alpha1.RayCronJobSpec{
+			Schedule: "*/15 * * * *",
+			TimeZone: pointer.StringPtr("UTC"),
+			JobTemplate: rayv1alpha1.RayJobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"team": "features"},
+				},
+				Spec: rayv1alpha1.RayJobSpec{
+					Entrypoint: "python /home/ray/samples/sample_code.py",
+				},
+			},
+		},
+	}
+}
+
+func newTestRayCronJobReconciler(fakeClock *clocktesting.FakeClock, objects ...runtime.Object) *RayCronJobReconciler {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	return &RayCronJobReconciler{
+		Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(objects...).Build(),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayCronJob"),
+		Recorder: &record.FakeRecorder{},
+		Clock:    fakeClock,
+	}
+}
+
+func listTestRayJobs(t *testing.T, r *RayCronJobReconciler) []rayv1alpha1.RayJob {
+	rayJobs := rayv1alpha1.RayJobList{}
+	assert.Nil(t, r.List(context.Background(), &rayJobs, client.InNamespace("default")))
+	return rayJobs.Items
+}
+
+func TestMostRecentScheduleTime(t *testing.T) {
+	creationTime := time.Date(2023, time.March, 15, 10, 7, 0, 0, time.UTC)
+	cronJob := newTestRayCronJob(creationTime)
+	schedule, err := cron.Parse(cronJob.Spec.Schedule)
+	assert.Nil(t, err)
+
+	// No run is due before the first scheduled time.
+	scheduledTime, missedRuns := mostRecentScheduleTime(cronJob, schedule, time.UTC, creationTime.Add(5*time.Minute))
+	assert.Nil(t, scheduledTime)
+	assert.Equal(t, 0, missedRuns)
+
+	// Only the most recent of the missed runs is returned.
+	scheduledTime, missedRuns = mostRecentScheduleTime(cronJob, schedule, time.UTC, creationTime.Add(time.Hour))
+	assert.Equal(t, time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC), *scheduledTime)
+	assert.Equal(t, 4, missedRuns)
+
+	// Runs before the last scheduled time have already been run.
+	cronJob.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC)}
+	scheduledTime, _ = mostRecentScheduleTime(cronJob, schedule, time.UTC, creationTime.Add(time.Hour))
+	assert.Nil(t, scheduledTime)
+
+	// Runs older than startingDeadlineSeconds are not counted.
+	cronJob.Status.LastScheduleTime = nil
+	cronJob.Spec.StartingDeadlineSeconds = pointer.Int64Ptr(20 * 60)
+	scheduledTime, missedRuns = mostRecentScheduleTime(cronJob, schedule, time.UTC, creationTime.Add(time.Hour))
+	assert.Equal(t, time.Date(2023, time.March, 15, 11, 0, 0, 0, time.UTC), *scheduledTime)
+	assert.Equal(t, 1, missedRuns)
+}
+
+func TestRayCronJobReconcile(t *testing.T) {
+	creationTime := time.Date(2023, time.March, 15, 10, 7, 0, 0, time.UTC)
+	cronJob := newTestRayCronJob(creationTime)
+	cronJob.Spec.ConcurrencyPolicy = rayv1alpha1.ForbidConcurrent
+	fakeClock := clocktesting.NewFakeClock(creationTime.Add(3 * time.Minute))
+	r := newTestRayCronJobReconciler(fakeClock, cronJob)
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "raycronjob-sample"}}
+
+	// Nothing is due yet, so the reconciliation waits for the first scheduled time.
+	result, err := r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Minute+100*time.Millisecond, result.RequeueAfter)
+	assert.Empty(t, listTestRayJobs(t, r))
+
+	// A RayJob is created at the scheduled time.
+	fakeClock.SetTime(time.Date(2023, time.March, 15, 10, 15, 1, 0, time.UTC))
+	_, err = r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	rayJobs := listTestRayJobs(t, r)
+	assert.Len(t, rayJobs, 1)
+	rayJob := rayJobs[0]
+	assert.Equal(t, fmt.Sprintf("raycronjob-sample-%d", time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC).Unix()/60), rayJob.Name)
+	assert.Equal(t, "raycronjob-sample", rayJob.Labels[common.RayCronJobLabelKey])
+	assert.Equal(t, "features", rayJob.Labels["team"])
+	assert.Equal(t, "2023-03-15T10:15:00Z", rayJob.Annotations[common.RayCronJobScheduledTimeAnnotationKey])
+	assert.True(t, metav1.IsControlledBy(&rayJob, cronJob))
+	assert.Equal(t, cronJob.Spec.JobTemplate.Spec.Entrypoint, rayJob.Spec.Entrypoint)
+
+	assert.Nil(t, r.Get(ctx, request.NamespacedName, cronJob))
+	assert.Len(t, cronJob.Status.Active, 1)
+	assert.Equal(t, rayJob.Name, cronJob.Status.Active[0].Name)
+	assert.Equal(t, time.Date(2023, time.March, 15, 10, 15, 0, 0, time.UTC), cronJob.Status.LastScheduleTime.Time.UTC())
+
+	// With the Forbid policy, the next run is skipped while the RayJob is still active.
+	fakeClock.SetTime(time.Date(2023, time.March, 15, 10, 30, 1, 0, time.UTC))
+	_, err = r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	assert.Len(t, listTestRayJobs(t, r), 1)
+
+	// Once the RayJob succeeded, the next run starts.
+	endTime := metav1.NewTime(time.Date(2023, time.March, 15, 10, 35, 0, 0, time.UTC))
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusSucceeded
+	rayJob.Status.EndTime = &endTime
+	assert.Nil(t, r.Status().Update(ctx, &rayJob))
+	fakeClock.SetTime(time.Date(2023, time.March, 15, 10, 45, 1, 0, time.UTC))
+	_, err = r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	assert.Len(t, listTestRayJobs(t, r), 2)
+	assert.Nil(t, r.Get(ctx, request.NamespacedName, cronJob))
+	assert.Len(t, cronJob.Status.Active, 1)
+	assert.NotEqual(t, rayJob.Name, cronJob.Status.Active[0].Name)
+	assert.Equal(t, endTime.Time, cronJob.Status.LastSuccessfulTime.Time.UTC())
+
+	// With the Replace policy, the active RayJob is deleted and replaced.
+	cronJob.Spec.ConcurrencyPolicy = rayv1alpha1.ReplaceConcurrent
+	assert.Nil(t, r.Update(ctx, cronJob))
+	replaced := cronJob.Status.Active[0].Name
+	fakeClock.SetTime(time.Date(2023, time.March, 15, 11, 0, 1, 0, time.UTC))
+	_, err = r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	names := []string{}
+	for _, rayJob := range listTestRayJobs(t, r) {
+		names = append(names, rayJob.Name)
+	}
+	assert.Len(t, names, 2)
+	assert.Contains(t, names, rayJob.Name)
+	assert.NotContains(t, names, replaced)
+
+	// A suspended RayCronJob doesn't start new runs.
+	assert.Nil(t, r.Get(ctx, request.NamespacedName, cronJob))
+	cronJob.Spec.Suspend = true
+	assert.Nil(t, r.Update(ctx, cronJob))
+	fakeClock.SetTime(time.Date(2023, time.March, 15, 11, 15, 1, 0, time.UTC))
+	_, err = r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	assert.Len(t, listTestRayJobs(t, r), 2)
+}
+
+func TestRayCronJobInvalidSchedule(t *testing.T) {
+	creationTime := time.Date(2023, time.March, 15, 10, 7, 0, 0, time.UTC)
+	cronJob := newTestRayCronJob(creationTime)
+	cronJob.Spec.TimeZone = pointer.StringPtr("Nowhere/Atlantis")
+	r := newTestRayCronJobReconciler(clocktesting.NewFakeClock(creationTime.Add(time.Hour)), cronJob)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "raycronjob-sample"}})
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.Empty(t, listTestRayJobs(t, r))
+}
+
+func TestCleanupFinishedRayJobs(t *testing.T) {
+	creationTime := time.Date(2023, time.March, 15, 10, 7, 0, 0, time.UTC)
+	cronJob := newTestRayCronJob(creationTime)
+	objects := []runtime.Object{cronJob}
+	for i := 0; i < 8; i++ {
+		rayJob := buildRayJob(cronJob, creationTime.Add(time.Duration(i)*time.Hour))
+		rayJob.CreationTimestamp = metav1.NewTime(creationTime.Add(time.Duration(i) * time.Hour))
+		rayJob.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: rayv1alpha1.GroupVersion.String(),
+			Kind:       "RayCronJob",
+			Name:       cronJob.Name,
+			UID:        cronJob.UID,
+			Controller: pointer.BoolPtr(true),
+		}}
+		switch {
+		case i < 5:
+			rayJob.Status.JobStatus = rayv1alpha1.JobStatusSucceeded
+		case i < 7:
+			rayJob.Status.JobStatus = rayv1alpha1.JobStatusFailed
+		default:
+			rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+		}
+		objects = append(objects, rayJob)
+	}
+	r := newTestRayCronJobReconciler(clocktesting.NewFakeClock(creationTime.Add(10*time.Hour)), objects...)
+
+	ctx := context.Background()
+	rayJobs, err := r.listRayJobs(ctx, cronJob)
+	assert.Nil(t, err)
+	active, successful, failed := classifyRayJobs(rayJobs)
+	assert.Len(t, active, 1)
+	assert.Len(t, successful, 5)
+	assert.Len(t, failed, 2)
+
+	// The oldest finished RayJobs beyond the default history limits are deleted.
+	assert.Nil(t, r.cleanupFinishedRayJobs(ctx, cronJob, successful, failed))
+	rayJobs, err = r.listRayJobs(ctx, cronJob)
+	assert.Nil(t, err)
+	active, successful, failed = classifyRayJobs(rayJobs)
+	assert.Len(t, active, 1)
+	assert.Len(t, successful, common.DefaultSuccessfulJobsHistoryLimit)
+	assert.Len(t, failed, common.DefaultFailedJobsHistoryLimit)
+	for _, rayJob := range successful {
+		assert.False(t, rayJob.CreationTimestamp.Time.Before(creationTime.Add(2*time.Hour)))
+	}
+	assert.Equal(t, creationTime.Add(6*time.Hour), failed[0].CreationTimestamp.Time.UTC())
+}
+
+func TestIsRayJobFailed(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{Spec: rayv1alpha1.RayJobSpec{BackoffLimit: pointer.Int32Ptr(2)}}
+	assert.False(t, isRayJobFailed(rayJob))
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusStopped
+	assert.True(t, isRayJobFailed(rayJob))
+
+	// A failed Ray job is retried while retries are left, unless the reason is final.
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusFailed
+	assert.False(t, isRayJobFailed(rayJob))
+	for _, reason := range []rayv1alpha1.JobFailedReason{rayv1alpha1.DeadlineExceeded, rayv1alpha1.DashboardUnreachable, rayv1alpha1.InvalidSpec} {
+		rayJob.Status.Reason = reason
+		assert.True(t, isRayJobFailed(rayJob), reason)
+	}
+	rayJob.Status.Reason = rayv1alpha1.HeadPodLost
+	assert.False(t, isRayJobFailed(rayJob))
+	rayJob.Status.Failed = 2
+	assert.True(t, isRayJobFailed(rayJob))
+}
@@ -2,13 +2,19 @@ package ray
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/client-go/tools/record"
 
 	"github.com/go-logr/logr"
 	fmtErrors "github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +24,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/logsink"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/notify"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -30,6 +38,15 @@ import (
 const (
 	RayJobDefaultRequeueDuration    = 3 * time.Second
 	RayJobDefaultClusterSelectorKey = "ray.io/cluster"
+	// The backoff between two attempts of a RayJob doubles after every failure, from RayJobRetryBaseBackoff
+	// up to RayJobRetryMaxBackoff, like the backoff of Kubernetes Jobs.
+	RayJobRetryBaseBackoff = 10 * time.Second
+	RayJobRetryMaxBackoff  = 6 * time.Minute
+	// RayJobDefaultDashboardUnreachableThreshold is how long the dashboard may be unreachable while the Ray job
+	// is running before the Ray job is declared lost, unless dashboardUnreachableSecondThreshold is set.
+	RayJobDefaultDashboardUnreachableThreshold = 300 * time.Second
+	// The exit code of a process killed with SIGKILL, which is how the OOM killer ends it.
+	oomKilledExitCode = 137
 )
 
 // RayJobReconciler reconciles a RayJob object
@@ -62,6 +79,9 @@ func NewRayJobReconciler(mgr manager.Manager) *RayJobReconciler {
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;delete;update
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
 // [WARNING]: There MUST be a newline after kubebuilder markers.
 // Reconcile reads that state of a RayJob object and makes changes based on it
@@ -111,6 +131,14 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
+	// Wait for the backoff to elapse before the next attempt of a failed RayJob.
+	if rayJobInstance.Status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusRetrying {
+		if remaining := retryBackoffRemaining(rayJobInstance, time.Now()); remaining > 0 {
+			r.Log.Info("waiting for the retry backoff", "RayJob", rayJobInstance.Name, "remaining", remaining)
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
 	// Mark the deployment status as Complete if RayJob is succeed or failed
 	// TODO: (jiaxin.shan) Double check raycluster status to make sure we don't have create duplicate clusters..
 	// But the code here is not elegant. We should spend some time to refactor the flow.
@@ -139,6 +167,17 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		}
 	}
 
+	// A RayJob that exceeded its deadline, whose Ray job was lost with the dashboard, or whose spec is invalid, is
+	// cleaned up without waiting for its RayCluster or its dashboard.
+	if isFinalFailureReason(rayJobInstance.Status.Reason) && isJobSucceedOrFailed(rayJobInstance.Status.JobStatus) {
+		return r.shutdownAfterJobFinishes(ctx, rayJobInstance)
+	}
+	// Fail the RayJob once startupTimeoutSeconds or activeDeadlineSeconds is exceeded. This is checked before
+	// waiting for the RayCluster, since the RayCluster may never become ready.
+	if message := deadlineExceededMessage(rayJobInstance, time.Now()); message != "" {
+		return r.failOnDeadlineExceeded(ctx, rayJobInstance, message)
+	}
+
 	// Set rayClusterName and rayJobId first, to avoid duplicate submission
 	err = r.setRayJobIdAndRayClusterNameIfNeed(ctx, rayJobInstance)
 	if err != nil {
@@ -148,6 +187,12 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 
 	var rayClusterInstance *rayv1alpha1.RayCluster
 	if rayClusterInstance, err = r.getOrCreateRayClusterInstance(ctx, rayJobInstance); err != nil {
+		if _, ok := err.(*rayJobValidationError); ok {
+			return r.failOnInvalidSpec(ctx, rayJobInstance, err)
+		}
+		if shouldRetry(rayJobInstance, rayv1alpha1.ClusterFailed) {
+			return r.retryRayJob(ctx, rayJobInstance, rayv1alpha1.ClusterFailed, err.Error())
+		}
 		err = r.updateState(ctx, rayJobInstance, nil, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusFailedToGetOrCreateRayCluster, err)
 		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
 	}
@@ -169,24 +214,39 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 	// Always update RayClusterStatus along with jobStatus and jobDeploymentStatus updates.
 	rayJobInstance.Status.RayClusterStatus = rayClusterInstance.Status
 
-	clientURL := rayJobInstance.Status.DashboardURL
-	if clientURL == "" {
-		// TODO: dashboard service may be changed. Check it instead of using the same URL always
-		if clientURL, err = utils.FetchDashboardURL(ctx, &r.Log, r.Client, rayClusterInstance); err != nil || clientURL == "" {
-			if clientURL == "" {
-				err = fmt.Errorf("empty dashboardURL")
-			}
-			err = r.updateState(ctx, rayJobInstance, nil, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusWaitForDashboard, err)
-			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	// The head service may be recreated with another dashboard port, or the RayJob may switch to another
+	// RayCluster, so the dashboard URL is resolved on every reconciliation instead of being cached.
+	clientURL, err := utils.FetchDashboardURL(ctx, &r.Log, r.Client, rayClusterInstance)
+	if err != nil || clientURL == "" {
+		if clientURL == "" && err == nil {
+			err = fmt.Errorf("empty dashboardURL")
+		}
+		if isJobPendingOrRunning(rayJobInstance.Status.JobStatus) {
+			return r.handleDashboardUnreachable(ctx, rayJobInstance, err)
+		}
+		err = r.updateState(ctx, rayJobInstance, nil, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusWaitForDashboard, err)
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	if clientURL != rayJobInstance.Status.DashboardURL {
+		if rayJobInstance.Status.DashboardURL != "" {
+			r.Log.Info("The dashboard URL changed", "RayJob", rayJobInstance.Name, "old", rayJobInstance.Status.DashboardURL, "new", clientURL)
+			r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "DashboardURLChanged", "Dashboard URL changed from %s to %s",
+				rayJobInstance.Status.DashboardURL, clientURL)
 		}
 		rayJobInstance.Status.DashboardURL = clientURL
+		// updateState skips the update while the job status doesn't change, and the saved URL is the one used to
+		// stop the Ray job when the RayJob is deleted, so the new URL is saved right away.
+		if err := r.Status().Update(ctx, rayJobInstance); err != nil {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
 	}
 
 	rayDashboardClient := utils.GetRayDashboardClientFunc()
 	rayDashboardClient.InitClient(clientURL)
 
-	// Check the current status of ray cluster before submitting.
-	if rayClusterInstance.Status.State != rayv1alpha1.Ready {
+	// Check the current status of ray cluster before submitting. Once the Ray job is submitted, its status is
+	// checked from the dashboard, which tells whether it was lost with the RayCluster.
+	if rayClusterInstance.Status.State != rayv1alpha1.Ready && !isJobPendingOrRunning(rayJobInstance.Status.JobStatus) {
 		r.Log.Info("waiting for the cluster to be ready", "rayCluster", rayClusterInstance.Name)
 		err = r.updateState(ctx, rayJobInstance, nil, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusInitializing, nil)
 		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
@@ -195,17 +255,61 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 	// Check the current status of ray jobs before submitting.
 	jobInfo, err := rayDashboardClient.GetJobInfo(ctx, rayJobInstance.Status.JobId)
 	if err != nil {
+		if isJobPendingOrRunning(rayJobInstance.Status.JobStatus) {
+			return r.handleDashboardUnreachable(ctx, rayJobInstance, err)
+		}
 		err = r.updateState(ctx, rayJobInstance, jobInfo, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusFailedToGetJobStatus, err)
 		// Dashboard service in head pod takes time to start, it's possible we get connection refused error.
 		// Requeue after few seconds to avoid continuous connection errors.
 		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
 	}
+	if rayJobInstance.Status.DashboardUnreachableSince != nil || rayJobInstance.Status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusFailedToGetJobStatus {
+		if err := r.markDashboardReachable(ctx, rayJobInstance); err != nil {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+	}
 
 	r.Log.V(1).Info("RayJob information", "RayJob", rayJobInstance.Name, "jobInfo", jobInfo, "rayJobInstance", rayJobInstance.Status.JobStatus)
+	if jobInfo == nil && isJobLost(rayJobInstance) && shouldRetry(rayJobInstance, rayv1alpha1.HeadPodLost) {
+		return r.retryRayJob(ctx, rayJobInstance, rayv1alpha1.HeadPodLost,
+			fmt.Sprintf("Job %s is not found in RayCluster %s", rayJobInstance.Status.JobId, rayClusterInstance.Name))
+	}
+	if jobInfo == nil && rayJobInstance.Spec.ExternalJobId != "" {
+		err = fmt.Errorf("external job %s is not found in RayCluster %s", rayJobInstance.Spec.ExternalJobId, rayClusterInstance.Name)
+		err = r.updateState(ctx, rayJobInstance, jobInfo, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusFailedJobDeploy, err)
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	if jobInfo == nil && rayJobInstance.Status.JobStatus == "" {
+		// Wait for a free slot if the RayCluster limits the number of RayJobs running on it.
+		admitted, err := r.admitRayJob(ctx, rayJobInstance, rayClusterInstance)
+		if err != nil || !admitted {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+	}
+	if jobInfo == nil {
+		r.detectUntrackedJobs(ctx, rayJobInstance, rayDashboardClient)
+	}
+	if jobInfo == nil && common.IsK8sJobMode(rayJobInstance) {
+		// In K8sJobMode, the submitter Kubernetes Job submits the Ray job. Wait until the Ray job shows up
+		// in the dashboard, and keep reconciling its status from the dashboard afterwards.
+		if err := r.createK8sJobIfNeed(ctx, rayJobInstance, rayClusterInstance); err != nil {
+			r.Log.Error(err, "failed to create the submitter Kubernetes Job")
+			err = r.updateState(ctx, rayJobInstance, jobInfo, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusFailedJobDeploy, err)
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+		err = r.updateState(ctx, rayJobInstance, jobInfo, rayv1alpha1.JobStatusPending, rayv1alpha1.JobDeploymentStatusRunning, nil)
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
 	if jobInfo == nil {
 		// Submit the job if no id set
 		jobId, err := rayDashboardClient.SubmitJob(ctx, rayJobInstance, &r.Log)
 		if err != nil {
+			// The dashboard may already know the submission ID, e.g. if the status update after a previous
+			// submission was lost or the job was submitted out of band. Track that job if it is the same job.
+			if adoptedJobInfo := r.adoptRayJob(ctx, rayJobInstance, rayDashboardClient); adoptedJobInfo != nil {
+				err = r.updateState(ctx, rayJobInstance, adoptedJobInfo, adoptedJobInfo.JobStatus, rayv1alpha1.JobDeploymentStatusRunning, nil)
+				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			}
 			r.Log.Error(err, "failed to submit job")
 			err = r.updateState(ctx, rayJobInstance, jobInfo, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusFailedJobDeploy, err)
 			return ctrl.Result{}, err
@@ -224,6 +328,7 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 	// Update RayJob.Status (Kubernetes CR) from Ray Job Status from Dashboard service
 	if jobInfo.JobStatus != rayJobInstance.Status.JobStatus {
 		r.Log.Info(fmt.Sprintf("Update status from %s to %s", rayJobInstance.Status.JobStatus, jobInfo.JobStatus), "rayjob", rayJobInstance.Status.JobId)
+		r.detectUntrackedJobs(ctx, rayJobInstance, rayDashboardClient)
 		err = r.updateState(ctx, rayJobInstance, jobInfo, jobInfo.JobStatus, rayv1alpha1.JobDeploymentStatusRunning, nil)
 		return ctrl.Result{}, err
 	}
@@ -251,6 +356,11 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 			if err != nil && !errors.IsNotFound(err) {
 				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
 			}
+			// The submitter Kubernetes Job refers to the Ray job that was just stopped. Delete it so that
+			// a new one is created for the next submission.
+			if err = r.deleteK8sJob(ctx, rayJobInstance); err != nil {
+				return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+			}
 			// Since RayCluster instance is gone, remove it status also
 			// on RayJob resource
 			rayJobInstance.Status.RayClusterStatus = rayv1alpha1.RayClusterStatus{}
@@ -272,27 +382,114 @@ func (r *RayJobReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		}
 	}
 
+	// Persist the driver logs before the RayCluster, and the logs with it, may be deleted.
+	if isJobSucceedOrFailed(rayJobInstance.Status.JobStatus) && rayJobInstance.Status.DriverLogs == nil {
+		rayJobInstance.Status.DriverLogs = r.persistDriverLogs(ctx, rayJobInstance, rayDashboardClient)
+		if rayJobInstance.Status.JobStatus == rayv1alpha1.JobStatusFailed {
+			rayJobInstance.Status.Reason = rayv1alpha1.AppFailed
+		}
+		if err := r.Status().Update(ctx, rayJobInstance); err != nil {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+	}
+
+	if rayJobInstance.Status.JobStatus == rayv1alpha1.JobStatusFailed && shouldRetry(rayJobInstance, rayv1alpha1.AppFailed) {
+		return r.retryRayJob(ctx, rayJobInstance, rayv1alpha1.AppFailed, rayJobInstance.Status.Message)
+	}
+
 	// Let's use rayJobInstance.Status.JobStatus to make sure we only delete cluster after the CR is updated.
 	if isJobSucceedOrFailed(rayJobInstance.Status.JobStatus) && rayJobInstance.Status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusRunning {
-		if rayJobInstance.Spec.ShutdownAfterJobFinishes {
-			if rayJobInstance.Spec.TTLSecondsAfterFinished != nil {
-				r.Log.V(3).Info("TTLSecondsAfterSetting", "end_time", rayJobInstance.Status.EndTime.Time, "now", time.Now(), "ttl", *rayJobInstance.Spec.TTLSecondsAfterFinished)
-				ttlDuration := time.Duration(*rayJobInstance.Spec.TTLSecondsAfterFinished) * time.Second
-				if rayJobInstance.Status.EndTime.Time.Add(ttlDuration).After(time.Now()) {
-					// time.Until prints duration until target time. We add additional 2 seconds to make sure we have buffer and requeueAfter is not 0.
-					delta := int32(time.Until(rayJobInstance.Status.EndTime.Time.Add(ttlDuration).Add(2 * time.Second)).Seconds())
-					r.Log.Info("TTLSecondsAfterFinish not reached, requeue it after", "RayJob", rayJobInstance.Name, "time(s)", delta)
-					return ctrl.Result{RequeueAfter: time.Duration(delta) * time.Second}, nil
-				}
-			}
-			r.Log.Info("shutdownAfterJobFinishes set to true, we will delete cluster",
-				"RayJob", rayJobInstance.Name, "clusterName", fmt.Sprintf("%s/%s", rayJobInstance.Namespace, rayJobInstance.Status.RayClusterName))
-			return r.deleteCluster(ctx, rayJobInstance)
+		return r.shutdownAfterJobFinishes(ctx, rayJobInstance)
+	}
+	return ctrl.Result{}, nil
+}
+
+// shutdownAfterJobFinishes applies the deletion rule of a finished RayJob once its TTL has passed. Without a
+// deletionPolicy, the RayCluster is deleted after TTLSecondsAfterFinished if ShutdownAfterJobFinishes is set.
+// A RayCluster selected by clusterSelector is never deleted or scaled down.
+func (r *RayJobReconciler) shutdownAfterJobFinishes(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) (ctrl.Result, error) {
+	rule := getDeletionRule(rayJobInstance)
+	if rule == nil {
+		return ctrl.Result{}, nil
+	}
+	if rule.TTLSecondsAfterFinished != nil {
+		r.Log.V(3).Info("TTLSecondsAfterSetting", "end_time", rayJobInstance.Status.EndTime.Time, "now", time.Now(), "ttl", *rule.TTLSecondsAfterFinished)
+		ttlDuration := time.Duration(*rule.TTLSecondsAfterFinished) * time.Second
+		if rayJobInstance.Status.EndTime.Time.Add(ttlDuration).After(time.Now()) {
+			// time.Until prints duration until target time. We add additional 2 seconds to make sure we have buffer and requeueAfter is not 0.
+			delta := int32(time.Until(rayJobInstance.Status.EndTime.Time.Add(ttlDuration).Add(2 * time.Second)).Seconds())
+			r.Log.Info("TTLSecondsAfterFinish not reached, requeue it after", "RayJob", rayJobInstance.Name, "time(s)", delta)
+			return ctrl.Result{RequeueAfter: time.Duration(delta) * time.Second}, nil
 		}
 	}
+
+	policy := rule.Policy
+	if len(rayJobInstance.Spec.ClusterSelector) != 0 && (policy == rayv1alpha1.DeleteCluster || policy == rayv1alpha1.DeleteWorkers) {
+		policy = rayv1alpha1.DeleteNone
+	}
+	r.Log.Info("Apply the deletion policy", "RayJob", rayJobInstance.Name, "policy", policy,
+		"clusterName", fmt.Sprintf("%s/%s", rayJobInstance.Namespace, rayJobInstance.Status.RayClusterName))
+	switch policy {
+	case rayv1alpha1.DeleteCluster:
+		return r.deleteCluster(ctx, rayJobInstance)
+	case rayv1alpha1.DeleteWorkers:
+		if err := r.deleteWorkers(ctx, rayJobInstance); err != nil {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+	case rayv1alpha1.DeleteSelf:
+		if err := r.Delete(ctx, rayJobInstance); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Deleted", "Deleted RayJob %s", rayJobInstance.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// The RayCluster is kept, so stop reconciling the RayJob, which would otherwise restore the RayCluster spec.
+	if err := r.updateState(ctx, rayJobInstance, nil, rayJobInstance.Status.JobStatus, rayv1alpha1.JobDeploymentStatusComplete, nil); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// getDeletionRule returns the deletion rule for the outcome of a finished RayJob, or nil if its resources are left
+// untouched and the RayJob keeps being reconciled.
+func getDeletionRule(rayJob *rayv1alpha1.RayJob) *rayv1alpha1.DeletionRule {
+	if rayJob.Spec.DeletionPolicy != nil {
+		if rayJob.Status.JobStatus == rayv1alpha1.JobStatusSucceeded {
+			return &rayJob.Spec.DeletionPolicy.OnSuccess
+		}
+		return &rayJob.Spec.DeletionPolicy.OnFailure
+	}
+	if !rayJob.Spec.ShutdownAfterJobFinishes || len(rayJob.Spec.ClusterSelector) != 0 {
+		return nil
+	}
+	return &rayv1alpha1.DeletionRule{
+		Policy:                  rayv1alpha1.DeleteCluster,
+		TTLSecondsAfterFinished: rayJob.Spec.TTLSecondsAfterFinished,
+	}
+}
+
+// deleteWorkers scales all the worker groups of the RayCluster of a RayJob to zero, keeping its head. maxReplicas is
+// zeroed too, so that the Ray autoscaler does not scale the worker groups up again.
+func (r *RayJobReconciler) deleteWorkers(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) error {
+	cluster := &rayv1alpha1.RayCluster{}
+	namespacedName := types.NamespacedName{Namespace: rayJobInstance.Namespace, Name: rayJobInstance.Status.RayClusterName}
+	if err := r.Get(ctx, namespacedName, cluster); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	zero := int32(0)
+	for i := range cluster.Spec.WorkerGroupSpecs {
+		cluster.Spec.WorkerGroupSpecs[i].Replicas = &zero
+		cluster.Spec.WorkerGroupSpecs[i].MinReplicas = &zero
+		cluster.Spec.WorkerGroupSpecs[i].MaxReplicas = &zero
+	}
+	if err := r.Update(ctx, cluster); err != nil {
+		return err
+	}
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "DeletedWorkers", "Scaled the worker groups of cluster %s to zero", cluster.Name)
+	return nil
+}
+
 func (r *RayJobReconciler) deleteCluster(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) (reconcile.Result, error) {
 	clusterIdentifier := types.NamespacedName{
 		Name:      rayJobInstance.Status.RayClusterName,
@@ -320,6 +517,529 @@ func (r *RayJobReconciler) deleteCluster(ctx context.Context, rayJobInstance *ra
 	return ctrl.Result{}, nil
 }
 
+// createK8sJobIfNeed creates the submitter Kubernetes Job of a RayJob in K8sJobMode if it does not exist yet.
+// It returns an error if the submitter Kubernetes Job has failed, since the Ray job was never submitted.
+func (r *RayJobReconciler) createK8sJobIfNeed(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, rayClusterInstance *rayv1alpha1.RayCluster) error {
+	job := &batchv1.Job{}
+	namespacedName := types.NamespacedName{Namespace: rayJobInstance.Namespace, Name: rayJobInstance.Name}
+	if err := r.Get(ctx, namespacedName, job); err == nil {
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				return fmt.Errorf("submitter Kubernetes Job %s failed: %s", job.Name, condition.Message)
+			}
+		}
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	job, err := common.BuildSubmitterJob(rayJobInstance, rayClusterInstance)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(rayJobInstance, job, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	r.Log.Info("Submitter Kubernetes Job created", "RayJob", rayJobInstance.Name, "jobId", rayJobInstance.Status.JobId)
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Created", "Created Kubernetes Job %s to submit Job %s", job.Name, rayJobInstance.Status.JobId)
+	return nil
+}
+
+// deleteK8sJob deletes the submitter Kubernetes Job of a RayJob in K8sJobMode, together with its pods.
+func (r *RayJobReconciler) deleteK8sJob(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) error {
+	if !common.IsK8sJobMode(rayJobInstance) {
+		return nil
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: rayJobInstance.Name, Namespace: rayJobInstance.Namespace},
+	}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	r.Log.Info("Submitter Kubernetes Job deleted", "RayJob", rayJobInstance.Name)
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Deleted", "Deleted Kubernetes Job %s", job.Name)
+	return nil
+}
+
+// persistDriverLogs fetches the driver logs of a finished Ray job, writes them to the configured sink and
+// returns the status to record. It is best-effort: failures are reported in the status and in events, but
+// never block the rest of the reconciliation.
+func (r *RayJobReconciler) persistDriverLogs(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, rayDashboardClient utils.RayDashboardClientInterface) *rayv1alpha1.DriverLogsStatus {
+	status := &rayv1alpha1.DriverLogsStatus{}
+	logs, err := rayDashboardClient.GetJobLog(ctx, rayJobInstance.Status.JobId, &r.Log)
+	if err != nil || logs == nil {
+		if err == nil {
+			err = fmt.Errorf("job %s not found", rayJobInstance.Status.JobId)
+		}
+		status.Message = fmt.Sprintf("failed to get the driver logs: %v", err)
+		r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, "DriverLogsNotPersisted", status.Message)
+		return status
+	}
+
+	tailLines := logsink.DefaultTailLines
+	var sinkSpec *rayv1alpha1.DriverLogsSink
+	if rayJobInstance.Spec.DriverLogs != nil {
+		if rayJobInstance.Spec.DriverLogs.TailLines != nil {
+			tailLines = int(*rayJobInstance.Spec.DriverLogs.TailLines)
+		}
+		sinkSpec = rayJobInstance.Spec.DriverLogs.Sink
+	}
+	status.Tail = logsink.Tail(*logs, tailLines)
+
+	sink, err := logsink.NewSink(r.Client, r.Scheme, sinkSpec)
+	if err == nil && sink != nil {
+		status.Location, err = sink.Write(ctx, rayJobInstance, *logs)
+	}
+	if err != nil {
+		status.Message = fmt.Sprintf("failed to store the driver logs: %v", err)
+		r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, "DriverLogsNotPersisted", status.Message)
+	} else if status.Location != "" {
+		r.Log.Info("Driver logs persisted", "RayJob", rayJobInstance.Name, "location", status.Location)
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "DriverLogsPersisted", "Stored the driver logs of Job %s in %s", rayJobInstance.Status.JobId, status.Location)
+	}
+	return status
+}
+
+// shouldRetry returns whether an attempt of the RayJob that failed for the given reason should be retried.
+func shouldRetry(rayJob *rayv1alpha1.RayJob, reason rayv1alpha1.JobFailedReason) bool {
+	// An external job can't be submitted again.
+	if rayJob.Spec.ExternalJobId != "" {
+		return false
+	}
+	if rayJob.Spec.BackoffLimit == nil || rayJob.Status.Failed >= *rayJob.Spec.BackoffLimit {
+		return false
+	}
+	if rayJob.Spec.RetryPolicy == rayv1alpha1.RetryOnInfraFailure && reason == rayv1alpha1.AppFailed {
+		return false
+	}
+	return true
+}
+
+// isFinalFailureReason returns whether a RayJob failed with the reason is never retried: deadlines, lost Ray jobs
+// and invalid specs.
+func isFinalFailureReason(reason rayv1alpha1.JobFailedReason) bool {
+	return reason == rayv1alpha1.DeadlineExceeded || reason == rayv1alpha1.DashboardUnreachable || reason == rayv1alpha1.InvalidSpec
+}
+
+// isJobFailedForGood returns whether the Ray job of the RayJob failed and won't be retried. Any failure without a
+// final reason is an application failure.
+func isJobFailedForGood(rayJob *rayv1alpha1.RayJob) bool {
+	return rayJob.Status.JobStatus == rayv1alpha1.JobStatusFailed &&
+		(isFinalFailureReason(rayJob.Status.Reason) || !shouldRetry(rayJob, rayv1alpha1.AppFailed))
+}
+
+// retryBackoff returns the backoff before the next attempt of a RayJob that has failed `failed` times.
+func retryBackoff(failed int32) time.Duration {
+	backoff := RayJobRetryBaseBackoff
+	for i := int32(1); i < failed; i++ {
+		backoff *= 2
+		if backoff >= RayJobRetryMaxBackoff {
+			return RayJobRetryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// retryBackoffRemaining returns how long to wait before the next attempt of a RayJob in the Retrying state.
+func retryBackoffRemaining(rayJob *rayv1alpha1.RayJob, now time.Time) time.Duration {
+	attempts := rayJob.Status.Attempts
+	if len(attempts) == 0 || attempts[len(attempts)-1].EndTime == nil {
+		return 0
+	}
+	return attempts[len(attempts)-1].EndTime.Add(retryBackoff(rayJob.Status.Failed)).Sub(now)
+}
+
+// isJobLost returns whether the Ray job of the current attempt was submitted but is no longer known by the
+// RayCluster, which happens when the head pod is lost.
+func isJobLost(rayJob *rayv1alpha1.RayJob) bool {
+	if rayJob.Status.JobDeploymentStatus != rayv1alpha1.JobDeploymentStatusRunning {
+		return false
+	}
+	if common.IsK8sJobMode(rayJob) {
+		// In K8sJobMode, the job is PENDING until the submitter has submitted it.
+		return rayJob.Status.JobStatus == rayv1alpha1.JobStatusRunning
+	}
+	return isJobPendingOrRunning(rayJob.Status.JobStatus)
+}
+
+// deadlineExceededMessage returns why the current attempt of the RayJob exceeded startupTimeoutSeconds or
+// activeDeadlineSeconds at the given time, or an empty string if it did not.
+func deadlineExceededMessage(rayJob *rayv1alpha1.RayJob, now time.Time) string {
+	status := rayJob.Status
+	if isJobSucceedOrFailed(status.JobStatus) || status.JobStatus == rayv1alpha1.JobStatusStopped {
+		return ""
+	}
+	switch status.JobDeploymentStatus {
+	case rayv1alpha1.JobDeploymentStatusSuspended, rayv1alpha1.JobDeploymentStatusQueued, rayv1alpha1.JobDeploymentStatusRetrying,
+		rayv1alpha1.JobDeploymentStatusComplete:
+		return ""
+	}
+
+	if status.StartTime == nil {
+		if rayJob.Spec.StartupTimeoutSeconds == nil || status.AttemptStartTime == nil {
+			return ""
+		}
+		timeout := time.Duration(*rayJob.Spec.StartupTimeoutSeconds) * time.Second
+		if now.After(status.AttemptStartTime.Add(timeout)) {
+			return fmt.Sprintf("Job did not start within startupTimeoutSeconds (%ds)", *rayJob.Spec.StartupTimeoutSeconds)
+		}
+		return ""
+	}
+
+	if rayJob.Spec.ActiveDeadlineSeconds != nil {
+		deadline := time.Duration(*rayJob.Spec.ActiveDeadlineSeconds) * time.Second
+		if now.After(status.StartTime.Add(deadline)) {
+			return fmt.Sprintf("Job %s did not finish within activeDeadlineSeconds (%ds)", status.JobId, *rayJob.Spec.ActiveDeadlineSeconds)
+		}
+	}
+	return ""
+}
+
+// failOnDeadlineExceeded stops the Ray job of a RayJob that exceeded its deadline and marks the RayJob as failed.
+// The deletion rule is then applied as for any finished RayJob, which completes the RayJob. Without a deletion rule,
+// the RayJob is complete right away.
+func (r *RayJobReconciler) failOnDeadlineExceeded(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, message string) (ctrl.Result, error) {
+	r.Log.Info("RayJob exceeded its deadline", "RayJob", rayJobInstance.Name, "message", message)
+	status := &rayJobInstance.Status
+	if isJobPendingOrRunning(status.JobStatus) && status.DashboardURL != "" && status.JobId != "" {
+		rayDashboardClient := utils.GetRayDashboardClientFunc()
+		rayDashboardClient.InitClient(status.DashboardURL)
+		if err := rayDashboardClient.StopJob(ctx, status.JobId, &r.Log); err != nil {
+			r.Log.Info("Failed to stop job", "error", err)
+		}
+		if status.DriverLogs == nil {
+			status.DriverLogs = r.persistDriverLogs(ctx, rayJobInstance, rayDashboardClient)
+		}
+	}
+	if err := r.deleteK8sJob(ctx, rayJobInstance); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, string(rayv1alpha1.DeadlineExceeded), message)
+
+	now := metav1.Now()
+	status.Reason = rayv1alpha1.DeadlineExceeded
+	status.Message = message
+	status.EndTime = &now
+	jobDeploymentStatus := status.JobDeploymentStatus
+	if getDeletionRule(rayJobInstance) == nil {
+		jobDeploymentStatus = rayv1alpha1.JobDeploymentStatusComplete
+	}
+	if err := r.updateState(ctx, rayJobInstance, nil, rayv1alpha1.JobStatusFailed, jobDeploymentStatus, nil); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	return r.shutdownAfterJobFinishes(ctx, rayJobInstance)
+}
+
+// failOnInvalidSpec fails the RayJob with the InvalidSpec reason. Another attempt won't fix the spec, so the RayJob
+// is not retried.
+func (r *RayJobReconciler) failOnInvalidSpec(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, specErr error) (ctrl.Result, error) {
+	r.Log.Info("invalid RayJob spec", "RayJob", rayJobInstance.Name, "error", specErr)
+	r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, string(rayv1alpha1.InvalidSpec), specErr.Error())
+
+	now := metav1.Now()
+	status := &rayJobInstance.Status
+	status.Reason = rayv1alpha1.InvalidSpec
+	status.Message = specErr.Error()
+	status.EndTime = &now
+	jobDeploymentStatus := rayv1alpha1.JobDeploymentStatusFailedToGetOrCreateRayCluster
+	if getDeletionRule(rayJobInstance) == nil {
+		jobDeploymentStatus = rayv1alpha1.JobDeploymentStatusComplete
+	}
+	if err := r.updateState(ctx, rayJobInstance, nil, rayv1alpha1.JobStatusFailed, jobDeploymentStatus, nil); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	return r.shutdownAfterJobFinishes(ctx, rayJobInstance)
+}
+
+// handleDashboardUnreachable tracks how long the dashboard has been unreachable while the Ray job is running.
+// The Ray job is declared lost once dashboardUnreachableSecondThreshold has passed. It is then retried as if its head
+// pod was lost, or fails with the DashboardUnreachable reason if no retries are left.
+func (r *RayJobReconciler) handleDashboardUnreachable(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, dashboardErr error) (ctrl.Result, error) {
+	status := &rayJobInstance.Status
+	now := metav1.Now()
+	if status.DashboardUnreachableSince == nil {
+		status.DashboardUnreachableSince = &now
+	}
+	threshold := RayJobDefaultDashboardUnreachableThreshold
+	if rayJobInstance.Spec.DashboardUnreachableSecondThreshold != nil {
+		threshold = time.Duration(*rayJobInstance.Spec.DashboardUnreachableSecondThreshold) * time.Second
+	}
+	unreachable := now.Sub(status.DashboardUnreachableSince.Time)
+	if unreachable > threshold {
+		message := fmt.Sprintf("Job %s is lost: the dashboard of RayCluster %s has been unreachable for %s: %v",
+			status.JobId, status.RayClusterName, unreachable.Round(time.Second), dashboardErr)
+		status.DashboardUnreachableSince = nil
+		if shouldRetry(rayJobInstance, rayv1alpha1.HeadPodLost) {
+			return r.retryRayJob(ctx, rayJobInstance, rayv1alpha1.HeadPodLost, message)
+		}
+		return r.failOnJobLost(ctx, rayJobInstance, message)
+	}
+
+	r.Log.Info("The dashboard is unreachable", "RayJob", rayJobInstance.Name, "unreachable", unreachable, "threshold", threshold, "error", dashboardErr)
+	status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusFailedToGetJobStatus
+	status.Message = dashboardErr.Error()
+	if err := r.Status().Update(ctx, rayJobInstance); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	// Dashboard service in head pod takes time to start, it's possible we get connection refused error.
+	// Requeue after few seconds to avoid continuous connection errors.
+	return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, nil
+}
+
+// markDashboardReachable clears the dashboard failures of a RayJob once its dashboard answers again.
+func (r *RayJobReconciler) markDashboardReachable(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) error {
+	status := &rayJobInstance.Status
+	r.Log.Info("The dashboard is reachable again", "RayJob", rayJobInstance.Name)
+	status.DashboardUnreachableSince = nil
+	if status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusFailedToGetJobStatus && isJobPendingOrRunning(status.JobStatus) {
+		status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusRunning
+	}
+	return r.Status().Update(ctx, rayJobInstance)
+}
+
+// failOnJobLost marks a RayJob whose Ray job was declared lost as failed, since the Ray job can't be stopped
+// without the dashboard. The RayCluster is then cleaned up as for any failed RayJob.
+func (r *RayJobReconciler) failOnJobLost(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, message string) (ctrl.Result, error) {
+	r.Log.Info("RayJob lost", "RayJob", rayJobInstance.Name, "message", message)
+	if err := r.deleteK8sJob(ctx, rayJobInstance); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	r.Recorder.Event(rayJobInstance, corev1.EventTypeWarning, string(rayv1alpha1.DashboardUnreachable), message)
+
+	now := metav1.Now()
+	status := &rayJobInstance.Status
+	status.Reason = rayv1alpha1.DashboardUnreachable
+	status.FailureType = rayv1alpha1.ClusterLostFailure
+	status.Message = message
+	status.EndTime = &now
+	if err := r.updateState(ctx, rayJobInstance, nil, rayv1alpha1.JobStatusFailed, rayv1alpha1.JobDeploymentStatusRunning, nil); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	return r.shutdownAfterJobFinishes(ctx, rayJobInstance)
+}
+
+// retryRayJob records the failed attempt of the RayJob, tears down its RayCluster and moves the RayJob to the
+// Retrying state. The next attempt gets a new job ID and a new RayCluster once the backoff has elapsed.
+func (r *RayJobReconciler) retryRayJob(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, reason rayv1alpha1.JobFailedReason, message string) (ctrl.Result, error) {
+	now := metav1.Now()
+	status := &rayJobInstance.Status
+	if reason == rayv1alpha1.HeadPodLost {
+		status.FailureType = rayv1alpha1.ClusterLostFailure
+	}
+	status.Attempts = append(status.Attempts, rayv1alpha1.RayJobAttempt{
+		JobId:          status.JobId,
+		RayClusterName: status.RayClusterName,
+		JobStatus:      status.JobStatus,
+		Reason:         reason,
+		FailureType:    status.FailureType,
+		Message:        message,
+		StartTime:      status.StartTime,
+		EndTime:        &now,
+	})
+	status.Failed++
+	backoff := retryBackoff(status.Failed)
+	r.Log.Info("RayJob attempt failed, retrying", "RayJob", rayJobInstance.Name, "reason", reason, "failed", status.Failed, "backoff", backoff)
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeWarning, "Retrying", "Job %s failed (%s), retrying in %s (%d/%d)",
+		status.JobId, reason, backoff, status.Failed, *rayJobInstance.Spec.BackoffLimit)
+
+	// Tear down the RayCluster of the failed attempt, unless it is shared through the clusterSelector.
+	if len(rayJobInstance.Spec.ClusterSelector) == 0 && status.RayClusterName != "" {
+		cluster := &rayv1alpha1.RayCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: status.RayClusterName, Namespace: rayJobInstance.Namespace},
+		}
+		if err := r.Delete(ctx, cluster); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+		}
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Deleted", "Deleted cluster %s", status.RayClusterName)
+	}
+	if err := r.deleteK8sJob(ctx, rayJobInstance); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+
+	status.JobId = ""
+	status.RayClusterName = ""
+	status.DashboardURL = ""
+	status.JobStatus = ""
+	status.Message = message
+	status.Reason = reason
+	status.StartTime = nil
+	status.EndTime = nil
+	status.AttemptStartTime = nil
+	status.DriverLogs = nil
+	status.JobDetails = nil
+	status.FailureType = ""
+	status.UntrackedJobs = nil
+	status.RayClusterStatus = rayv1alpha1.RayClusterStatus{}
+	status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusRetrying
+	status.ObservedGeneration = rayJobInstance.ObjectMeta.Generation
+	if err := r.Status().Update(ctx, rayJobInstance); err != nil {
+		return ctrl.Result{RequeueAfter: RayJobDefaultRequeueDuration}, err
+	}
+	return ctrl.Result{RequeueAfter: backoff}, nil
+}
+
+// adoptRayJob returns the Ray job the dashboard knows under the job ID of the RayJob, if it runs the entrypoint of
+// the RayJob, so that the RayJob tracks it instead of failing to submit a duplicate job. It returns nil otherwise.
+func (r *RayJobReconciler) adoptRayJob(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, rayDashboardClient utils.RayDashboardClientInterface) *utils.RayJobInfo {
+	jobInfo, err := rayDashboardClient.GetJobInfo(ctx, rayJobInstance.Status.JobId)
+	if err != nil || jobInfo == nil {
+		return nil
+	}
+	if jobInfo.Entrypoint != rayJobInstance.Spec.Entrypoint {
+		r.Log.Info("The job ID is used by a job with another entrypoint", "RayJob", rayJobInstance.Name,
+			"jobId", rayJobInstance.Status.JobId, "entrypoint", jobInfo.Entrypoint)
+		return nil
+	}
+	r.Log.Info("Adopt the existing job", "RayJob", rayJobInstance.Name, "jobId", rayJobInstance.Status.JobId, "jobStatus", jobInfo.JobStatus)
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Adopted", "Adopted existing job %s", rayJobInstance.Status.JobId)
+	return jobInfo
+}
+
+// detectUntrackedJobs records in the status the active Ray jobs on the RayCluster created by the RayJob that the
+// RayJob does not track, and emits an UntrackedJobs event when they change. RayClusters selected by clusterSelector
+// are shared by design, so they are not checked. Errors are only logged, since the check is informational.
+func (r *RayJobReconciler) detectUntrackedJobs(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, rayDashboardClient utils.RayDashboardClientInterface) {
+	if len(rayJobInstance.Spec.ClusterSelector) != 0 {
+		return
+	}
+	jobInfos, err := rayDashboardClient.ListJobs(ctx)
+	if err != nil {
+		r.Log.Info("Failed to list jobs", "RayJob", rayJobInstance.Name, "error", err)
+		return
+	}
+	var untrackedJobs []string
+	for _, jobInfo := range jobInfos {
+		jobId := jobInfo.SubmissionId
+		if jobId == "" {
+			// Drivers started without the Ray Jobs API have no submission ID.
+			jobId = jobInfo.JobId
+		}
+		if jobId != rayJobInstance.Status.JobId && !rayv1alpha1.IsJobTerminal(jobInfo.JobStatus) {
+			untrackedJobs = append(untrackedJobs, jobId)
+		}
+	}
+	sort.Strings(untrackedJobs)
+	if reflect.DeepEqual(untrackedJobs, rayJobInstance.Status.UntrackedJobs) {
+		return
+	}
+	rayJobInstance.Status.UntrackedJobs = untrackedJobs
+	if len(untrackedJobs) == 0 {
+		return
+	}
+	r.Recorder.Eventf(rayJobInstance, corev1.EventTypeWarning, "UntrackedJobs", "RayCluster %s runs jobs not tracked by the RayJob: %s",
+		rayJobInstance.Status.RayClusterName, strings.Join(untrackedJobs, ", "))
+}
+
+// admitRayJob returns whether the RayJob may be submitted to its RayCluster. A RayCluster shared through
+// clusterSelector can limit the number of RayJobs running on it with the ray.io/max-concurrent-jobs annotation.
+// The RayJobs beyond the limit are Queued by priority, then creation time, until running RayJobs finish.
+func (r *RayJobReconciler) admitRayJob(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob, rayClusterInstance *rayv1alpha1.RayCluster) (bool, error) {
+	if len(rayJobInstance.Spec.ClusterSelector) == 0 {
+		return true, nil
+	}
+	maxConcurrentJobs, err := getMaxConcurrentJobs(rayClusterInstance)
+	if err != nil {
+		r.Log.Error(err, "ignoring the concurrent job limit of the RayCluster", "RayCluster", rayClusterInstance.Name)
+		return true, nil
+	}
+	if maxConcurrentJobs == 0 {
+		return true, nil
+	}
+
+	rayJobList := rayv1alpha1.RayJobList{}
+	if err := r.List(ctx, &rayJobList, client.InNamespace(rayJobInstance.Namespace)); err != nil {
+		return false, err
+	}
+	running := 0
+	queue := []rayv1alpha1.RayJob{*rayJobInstance}
+	for _, rayJob := range rayJobList.Items {
+		if rayJob.Name == rayJobInstance.Name || rayJob.Spec.ClusterSelector[RayJobDefaultClusterSelectorKey] != rayClusterInstance.Name {
+			continue
+		}
+		if isJobPendingOrRunning(rayJob.Status.JobStatus) {
+			running++
+		} else if isRayJobWaiting(&rayJob) {
+			queue = append(queue, rayJob)
+		}
+	}
+	sortRayJobQueue(queue)
+
+	freeSlots := maxConcurrentJobs - running
+	if freeSlots < 0 {
+		freeSlots = 0
+	}
+	position := 0
+	for i := range queue {
+		if queue[i].Name == rayJobInstance.Name {
+			position = i + 1 - freeSlots
+		}
+	}
+	if position <= 0 {
+		rayJobInstance.Status.QueuePosition = 0
+		return true, nil
+	}
+
+	status := &rayJobInstance.Status
+	if status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusQueued && status.QueuePosition == int32(position) {
+		return false, nil
+	}
+	if status.JobDeploymentStatus != rayv1alpha1.JobDeploymentStatusQueued {
+		r.Log.Info("RayJob queued", "RayJob", rayJobInstance.Name, "RayCluster", rayClusterInstance.Name, "running", running, "position", position)
+		r.Recorder.Eventf(rayJobInstance, corev1.EventTypeNormal, "Queued", "Queued at position %d, %d of %d jobs are running on cluster %s",
+			position, running, maxConcurrentJobs, rayClusterInstance.Name)
+	}
+	status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusQueued
+	status.QueuePosition = int32(position)
+	status.AttemptStartTime = nil
+	status.ObservedGeneration = rayJobInstance.ObjectMeta.Generation
+	return false, r.Status().Update(ctx, rayJobInstance)
+}
+
+// getMaxConcurrentJobs returns the maximum number of RayJobs running at the same time on the RayCluster, or 0
+// if there is no limit.
+func getMaxConcurrentJobs(rayCluster *rayv1alpha1.RayCluster) (int, error) {
+	value, ok := rayCluster.Annotations[common.RayClusterMaxConcurrentJobsAnnotationKey]
+	if !ok {
+		return 0, nil
+	}
+	maxConcurrentJobs, err := strconv.Atoi(value)
+	if err != nil || maxConcurrentJobs < 1 {
+		return 0, fmt.Errorf("invalid %s annotation %q: expected a positive integer", common.RayClusterMaxConcurrentJobsAnnotationKey, value)
+	}
+	return maxConcurrentJobs, nil
+}
+
+// isRayJobWaiting returns whether the RayJob is waiting to be submitted to its RayCluster.
+func isRayJobWaiting(rayJob *rayv1alpha1.RayJob) bool {
+	if rayJob.Status.JobStatus != "" || rayJob.Spec.Suspend || !rayJob.DeletionTimestamp.IsZero() {
+		return false
+	}
+	switch rayJob.Status.JobDeploymentStatus {
+	case rayv1alpha1.JobDeploymentStatusRetrying, rayv1alpha1.JobDeploymentStatusComplete:
+		return false
+	}
+	return true
+}
+
+// sortRayJobQueue sorts the waiting RayJobs by decreasing priority, then by creation time.
+func sortRayJobQueue(queue []rayv1alpha1.RayJob) {
+	sort.SliceStable(queue, func(i, j int) bool {
+		if queue[i].Spec.Priority != queue[j].Spec.Priority {
+			return queue[i].Spec.Priority > queue[j].Spec.Priority
+		}
+		if !queue[i].CreationTimestamp.Equal(&queue[j].CreationTimestamp) {
+			return queue[i].CreationTimestamp.Before(&queue[j].CreationTimestamp)
+		}
+		return queue[i].Name < queue[j].Name
+	})
+}
+
 // isJobSucceedOrFailed indicates whether the job comes into end status.
 func isJobSucceedOrFailed(status rayv1alpha1.JobStatus) bool {
 	return (status == rayv1alpha1.JobStatusSucceeded) || (status == rayv1alpha1.JobStatusFailed)
@@ -336,6 +1056,7 @@ func (r *RayJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&rayv1alpha1.RayJob{}).
 		Owns(&rayv1alpha1.RayCluster{}).
 		Owns(&corev1.Service{}).
+		Owns(&batchv1.Job{}).
 		Complete(r)
 }
 
@@ -357,7 +1078,10 @@ func (r *RayJobReconciler) setRayJobIdAndRayClusterNameIfNeed(ctx context.Contex
 	shouldUpdateStatus := false
 	if rayJob.Status.JobId == "" {
 		shouldUpdateStatus = true
-		if rayJob.Spec.JobId != "" {
+		// The job ID of the spec is only used for the first attempt, since Ray rejects duplicate job IDs.
+		if rayJob.Spec.ExternalJobId != "" {
+			rayJob.Status.JobId = rayJob.Spec.ExternalJobId
+		} else if rayJob.Spec.JobId != "" && rayJob.Status.Failed == 0 {
 			rayJob.Status.JobId = rayJob.Spec.JobId
 		} else {
 			rayJob.Status.JobId = utils.GenerateRayJobId(rayJob.Name)
@@ -396,8 +1120,23 @@ func (r *RayJobReconciler) updateState(ctx context.Context, rayJob *rayv1alpha1.
 	}
 
 	r.Log.Info("UpdateState", "oldJobStatus", rayJob.Status.JobStatus, "newJobStatus", jobStatus, "oldJobDeploymentStatus", rayJob.Status.JobDeploymentStatus, "newJobDeploymentStatus", jobDeploymentStatus)
+	jobStatusChanged := rayJob.Status.JobStatus != jobStatus
+	if jobStatusChanged {
+		r.recordJobStatusTransition(rayJob, jobInfo, jobStatus)
+	}
 	rayJob.Status.JobStatus = jobStatus
 	rayJob.Status.JobDeploymentStatus = jobDeploymentStatus
+	// An attempt starts once the RayJob is neither waiting nor complete, which startupTimeoutSeconds is measured from.
+	switch jobDeploymentStatus {
+	case rayv1alpha1.JobDeploymentStatusSuspended, rayv1alpha1.JobDeploymentStatusQueued, rayv1alpha1.JobDeploymentStatusRetrying:
+		rayJob.Status.AttemptStartTime = nil
+	case rayv1alpha1.JobDeploymentStatusComplete:
+	default:
+		if rayJob.Status.AttemptStartTime == nil {
+			now := metav1.Now()
+			rayJob.Status.AttemptStartTime = &now
+		}
+	}
 	if jobInfo != nil {
 		rayJob.Status.Message = jobInfo.Message
 		rayJob.Status.StartTime = utils.ConvertUnixTimeToMetav1Time(jobInfo.StartTime)
@@ -406,6 +1145,10 @@ func (r *RayJobReconciler) updateState(ctx context.Context, rayJob *rayv1alpha1.
 		} else {
 			rayJob.Status.EndTime = utils.ConvertUnixTimeToMetav1Time(jobInfo.EndTime)
 		}
+		rayJob.Status.JobDetails = getRayJobDetails(jobInfo)
+		if jobStatus == rayv1alpha1.JobStatusFailed {
+			rayJob.Status.FailureType = classifyJobFailure(jobInfo)
+		}
 	}
 
 	// TODO (kevin85421): ObservedGeneration should be used to determine whether update this CR or not.
@@ -414,9 +1157,119 @@ func (r *RayJobReconciler) updateState(ctx context.Context, rayJob *rayv1alpha1.
 	if errStatus := r.Status().Update(ctx, rayJob); errStatus != nil {
 		return fmtErrors.Errorf("combined error: %v %v", err, errStatus)
 	}
+	if jobStatusChanged {
+		r.notifyJobFinished(ctx, rayJob)
+	}
 	return err
 }
 
+// notifyJobFinished notifies the outcome of the RayJob once its Ray job succeeded, or failed and won't be retried.
+func (r *RayJobReconciler) notifyJobFinished(ctx context.Context, rayJob *rayv1alpha1.RayJob) {
+	data := notify.NewJobData(rayJob)
+	var eventType rayv1alpha1.NotificationEvent
+	switch {
+	case rayJob.Status.JobStatus == rayv1alpha1.JobStatusSucceeded:
+		eventType = rayv1alpha1.JobSucceeded
+	case rayJob.Status.JobStatus == rayv1alpha1.JobStatusFailed:
+		if !isJobFailedForGood(rayJob) {
+			return
+		}
+		if !isFinalFailureReason(rayJob.Status.Reason) {
+			data.Reason = rayv1alpha1.AppFailed
+		}
+		eventType = rayv1alpha1.JobFailed
+	default:
+		return
+	}
+	notify.Send(ctx, r.Client, r.Recorder, rayJob.Spec.Notifications, "rayjobs", rayJob, eventType, rayJob.Status.JobId, data)
+}
+
+// recordJobStatusTransition emits a JobStatusChanged event when the status of the Ray job changes.
+func (r *RayJobReconciler) recordJobStatusTransition(rayJob *rayv1alpha1.RayJob, jobInfo *utils.RayJobInfo, jobStatus rayv1alpha1.JobStatus) {
+	eventType := corev1.EventTypeNormal
+	if jobStatus == rayv1alpha1.JobStatusFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	message := fmt.Sprintf("Job %s status changed from %s to %s", rayJob.Status.JobId, rayJob.Status.JobStatus, jobStatus)
+	if rayJob.Status.JobStatus == "" {
+		message = fmt.Sprintf("Job %s status changed to %s", rayJob.Status.JobId, jobStatus)
+	}
+	if jobInfo != nil && jobInfo.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, jobInfo.Message)
+	}
+	r.Recorder.Event(rayJob, eventType, "JobStatusChanged", message)
+}
+
+// getRayJobDetails returns the details of the Ray job to record in the RayJob status.
+func getRayJobDetails(jobInfo *utils.RayJobInfo) *rayv1alpha1.RayJobDetails {
+	details := &rayv1alpha1.RayJobDetails{
+		SubmissionId:   jobInfo.SubmissionId,
+		DriverJobId:    jobInfo.JobId,
+		DriverNodeId:   jobInfo.DriverNodeId,
+		DriverExitCode: jobInfo.DriverExitCode,
+		Entrypoint:     jobInfo.Entrypoint,
+	}
+	if jobInfo.DriverInfo != nil {
+		details.DriverNodeIPAddress = jobInfo.DriverInfo.NodeIpAddress
+	}
+	if jobInfo.ErrorType != nil {
+		details.ErrorType = *jobInfo.ErrorType
+	}
+	if len(jobInfo.RuntimeEnv) != 0 {
+		if runtimeEnv, err := json.Marshal(jobInfo.RuntimeEnv); err == nil {
+			details.RuntimeEnv = string(runtimeEnv)
+		}
+	}
+	return details
+}
+
+// classifyJobFailure returns why the failed Ray job failed, from its error type, driver exit code and message.
+// Older Ray versions report neither the error type nor the exit code, so the message is checked as well.
+func classifyJobFailure(jobInfo *utils.RayJobInfo) rayv1alpha1.JobFailureType {
+	errorType := ""
+	if jobInfo.ErrorType != nil {
+		errorType = *jobInfo.ErrorType
+	}
+	message := strings.ToLower(jobInfo.Message)
+	switch {
+	case errorType == "RUNTIME_ENV_SETUP_FAILURE" || strings.Contains(message, "runtime_env setup failed"):
+		return rayv1alpha1.RuntimeEnvSetupFailure
+	case jobInfo.DriverExitCode != nil && *jobInfo.DriverExitCode == oomKilledExitCode,
+		strings.Contains(message, "outofmemoryerror"), strings.Contains(message, "oomkilled"):
+		return rayv1alpha1.OutOfMemoryFailure
+	case strings.HasPrefix(errorType, "JOB_SUPERVISOR_ACTOR_"), strings.Contains(message, "job supervisor actor died"):
+		return rayv1alpha1.ClusterLostFailure
+	default:
+		return rayv1alpha1.UserCodeFailure
+	}
+}
+
+// rayJobValidationError is an invalid RayJob spec, which fails the RayJob without retrying it.
+type rayJobValidationError struct {
+	err error
+}
+
+func (e *rayJobValidationError) Error() string {
+	return e.err.Error()
+}
+
+// validateRayJobSpec returns a rayJobValidationError if the spec of the RayJob is invalid.
+func validateRayJobSpec(rayJob *rayv1alpha1.RayJob) error {
+	if rayJob.Spec.WorkingDirFrom != nil && !common.IsK8sJobMode(rayJob) {
+		return &rayJobValidationError{err: fmt.Errorf("workingDirFrom requires K8sJobMode, since only the submitter Kubernetes Job uploads the working directory")}
+	}
+	if rayJob.Spec.Entrypoint == "" && rayJob.Spec.ExternalJobId == "" {
+		return &rayJobValidationError{err: fmt.Errorf("entrypoint is required unless externalJobId is set")}
+	}
+	if len(rayJob.Spec.ClusterSelector) == 0 && rayJob.Spec.ExternalJobId != "" {
+		return &rayJobValidationError{err: fmt.Errorf("externalJobId requires clusterSelector, since an external job can't run on a cluster created by the RayJob")}
+	}
+	if _, _, err := common.GetWorkingDirVolume(rayJob); err != nil {
+		return &rayJobValidationError{err: err}
+	}
+	return nil
+}
+
 // TODO: select existing rayclusters by ClusterSelector
 func (r *RayJobReconciler) getOrCreateRayClusterInstance(ctx context.Context, rayJobInstance *rayv1alpha1.RayJob) (*rayv1alpha1.RayCluster, error) {
 	rayClusterInstanceName := rayJobInstance.Status.RayClusterName
@@ -426,6 +1279,10 @@ func (r *RayJobReconciler) getOrCreateRayClusterInstance(ctx context.Context, ra
 		Name:      rayClusterInstanceName,
 	}
 
+	if err := validateRayJobSpec(rayJobInstance); err != nil {
+		return nil, err
+	}
+
 	rayClusterInstance := &rayv1alpha1.RayCluster{}
 	err := r.Get(ctx, rayClusterNamespacedName, rayClusterInstance)
 	if err == nil {
@@ -0,0 +1,1071 @@
+package ray
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/notify"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestShouldRetry(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{}
+
+	// Retries are disabled without backoffLimit.
+	assert.False(t, shouldRetry(rayJob, rayv1alpha1.AppFailed))
+	assert.False(t, shouldRetry(rayJob, rayv1alpha1.ClusterFailed))
+
+	rayJob.Spec.BackoffLimit = pointer.Int32Ptr(2)
+	assert.True(t, shouldRetry(rayJob, rayv1alpha1.AppFailed))
+	assert.True(t, shouldRetry(rayJob, rayv1alpha1.HeadPodLost))
+
+	// OnInfraFailure does not retry the failures of the Ray job itself.
+	rayJob.Spec.RetryPolicy = rayv1alpha1.RetryOnInfraFailure
+	assert.False(t, shouldRetry(rayJob, rayv1alpha1.AppFailed))
+	assert.True(t, shouldRetry(rayJob, rayv1alpha1.ClusterFailed))
+	assert.True(t, shouldRetry(rayJob, rayv1alpha1.HeadPodLost))
+
+	// No retries are left once backoffLimit attempts have failed.
+	rayJob.Status.Failed = 2
+	assert.False(t, shouldRetry(rayJob, rayv1alpha1.ClusterFailed))
+
+	// An external job is never retried.
+	rayJob.Status.Failed = 0
+	rayJob.Spec.ExternalJobId = "raysubmit_external"
+	assert.False(t, shouldRetry(rayJob, rayv1alpha1.HeadPodLost))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, retryBackoff(1))
+	assert.Equal(t, 20*time.Second, retryBackoff(2))
+	assert.Equal(t, 40*time.Second, retryBackoff(3))
+	assert.Equal(t, RayJobRetryMaxBackoff, retryBackoff(10))
+
+	now := time.Now()
+	rayJob := &rayv1alpha1.RayJob{
+		Status: rayv1alpha1.RayJobStatus{
+			Failed: 1,
+			Attempts: []rayv1alpha1.RayJobAttempt{
+				{EndTime: &metav1.Time{Time: now.Add(-4 * time.Second)}},
+			},
+		},
+	}
+	assert.Equal(t, 6*time.Second, retryBackoffRemaining(rayJob, now))
+	assert.True(t, retryBackoffRemaining(rayJob, now.Add(time.Minute)) <= 0)
+}
+
+func TestIsJobLost(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{
+		Status: rayv1alpha1.RayJobStatus{
+			JobStatus:           rayv1alpha1.JobStatusPending,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+		},
+	}
+	assert.True(t, isJobLost(rayJob))
+
+	// In K8sJobMode, a PENDING job may not have been submitted yet.
+	rayJob.Spec.SubmissionMode = rayv1alpha1.K8sJobMode
+	assert.False(t, isJobLost(rayJob))
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+	assert.True(t, isJobLost(rayJob))
+
+	rayJob.Status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusInitializing
+	assert.False(t, isJobLost(rayJob))
+}
+
+func TestRetryRayJob(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	_ = batchv1.AddToScheme(newScheme)
+
+	startTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			JobId:        "custom-job-id",
+			BackoffLimit: pointer.Int32Ptr(3),
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "custom-job-id",
+			RayClusterName:      "rayjob-sample-raycluster-abcde",
+			DashboardURL:        "rayjob-sample-raycluster-abcde-head-svc.default.svc.cluster.local:8265",
+			JobStatus:           rayv1alpha1.JobStatusFailed,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+			Message:             "Job failed",
+			StartTime:           &startTime,
+			DriverLogs:          &rayv1alpha1.DriverLogsStatus{Tail: "Traceback"},
+			JobDetails:          &rayv1alpha1.RayJobDetails{SubmissionId: "custom-job-id"},
+			FailureType:         rayv1alpha1.UserCodeFailure,
+		},
+	}
+	rayCluster := &rayv1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample-raycluster-abcde",
+			Namespace: "default",
+		},
+	}
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob, rayCluster).Build()
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	ctx := context.Background()
+	result, err := r.retryRayJob(ctx, rayJob, rayv1alpha1.AppFailed, rayJob.Status.Message)
+	assert.Nil(t, err)
+	assert.Equal(t, RayJobRetryBaseBackoff, result.RequeueAfter)
+
+	// The failed attempt is recorded.
+	status := rayJob.Status
+	assert.Equal(t, int32(1), status.Failed)
+	assert.Equal(t, rayv1alpha1.AppFailed, status.Reason)
+	assert.Len(t, status.Attempts, 1)
+	assert.Equal(t, "custom-job-id", status.Attempts[0].JobId)
+	assert.Equal(t, "rayjob-sample-raycluster-abcde", status.Attempts[0].RayClusterName)
+	assert.Equal(t, rayv1alpha1.JobStatusFailed, status.Attempts[0].JobStatus)
+	assert.Equal(t, rayv1alpha1.UserCodeFailure, status.Attempts[0].FailureType)
+	assert.Equal(t, &startTime, status.Attempts[0].StartTime)
+	assert.NotNil(t, status.Attempts[0].EndTime)
+
+	// The state of the failed attempt is reset.
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusRetrying, status.JobDeploymentStatus)
+	assert.Empty(t, status.JobId)
+	assert.Empty(t, status.RayClusterName)
+	assert.Empty(t, status.DashboardURL)
+	assert.Empty(t, status.JobStatus)
+	assert.Nil(t, status.StartTime)
+	assert.Nil(t, status.DriverLogs)
+	assert.Nil(t, status.JobDetails)
+	assert.Empty(t, status.FailureType)
+
+	// The RayCluster of the failed attempt is deleted.
+	err = fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rayjob-sample-raycluster-abcde"}, &rayv1alpha1.RayCluster{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// The next attempt gets a new job ID, since Ray rejects duplicate job IDs.
+	assert.Nil(t, r.setRayJobIdAndRayClusterNameIfNeed(ctx, rayJob))
+	assert.NotEmpty(t, rayJob.Status.JobId)
+	assert.NotEqual(t, "custom-job-id", rayJob.Status.JobId)
+	assert.NotEmpty(t, rayJob.Status.RayClusterName)
+	assert.NotEqual(t, "rayjob-sample-raycluster-abcde", rayJob.Status.RayClusterName)
+}
+
+func TestReconcileInvalidRayJob(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			Entrypoint:   "python /home/ray/samples/sample_code.py",
+			BackoffLimit: pointer.Int32Ptr(3),
+			WorkingDirFrom: &rayv1alpha1.WorkingDirSource{
+				ConfigMap: &rayv1alpha1.ConfigMapWorkingDirSource{Name: "working-dir"},
+			},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: recorder,
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	// An invalid spec fails the RayJob without using its retries.
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "rayjob-sample"}}
+	result, err := r.Reconcile(ctx, request)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.Nil(t, fakeClient.Get(ctx, request.NamespacedName, rayJob))
+	assert.Equal(t, rayv1alpha1.JobStatusFailed, rayJob.Status.JobStatus)
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusComplete, rayJob.Status.JobDeploymentStatus)
+	assert.Equal(t, rayv1alpha1.InvalidSpec, rayJob.Status.Reason)
+	assert.NotNil(t, rayJob.Status.EndTime)
+	assert.Equal(t, int32(0), rayJob.Status.Failed)
+	assert.Empty(t, rayJob.Status.Attempts)
+	assert.Contains(t, rayJob.Status.Message, "workingDirFrom requires K8sJobMode")
+	assert.Contains(t, <-recorder.Events, "Warning InvalidSpec")
+}
+
+func TestValidateRayJobSpec(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{
+		Spec: rayv1alpha1.RayJobSpec{Entrypoint: "python /home/ray/samples/sample_code.py"},
+	}
+	assert.Nil(t, validateRayJobSpec(rayJob))
+	withWorkingDir := rayJob.DeepCopy()
+	withWorkingDir.Spec.SubmissionMode = rayv1alpha1.K8sJobMode
+	withWorkingDir.Spec.WorkingDirFrom = &rayv1alpha1.WorkingDirSource{ConfigMap: &rayv1alpha1.ConfigMapWorkingDirSource{Name: "working-dir"}}
+	assert.Nil(t, validateRayJobSpec(withWorkingDir))
+	withWorkingDir.Spec.ClusterSelector = map[string]string{RayJobDefaultClusterSelectorKey: "raycluster-sample"}
+	assert.Nil(t, validateRayJobSpec(withWorkingDir))
+
+	invalid := []func(spec *rayv1alpha1.RayJobSpec){
+		func(spec *rayv1alpha1.RayJobSpec) {
+			spec.WorkingDirFrom = &rayv1alpha1.WorkingDirSource{ConfigMap: &rayv1alpha1.ConfigMapWorkingDirSource{Name: "working-dir"}}
+		},
+		func(spec *rayv1alpha1.RayJobSpec) { spec.Entrypoint = "" },
+		func(spec *rayv1alpha1.RayJobSpec) { spec.ExternalJobId = "raysubmit_external" },
+		func(spec *rayv1alpha1.RayJobSpec) {
+			spec.SubmissionMode = rayv1alpha1.K8sJobMode
+			spec.WorkingDirFrom = &rayv1alpha1.WorkingDirSource{}
+		},
+	}
+	for _, mutate := range invalid {
+		invalidRayJob := rayJob.DeepCopy()
+		mutate(&invalidRayJob.Spec)
+		err := validateRayJobSpec(invalidRayJob)
+		assert.NotNil(t, err)
+		assert.IsType(t, &rayJobValidationError{}, err)
+	}
+}
+
+func TestDeadlineExceededMessage(t *testing.T) {
+	now := time.Now()
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			StartupTimeoutSeconds: pointer.Int32Ptr(30),
+			ActiveDeadlineSeconds: pointer.Int32Ptr(120),
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "rayjob-sample-abcde",
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusInitializing,
+			AttemptStartTime:    &metav1.Time{Time: now.Add(-time.Minute)},
+		},
+	}
+
+	// The attempt started a minute ago and the Ray job has not started yet.
+	assert.Contains(t, deadlineExceededMessage(rayJob, now), "startupTimeoutSeconds")
+	rayJob.Spec.StartupTimeoutSeconds = pointer.Int32Ptr(90)
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+
+	// The time before the attempt started, e.g. in the backoff of a retry, does not count.
+	rayJob.Spec.StartupTimeoutSeconds = pointer.Int32Ptr(30)
+	rayJob.Status.AttemptStartTime = &metav1.Time{Time: now.Add(-10 * time.Second)}
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+	rayJob.Status.AttemptStartTime = &metav1.Time{Time: now.Add(-time.Minute)}
+
+	// A suspended or queued RayJob never exceeds its deadline.
+	rayJob.Status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusSuspended
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+	rayJob.Status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusQueued
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+
+	// Once the Ray job started, activeDeadlineSeconds applies instead.
+	rayJob.Status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusRunning
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+	rayJob.Status.StartTime = &metav1.Time{Time: now.Add(-time.Minute)}
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+	rayJob.Status.StartTime = &metav1.Time{Time: now.Add(-3 * time.Minute)}
+	assert.Contains(t, deadlineExceededMessage(rayJob, now), "activeDeadlineSeconds")
+
+	// A finished Ray job never exceeds its deadline.
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusSucceeded
+	assert.Empty(t, deadlineExceededMessage(rayJob, now))
+}
+
+func TestFailOnDeadlineExceeded(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	_ = batchv1.AddToScheme(newScheme)
+
+	utils.GetRayDashboardClientFunc = func() utils.RayDashboardClientInterface {
+		return &utils.FakeRayDashboardClient{}
+	}
+	defer func() { utils.GetRayDashboardClientFunc = utils.GetRayDashboardClient }()
+
+	startTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			ActiveDeadlineSeconds:    pointer.Int32Ptr(60),
+			ShutdownAfterJobFinishes: true,
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "rayjob-sample-abcde",
+			RayClusterName:      "rayjob-sample-raycluster-abcde",
+			DashboardURL:        "rayjob-sample-raycluster-abcde-head-svc.default.svc.cluster.local:8265",
+			JobStatus:           rayv1alpha1.JobStatusRunning,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+			StartTime:           &startTime,
+		},
+	}
+	rayCluster := &rayv1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample-raycluster-abcde",
+			Namespace: "default",
+		},
+	}
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob, rayCluster).Build()
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	ctx := context.Background()
+	message := deadlineExceededMessage(rayJob, time.Now())
+	assert.NotEmpty(t, message)
+	_, err := r.failOnDeadlineExceeded(ctx, rayJob, message)
+	assert.Nil(t, err)
+
+	assert.Equal(t, rayv1alpha1.JobStatusFailed, rayJob.Status.JobStatus)
+	assert.Equal(t, rayv1alpha1.DeadlineExceeded, rayJob.Status.Reason)
+	assert.Equal(t, message, rayJob.Status.Message)
+	assert.NotNil(t, rayJob.Status.EndTime)
+	assert.NotNil(t, rayJob.Status.DriverLogs)
+
+	// The RayCluster is deleted since shutdownAfterJobFinishes is set, and the RayJob is complete once it is gone.
+	err = fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rayjob-sample-raycluster-abcde"}, &rayv1alpha1.RayCluster{})
+	assert.True(t, errors.IsNotFound(err))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "rayjob-sample"}})
+	assert.Nil(t, err)
+	assert.Nil(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rayjob-sample"}, rayJob))
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusComplete, rayJob.Status.JobDeploymentStatus)
+
+	// Without a deletion rule, the RayJob is complete right away.
+	rayJob.Spec.ShutdownAfterJobFinishes = false
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+	rayJob.Status.JobDeploymentStatus = rayv1alpha1.JobDeploymentStatusRunning
+	_, err = r.failOnDeadlineExceeded(ctx, rayJob, message)
+	assert.Nil(t, err)
+	assert.Equal(t, rayv1alpha1.JobStatusFailed, rayJob.Status.JobStatus)
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusComplete, rayJob.Status.JobDeploymentStatus)
+}
+
+func TestReconcileDashboardURLChanged(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	_ = batchv1.AddToScheme(newScheme)
+
+	utils.GetRayDashboardClientFunc = func() utils.RayDashboardClientInterface {
+		dashboardClient := &utils.FakeRayDashboardClient{}
+		dashboardClient.SetJobInfos([]utils.RayJobInfo{{SubmissionId: "rayjob-sample-abcde", JobStatus: rayv1alpha1.JobStatusRunning}})
+		return dashboardClient
+	}
+	defer func() { utils.GetRayDashboardClientFunc = utils.GetRayDashboardClient }()
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "rayjob-sample",
+			Namespace:  "default",
+			Finalizers: []string{common.RayJobStopJobFinalizer},
+		},
+		Spec: rayv1alpha1.RayJobSpec{Entrypoint: "python /home/ray/samples/sample_code.py"},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "rayjob-sample-abcde",
+			RayClusterName:      "rayjob-sample-raycluster-abcde",
+			DashboardURL:        "rayjob-sample-raycluster-abcde-head-svc.default.svc.cluster.local:8265",
+			JobStatus:           rayv1alpha1.JobStatusRunning,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+		},
+	}
+	rayCluster := &rayv1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayjob-sample-raycluster-abcde", Namespace: "default"},
+		Status:     rayv1alpha1.RayClusterStatus{State: rayv1alpha1.Ready},
+	}
+	headService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: utils.GenerateServiceName(rayCluster.Name), Namespace: "default"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: utils.DefaultDashboardName, Port: 8266}},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob, rayCluster, headService).Build()
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	// The new dashboard URL is saved even though the job status doesn't change.
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "rayjob-sample"}}
+	_, _ = r.Reconcile(ctx, request)
+	assert.Nil(t, fakeClient.Get(ctx, request.NamespacedName, rayJob))
+	assert.Equal(t, "rayjob-sample-raycluster-abcde-head-svc.default.svc.cluster.local:8266", rayJob.Status.DashboardURL)
+}
+
+func TestUpdateStateAttemptStartTime(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+	}
+	r := &RayJobReconciler{
+		Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob).Build(),
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	ctx := context.Background()
+
+	// The attempt starts with the first deployment status, and keeps its start time until it waits again.
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, "", rayv1alpha1.JobDeploymentStatusInitializing, nil))
+	attemptStartTime := rayJob.Status.AttemptStartTime
+	assert.NotNil(t, attemptStartTime)
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusPending, rayv1alpha1.JobDeploymentStatusRunning, nil))
+	assert.Equal(t, attemptStartTime, rayJob.Status.AttemptStartTime)
+
+	// A suspended RayJob starts a new attempt once it resumes.
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusStopped, rayv1alpha1.JobDeploymentStatusSuspended, nil))
+	assert.Nil(t, rayJob.Status.AttemptStartTime)
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusStopped, rayv1alpha1.JobDeploymentStatusInitializing, nil))
+	assert.NotNil(t, rayJob.Status.AttemptStartTime)
+}
+
+func TestAdmitRayJob(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	now := time.Now()
+	newRayJob := func(name string, priority int32, age time.Duration, jobStatus rayv1alpha1.JobStatus) *rayv1alpha1.RayJob {
+		return &rayv1alpha1.RayJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: rayv1alpha1.RayJobSpec{
+				ClusterSelector: map[string]string{RayJobDefaultClusterSelectorKey: "shared-cluster"},
+				Priority:        priority,
+			},
+			Status: rayv1alpha1.RayJobStatus{
+				JobStatus:           jobStatus,
+				JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusInitializing,
+				RayClusterName:      "shared-cluster",
+			},
+		}
+	}
+	running := newRayJob("running", 0, 3*time.Hour, rayv1alpha1.JobStatusRunning)
+	oldest := newRayJob("oldest", 0, 2*time.Hour, "")
+	urgent := newRayJob("urgent", 10, time.Hour, "")
+	newest := newRayJob("newest", 0, time.Minute, "")
+	finished := newRayJob("finished", 0, 4*time.Hour, rayv1alpha1.JobStatusSucceeded)
+	rayCluster := &rayv1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared-cluster",
+			Namespace:   "default",
+			Annotations: map[string]string{common.RayClusterMaxConcurrentJobsAnnotationKey: "1"},
+		},
+	}
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(running, oldest, urgent, newest, finished, rayCluster).Build()
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	ctx := context.Background()
+
+	// The only slot of the RayCluster is taken, so the RayJobs are queued by priority, then creation time.
+	for _, test := range []struct {
+		rayJob   *rayv1alpha1.RayJob
+		position int32
+	}{{urgent, 1}, {oldest, 2}, {newest, 3}} {
+		admitted, err := r.admitRayJob(ctx, test.rayJob, rayCluster)
+		assert.Nil(t, err)
+		assert.False(t, admitted)
+		assert.Equal(t, rayv1alpha1.JobDeploymentStatusQueued, test.rayJob.Status.JobDeploymentStatus)
+		assert.Equal(t, test.position, test.rayJob.Status.QueuePosition)
+	}
+
+	// Once the running RayJob finishes, the head of the queue is admitted.
+	running.Status.JobStatus = rayv1alpha1.JobStatusSucceeded
+	assert.Nil(t, r.Status().Update(ctx, running))
+	admitted, err := r.admitRayJob(ctx, oldest, rayCluster)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, int32(1), oldest.Status.QueuePosition)
+	admitted, err = r.admitRayJob(ctx, urgent, rayCluster)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+	assert.Equal(t, int32(0), urgent.Status.QueuePosition)
+
+	// RayJobs are not queued on a RayCluster without a valid limit, or without clusterSelector.
+	for _, annotations := range []map[string]string{nil, {common.RayClusterMaxConcurrentJobsAnnotationKey: "0"}} {
+		rayCluster.Annotations = annotations
+		admitted, err = r.admitRayJob(ctx, newest, rayCluster)
+		assert.Nil(t, err)
+		assert.True(t, admitted)
+	}
+	rayCluster.Annotations = map[string]string{common.RayClusterMaxConcurrentJobsAnnotationKey: "1"}
+	newest.Spec.ClusterSelector = nil
+	admitted, err = r.admitRayJob(ctx, newest, rayCluster)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+}
+
+func TestGetMaxConcurrentJobs(t *testing.T) {
+	rayCluster := &rayv1alpha1.RayCluster{}
+	maxConcurrentJobs, err := getMaxConcurrentJobs(rayCluster)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, maxConcurrentJobs)
+
+	rayCluster.Annotations = map[string]string{common.RayClusterMaxConcurrentJobsAnnotationKey: "4"}
+	maxConcurrentJobs, err = getMaxConcurrentJobs(rayCluster)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, maxConcurrentJobs)
+
+	for _, value := range []string{"0", "-1", "four"} {
+		rayCluster.Annotations[common.RayClusterMaxConcurrentJobsAnnotationKey] = value
+		_, err = getMaxConcurrentJobs(rayCluster)
+		assert.NotNil(t, err, value)
+	}
+}
+
+func TestClassifyJobFailure(t *testing.T) {
+	errorType := func(s string) *string { return &s }
+	tests := map[string]struct {
+		jobInfo  utils.RayJobInfo
+		expected rayv1alpha1.JobFailureType
+	}{
+		"entrypoint fails": {
+			jobInfo:  utils.RayJobInfo{Message: "Job entrypoint command failed with exit code 1", DriverExitCode: pointer.Int32Ptr(1)},
+			expected: rayv1alpha1.UserCodeFailure,
+		},
+		"runtime env setup fails": {
+			jobInfo:  utils.RayJobInfo{Message: "runtime_env setup failed: Failed to set up runtime environment.", ErrorType: errorType("RUNTIME_ENV_SETUP_FAILURE")},
+			expected: rayv1alpha1.RuntimeEnvSetupFailure,
+		},
+		"runtime env setup fails before Ray 2.5": {
+			jobInfo:  utils.RayJobInfo{Message: "runtime_env setup failed: Could not find module 'foo'"},
+			expected: rayv1alpha1.RuntimeEnvSetupFailure,
+		},
+		"driver is OOM killed": {
+			jobInfo:  utils.RayJobInfo{Message: "Job entrypoint command failed with exit code 137", DriverExitCode: pointer.Int32Ptr(137)},
+			expected: rayv1alpha1.OutOfMemoryFailure,
+		},
+		"task runs out of memory": {
+			jobInfo:  utils.RayJobInfo{Message: "ray.exceptions.OutOfMemoryError: Task was killed due to the node running low on memory."},
+			expected: rayv1alpha1.OutOfMemoryFailure,
+		},
+		"job supervisor dies": {
+			jobInfo:  utils.RayJobInfo{Message: "Job supervisor actor died", ErrorType: errorType("JOB_SUPERVISOR_ACTOR_DIED")},
+			expected: rayv1alpha1.ClusterLostFailure,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, classifyJobFailure(&tc.jobInfo))
+		})
+	}
+}
+
+func TestUpdateStateWithJobDetails(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "rayjob-sample-abcde",
+			JobStatus:           rayv1alpha1.JobStatusRunning,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &RayJobReconciler{
+		Client:   fakeClient,
+		Recorder: recorder,
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	errorType := "JOB_ENTRYPOINT_COMMAND_ERROR"
+	jobInfo := &utils.RayJobInfo{
+		JobStatus:      rayv1alpha1.JobStatusFailed,
+		Entrypoint:     "python /home/ray/samples/sample_code.py",
+		Message:        "Job entrypoint command failed with exit code 1",
+		ErrorType:      &errorType,
+		StartTime:      1000,
+		EndTime:        2000,
+		JobId:          "02000000",
+		SubmissionId:   "rayjob-sample-abcde",
+		DriverInfo:     &utils.RayJobDriverInfo{Id: "02000000", NodeIpAddress: "10.0.0.1", Pid: "1234"},
+		DriverNodeId:   "node-1",
+		DriverExitCode: pointer.Int32Ptr(1),
+		RuntimeEnv:     map[string]interface{}{"pip": []string{"requests==2.26.0"}},
+	}
+	err := r.updateState(context.Background(), rayJob, jobInfo, jobInfo.JobStatus, rayv1alpha1.JobDeploymentStatusRunning, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, &rayv1alpha1.RayJobDetails{
+		SubmissionId:        "rayjob-sample-abcde",
+		DriverJobId:         "02000000",
+		DriverNodeId:        "node-1",
+		DriverNodeIPAddress: "10.0.0.1",
+		DriverExitCode:      pointer.Int32Ptr(1),
+		ErrorType:           "JOB_ENTRYPOINT_COMMAND_ERROR",
+		Entrypoint:          "python /home/ray/samples/sample_code.py",
+		RuntimeEnv:          `{"pip":["requests==2.26.0"]}`,
+	}, rayJob.Status.JobDetails)
+	assert.Equal(t, rayv1alpha1.UserCodeFailure, rayJob.Status.FailureType)
+
+	// A dedicated event is emitted for the status transition.
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "Warning JobStatusChanged Job rayjob-sample-abcde status changed from RUNNING to FAILED")
+}
+
+func TestGetDeletionRule(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{
+		Spec: rayv1alpha1.RayJobSpec{
+			ShutdownAfterJobFinishes: true,
+			TTLSecondsAfterFinished:  pointer.Int32Ptr(10),
+		},
+		Status: rayv1alpha1.RayJobStatus{JobStatus: rayv1alpha1.JobStatusFailed},
+	}
+	// ShutdownAfterJobFinishes deletes the RayCluster whatever the outcome.
+	assert.Equal(t, &rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteCluster, TTLSecondsAfterFinished: pointer.Int32Ptr(10)}, getDeletionRule(rayJob))
+
+	rayJob.Spec.ShutdownAfterJobFinishes = false
+	assert.Nil(t, getDeletionRule(rayJob))
+
+	// The deletion policy takes precedence and depends on the outcome.
+	rayJob.Spec.ShutdownAfterJobFinishes = true
+	rayJob.Spec.DeletionPolicy = &rayv1alpha1.DeletionPolicy{
+		OnSuccess: rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteCluster},
+		OnFailure: rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteNone, TTLSecondsAfterFinished: pointer.Int32Ptr(3600)},
+	}
+	assert.Equal(t, rayv1alpha1.DeleteNone, getDeletionRule(rayJob).Policy)
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusSucceeded
+	assert.Equal(t, rayv1alpha1.DeleteCluster, getDeletionRule(rayJob).Policy)
+}
+
+func TestShutdownAfterJobFinishesWithDeletionPolicy(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	endTime := metav1.NewTime(time.Now().Add(-time.Minute))
+	tests := map[string]struct {
+		rule            rayv1alpha1.DeletionRule
+		clusterSelector map[string]string
+		autoscaling     bool
+		requeue         bool
+		rayJobDeleted   bool
+		clusterDeleted  bool
+		workersDeleted  bool
+		complete        bool
+	}{
+		"DeleteCluster": {
+			rule:           rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteCluster},
+			clusterDeleted: true,
+		},
+		"DeleteWorkers": {
+			rule:           rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteWorkers},
+			workersDeleted: true,
+			complete:       true,
+		},
+		"DeleteWorkers with the Ray autoscaler": {
+			rule:           rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteWorkers},
+			autoscaling:    true,
+			workersDeleted: true,
+			complete:       true,
+		},
+		"DeleteSelf": {
+			rule:          rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteSelf},
+			rayJobDeleted: true,
+		},
+		"DeleteNone": {
+			rule:     rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteNone},
+			complete: true,
+		},
+		"TTL not reached": {
+			rule:    rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteCluster, TTLSecondsAfterFinished: pointer.Int32Ptr(3600)},
+			requeue: true,
+		},
+		"shared RayCluster is kept": {
+			rule:            rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteWorkers},
+			clusterSelector: map[string]string{RayJobDefaultClusterSelectorKey: "raycluster-sample"},
+			complete:        true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rayJob := &rayv1alpha1.RayJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rayjob-sample",
+					Namespace: "default",
+				},
+				Spec: rayv1alpha1.RayJobSpec{
+					ClusterSelector: tc.clusterSelector,
+					DeletionPolicy: &rayv1alpha1.DeletionPolicy{
+						OnSuccess: rayv1alpha1.DeletionRule{Policy: rayv1alpha1.DeleteNone},
+						OnFailure: tc.rule,
+					},
+				},
+				Status: rayv1alpha1.RayJobStatus{
+					RayClusterName:      "raycluster-sample",
+					JobStatus:           rayv1alpha1.JobStatusFailed,
+					JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+					EndTime:             &endTime,
+				},
+			}
+			rayCluster := &rayv1alpha1.RayCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "raycluster-sample",
+					Namespace: "default",
+				},
+				Spec: rayv1alpha1.RayClusterSpec{
+					EnableInTreeAutoscaling: pointer.Bool(tc.autoscaling),
+					WorkerGroupSpecs: []rayv1alpha1.WorkerGroupSpec{
+						{GroupName: "small-group", Replicas: pointer.Int32Ptr(2), MinReplicas: pointer.Int32Ptr(1), MaxReplicas: pointer.Int32Ptr(5)},
+						{GroupName: "gpu-group", Replicas: pointer.Int32Ptr(1), MinReplicas: pointer.Int32Ptr(1), MaxReplicas: pointer.Int32Ptr(2)},
+					},
+				},
+			}
+			fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob, rayCluster).Build()
+			r := &RayJobReconciler{
+				Client:   fakeClient,
+				Recorder: &record.FakeRecorder{},
+				Scheme:   newScheme,
+				Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+			}
+
+			ctx := context.Background()
+			result, err := r.shutdownAfterJobFinishes(ctx, rayJob)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.requeue, result.RequeueAfter > RayJobDefaultRequeueDuration)
+
+			err = fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rayjob-sample"}, &rayv1alpha1.RayJob{})
+			assert.Equal(t, tc.rayJobDeleted, errors.IsNotFound(err))
+
+			cluster := &rayv1alpha1.RayCluster{}
+			err = fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "raycluster-sample"}, cluster)
+			assert.Equal(t, tc.clusterDeleted, errors.IsNotFound(err))
+			if !tc.clusterDeleted {
+				for _, group := range cluster.Spec.WorkerGroupSpecs {
+					// The Ray autoscaler can't scale the worker groups up again once maxReplicas is zero.
+					assert.Equal(t, tc.workersDeleted, *group.Replicas == 0 && *group.MinReplicas == 0 && *group.MaxReplicas == 0)
+				}
+			}
+
+			assert.Equal(t, tc.complete, rayJob.Status.JobDeploymentStatus == rayv1alpha1.JobDeploymentStatusComplete)
+		})
+	}
+}
+
+func TestAdoptRayJob(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			Entrypoint: "python /home/ray/samples/sample_code.py",
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId: "rayjob-sample-abcde",
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RayJobReconciler{
+		Recorder: recorder,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	ctx := context.Background()
+	rayDashboardClient := &utils.FakeRayDashboardClient{}
+
+	// There is nothing to adopt if the dashboard does not know the job ID.
+	assert.Nil(t, r.adoptRayJob(ctx, rayJob, rayDashboardClient))
+
+	// A job with the same job ID and entrypoint is adopted.
+	rayDashboardClient.SetJobInfos([]utils.RayJobInfo{
+		{SubmissionId: "rayjob-sample-abcde", Entrypoint: rayJob.Spec.Entrypoint, JobStatus: rayv1alpha1.JobStatusRunning},
+	})
+	jobInfo := r.adoptRayJob(ctx, rayJob, rayDashboardClient)
+	assert.NotNil(t, jobInfo)
+	assert.Equal(t, rayv1alpha1.JobStatusRunning, jobInfo.JobStatus)
+	assert.Contains(t, <-recorder.Events, "Normal Adopted Adopted existing job rayjob-sample-abcde")
+
+	// A job with the same job ID but another entrypoint is not.
+	rayDashboardClient.SetJobInfos([]utils.RayJobInfo{
+		{SubmissionId: "rayjob-sample-abcde", Entrypoint: "python other.py", JobStatus: rayv1alpha1.JobStatusRunning},
+	})
+	assert.Nil(t, r.adoptRayJob(ctx, rayJob, rayDashboardClient))
+}
+
+func TestDetectUntrackedJobs(t *testing.T) {
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:          "rayjob-sample-abcde",
+			RayClusterName: "rayjob-sample-raycluster-abcde",
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &RayJobReconciler{
+		Recorder: recorder,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	ctx := context.Background()
+	rayDashboardClient := &utils.FakeRayDashboardClient{}
+	rayDashboardClient.SetJobInfos([]utils.RayJobInfo{
+		{SubmissionId: "rayjob-sample-abcde", JobStatus: rayv1alpha1.JobStatusRunning},
+		{SubmissionId: "raysubmit_out_of_band", JobStatus: rayv1alpha1.JobStatusPending},
+		{SubmissionId: "raysubmit_finished", JobStatus: rayv1alpha1.JobStatusSucceeded},
+		{JobId: "03000000", JobStatus: rayv1alpha1.JobStatusRunning},
+	})
+
+	// Active jobs other than the one of the RayJob are untracked, including drivers without a submission ID.
+	r.detectUntrackedJobs(ctx, rayJob, rayDashboardClient)
+	assert.Equal(t, []string{"03000000", "raysubmit_out_of_band"}, rayJob.Status.UntrackedJobs)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning UntrackedJobs RayCluster rayjob-sample-raycluster-abcde runs jobs not tracked by the RayJob: 03000000, raysubmit_out_of_band")
+
+	// The event is only emitted when the untracked jobs change.
+	r.detectUntrackedJobs(ctx, rayJob, rayDashboardClient)
+	assert.Len(t, recorder.Events, 0)
+	rayDashboardClient.SetJobInfos(nil)
+	r.detectUntrackedJobs(ctx, rayJob, rayDashboardClient)
+	assert.Nil(t, rayJob.Status.UntrackedJobs)
+	assert.Len(t, recorder.Events, 0)
+
+	// Shared RayClusters are not checked.
+	rayJob.Spec.ClusterSelector = map[string]string{RayJobDefaultClusterSelectorKey: "raycluster-sample"}
+	rayDashboardClient.SetJobInfos([]utils.RayJobInfo{{SubmissionId: "rayjob-other", JobStatus: rayv1alpha1.JobStatusRunning}})
+	r.detectUntrackedJobs(ctx, rayJob, rayDashboardClient)
+	assert.Nil(t, rayJob.Status.UntrackedJobs)
+}
+
+func TestSetRayJobIdWithExternalJobId(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			ExternalJobId:   "raysubmit_external",
+			ClusterSelector: map[string]string{RayJobDefaultClusterSelectorKey: "raycluster-sample"},
+		},
+	}
+	r := &RayJobReconciler{
+		Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob).Build(),
+		Recorder: &record.FakeRecorder{},
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+
+	// The RayJob tracks the external job on the selected RayCluster.
+	assert.Nil(t, r.setRayJobIdAndRayClusterNameIfNeed(context.Background(), rayJob))
+	assert.Equal(t, "raysubmit_external", rayJob.Status.JobId)
+	assert.Equal(t, "raycluster-sample", rayJob.Status.RayClusterName)
+
+	// An external job requires clusterSelector.
+	rayJob.Spec.ClusterSelector = nil
+	_, err := r.getOrCreateRayClusterInstance(context.Background(), rayJob)
+	assert.NotNil(t, err)
+}
+
+func TestHandleDashboardUnreachable(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	_ = batchv1.AddToScheme(newScheme)
+
+	newRayJob := func() *rayv1alpha1.RayJob {
+		return &rayv1alpha1.RayJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rayjob-sample",
+				Namespace: "default",
+			},
+			Spec: rayv1alpha1.RayJobSpec{
+				ShutdownAfterJobFinishes:            true,
+				DashboardUnreachableSecondThreshold: pointer.Int32Ptr(60),
+			},
+			Status: rayv1alpha1.RayJobStatus{
+				JobId:               "rayjob-sample-abcde",
+				RayClusterName:      "rayjob-sample-raycluster-abcde",
+				JobStatus:           rayv1alpha1.JobStatusRunning,
+				JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+			},
+		}
+	}
+	newReconciler := func(rayJob *rayv1alpha1.RayJob) *RayJobReconciler {
+		rayCluster := &rayv1alpha1.RayCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "rayjob-sample-raycluster-abcde",
+				Namespace: "default",
+			},
+		}
+		return &RayJobReconciler{
+			Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob, rayCluster).Build(),
+			Recorder: &record.FakeRecorder{},
+			Scheme:   newScheme,
+			Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+		}
+	}
+	ctx := context.Background()
+	dashboardErr := fmt.Errorf("connection refused")
+
+	// The first failure starts tracking the outage.
+	rayJob := newRayJob()
+	r := newReconciler(rayJob)
+	result, err := r.handleDashboardUnreachable(ctx, rayJob, dashboardErr)
+	assert.Nil(t, err)
+	assert.Equal(t, RayJobDefaultRequeueDuration, result.RequeueAfter)
+	assert.NotNil(t, rayJob.Status.DashboardUnreachableSince)
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusFailedToGetJobStatus, rayJob.Status.JobDeploymentStatus)
+	assert.Equal(t, rayv1alpha1.JobStatusRunning, rayJob.Status.JobStatus)
+
+	// The outage is cleared once the dashboard answers again.
+	assert.Nil(t, r.markDashboardReachable(ctx, rayJob))
+	assert.Nil(t, rayJob.Status.DashboardUnreachableSince)
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusRunning, rayJob.Status.JobDeploymentStatus)
+
+	// The Ray job is lost once the threshold has passed, and fails without retries left.
+	unreachableSince := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	rayJob.Status.DashboardUnreachableSince = &unreachableSince
+	_, err = r.handleDashboardUnreachable(ctx, rayJob, dashboardErr)
+	assert.Nil(t, err)
+	assert.Equal(t, rayv1alpha1.JobStatusFailed, rayJob.Status.JobStatus)
+	assert.Equal(t, rayv1alpha1.DashboardUnreachable, rayJob.Status.Reason)
+	assert.Equal(t, rayv1alpha1.ClusterLostFailure, rayJob.Status.FailureType)
+	assert.Contains(t, rayJob.Status.Message, "connection refused")
+	assert.NotNil(t, rayJob.Status.EndTime)
+	err = r.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rayjob-sample-raycluster-abcde"}, &rayv1alpha1.RayCluster{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// With retries left, the Ray job is retried as if its head pod was lost.
+	rayJob = newRayJob()
+	rayJob.Spec.BackoffLimit = pointer.Int32Ptr(1)
+	rayJob.Status.DashboardUnreachableSince = &unreachableSince
+	r = newReconciler(rayJob)
+	_, err = r.handleDashboardUnreachable(ctx, rayJob, dashboardErr)
+	assert.Nil(t, err)
+	assert.Equal(t, rayv1alpha1.JobDeploymentStatusRetrying, rayJob.Status.JobDeploymentStatus)
+	assert.Equal(t, rayv1alpha1.HeadPodLost, rayJob.Status.Reason)
+	assert.Len(t, rayJob.Status.Attempts, 1)
+	assert.Equal(t, rayv1alpha1.ClusterLostFailure, rayJob.Status.Attempts[0].FailureType)
+	assert.Nil(t, rayJob.Status.DashboardUnreachableSince)
+}
+
+func TestNotifyJobFinished(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+
+	received := make(chan notify.Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event notify.Event
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&event))
+		received <- event
+	}))
+	defer server.Close()
+	nextEvent := func() *notify.Event {
+		select {
+		case event := <-received:
+			return &event
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+
+	rayJob := &rayv1alpha1.RayJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayjob-sample",
+			Namespace: "default",
+		},
+		Spec: rayv1alpha1.RayJobSpec{
+			BackoffLimit:  pointer.Int32Ptr(1),
+			Notifications: &rayv1alpha1.NotificationSpec{URL: server.URL},
+		},
+		Status: rayv1alpha1.RayJobStatus{
+			JobId:               "rayjob-sample-abcde",
+			JobStatus:           rayv1alpha1.JobStatusRunning,
+			JobDeploymentStatus: rayv1alpha1.JobDeploymentStatusRunning,
+		},
+	}
+	r := &RayJobReconciler{
+		Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayJob).Build(),
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayJob"),
+	}
+	ctx := context.Background()
+
+	// A failure that is retried is not notified.
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusFailed, rayv1alpha1.JobDeploymentStatusRunning, nil))
+	select {
+	case event := <-received:
+		t.Fatalf("unexpected notification %s", event.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The failure of the last attempt is notified.
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+	rayJob.Status.Failed = 1
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusFailed, rayv1alpha1.JobDeploymentStatusRunning, nil))
+	event := nextEvent()
+	if assert.NotNil(t, event) {
+		assert.Equal(t, notify.EventTypePrefix+string(rayv1alpha1.JobFailed), event.Type)
+		assert.Equal(t, string(rayv1alpha1.AppFailed), event.Data.(map[string]interface{})["reason"])
+	}
+
+	// A success is notified.
+	rayJob.Status.JobStatus = rayv1alpha1.JobStatusRunning
+	assert.Nil(t, r.updateState(ctx, rayJob, nil, rayv1alpha1.JobStatusSucceeded, rayv1alpha1.JobDeploymentStatusRunning, nil))
+	event = nextEvent()
+	if assert.NotNil(t, event) {
+		assert.Equal(t, notify.EventTypePrefix+string(rayv1alpha1.JobSucceeded), event.Type)
+		assert.Equal(t, "rayjob-sample-abcde", event.Data.(map[string]interface{})["jobId"])
+	}
+}
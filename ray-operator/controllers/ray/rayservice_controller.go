@@ -3,7 +3,10 @@ package ray
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/json"
@@ -13,18 +16,22 @@ import (
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
 
-	cmap "github.com/orcaman/concurrent-map"
-
 	"github.com/go-logr/logr"
 	fmtErrors "github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/notify"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,11 +44,15 @@ import (
 // This variable is mutable for unit testing purpose.
 var ServiceUnhealthySecondThreshold = 60.0 // Serve deployment related health check.
 
+// ServeHealthCheckConcurrency is the number of Serve HTTP proxies of a RayCluster checked at the same time.
+var ServeHealthCheckConcurrency = 16
+
 const (
 	ServiceDefaultRequeueDuration      = 2 * time.Second
 	ServiceRestartRequeueDuration      = 10 * time.Second
 	RayClusterDeletionDelayDuration    = 60 * time.Second
 	DeploymentUnhealthySecondThreshold = 60.0 // Dashboard agent related health check.
+	ServePodHealthExpiration           = 10 * time.Minute
 )
 
 // RayServiceReconciler reconciles a RayService object
@@ -50,21 +61,18 @@ type RayServiceReconciler struct {
 	Scheme   *runtime.Scheme
 	Log      logr.Logger
 	Recorder record.EventRecorder
-	// Currently, the Ray dashboard doesn't cache the Serve deployment config.
-	// To avoid reapplying the same config repeatedly, cache the config in this map.
-	ServeDeploymentConfigs       cmap.ConcurrentMap
-	RayClusterDeletionTimestamps cmap.ConcurrentMap
+	// The results of the Serve HTTP proxy health checks, which only change the serving label of a pod after
+	// consecutive failures or successes.
+	servePodHealth servePodHealthCache
 }
 
 // NewRayServiceReconciler returns a new reconcile.Reconciler
 func NewRayServiceReconciler(mgr manager.Manager) *RayServiceReconciler {
 	return &RayServiceReconciler{
-		Client:                       mgr.GetClient(),
-		Scheme:                       mgr.GetScheme(),
-		Log:                          ctrl.Log.WithName("controllers").WithName("RayService"),
-		Recorder:                     mgr.GetEventRecorderFor("rayservice-controller"),
-		ServeDeploymentConfigs:       cmap.New(),
-		RayClusterDeletionTimestamps: cmap.New(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+		Recorder: mgr.GetEventRecorderFor("rayservice-controller"),
 	}
 }
 
@@ -74,6 +82,7 @@ func NewRayServiceReconciler(mgr manager.Manager) *RayServiceReconciler {
 // +kubebuilder:rbac:groups=ray.io,resources=rayclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ray.io,resources=rayclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ray.io,resources=rayclusters/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;list;watch;create;update;patch;delete
@@ -83,6 +92,9 @@ func NewRayServiceReconciler(mgr manager.Manager) *RayServiceReconciler {
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingressclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups=extensions,resources=ingresses,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=grpcroutes,verbs=get;list;watch;create;update;delete;patch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=roles,verbs=get;list;watch;create;delete;update
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;delete
@@ -108,11 +120,15 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 	originalRayServiceInstance := rayServiceInstance.DeepCopy()
-	r.cleanUpServeConfigCache(rayServiceInstance)
 
 	// TODO (kevin85421): ObservedGeneration should be used to determine whether to update this CR or not.
 	rayServiceInstance.Status.ObservedGeneration = rayServiceInstance.ObjectMeta.Generation
 
+	if err = r.reconcileRevisions(ctx, rayServiceInstance); err != nil {
+		logger.Error(err, "Failed to reconcile the revisions of the RayService.")
+		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+	}
+
 	logger.Info("Reconciling the cluster component.")
 	// Find active and pending ray cluster objects given current service name.
 	var activeRayClusterInstance *rayv1alpha1.RayCluster
@@ -133,6 +149,14 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, nil
 	}
 
+	// Route the traffic before reconciling Serve, so that the traffic moves back to the active RayCluster as soon as
+	// the pending one restarts or changes. The traffic shifts and switchovers of reconcileServe are routed by the next
+	// reconciliation, while the previous RayCluster is still running.
+	if err := r.reconcileTrafficRouting(ctx, rayServiceInstance, activeRayClusterInstance, pendingRayClusterInstance); err != nil {
+		err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateService, err)
+		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+	}
+
 	/*
 		Update ray cluster for 4 possible situations.
 		If a ray cluster does not exist, clear its status.
@@ -180,7 +204,14 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 
 	// Get the ready Ray cluster instance for service and ingress update.
 	var rayClusterInstance *rayv1alpha1.RayCluster
-	if pendingRayClusterInstance != nil {
+	isShiftingTraffic := r.isShiftingTraffic(rayServiceInstance) && activeRayClusterInstance != nil && pendingRayClusterInstance != nil
+	if isShiftingTraffic {
+		// The serve service keeps pointing at the active cluster until the traffic router has shifted all the
+		// traffic to the pending one.
+		rayClusterInstance = activeRayClusterInstance
+		logger.Info("Reconciling the ingress and service resources " +
+			"on the active Ray cluster while shifting the traffic to the pending Ray cluster.")
+	} else if pendingRayClusterInstance != nil {
 		rayClusterInstance = pendingRayClusterInstance
 		logger.Info("Reconciling the ingress and service resources " +
 			"on the pending Ray cluster.")
@@ -202,7 +233,7 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateService, err)
 			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
 		}
-		if err := r.labelHealthyServePods(ctx, rayClusterInstance); err != nil {
+		if err := r.labelHealthyServePods(ctx, rayServiceInstance, rayClusterInstance); err != nil {
 			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateServingPodLabel, err)
 			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
 		}
@@ -210,6 +241,20 @@ func (r *RayServiceReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateService, err)
 			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
 		}
+		if err := r.reconcileServeIngress(ctx, rayServiceInstance, rayClusterInstance); err != nil {
+			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateIngress, err)
+			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+		}
+		if err := r.reconcileServeGRPCRoute(ctx, rayServiceInstance, rayClusterInstance); err != nil {
+			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateIngress, err)
+			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+		}
+		if isShiftingTraffic {
+			if err := r.labelHealthyServePods(ctx, rayServiceInstance, pendingRayClusterInstance); err != nil {
+				err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToUpdateServingPodLabel, err)
+				return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, err
+			}
+		}
 	}
 
 	// Final status update for any CR modification.
@@ -233,6 +278,16 @@ func (r *RayServiceReconciler) inconsistentRayServiceStatus(oldStatus rayv1alpha
 		return true
 	}
 
+	if !reflect.DeepEqual(oldStatus.TrafficWeight, newStatus.TrafficWeight) {
+		r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService TrafficWeight changed from %v to %v", oldStatus.TrafficWeight, newStatus.TrafficWeight))
+		return true
+	}
+
+	if !reflect.DeepEqual(oldStatus.ApplicationProbes, newStatus.ApplicationProbes) {
+		r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService ApplicationProbes changed from %v to %v", oldStatus.ApplicationProbes, newStatus.ApplicationProbes))
+		return true
+	}
+
 	if oldStatus.DashboardStatus.IsHealthy != newStatus.DashboardStatus.IsHealthy {
 		r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService DashboardStatus changed from %v to %v", oldStatus.DashboardStatus, newStatus.DashboardStatus))
 		return true
@@ -258,6 +313,26 @@ func (r *RayServiceReconciler) inconsistentRayServiceStatus(oldStatus rayv1alpha
 		}
 	}
 
+	if len(oldStatus.Applications) != len(newStatus.Applications) {
+		r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService number of Serve applications changed from %v to %v", len(oldStatus.Applications), len(newStatus.Applications)))
+		return true
+	}
+
+	for appName, newApp := range newStatus.Applications {
+		oldApp, exist := oldStatus.Applications[appName]
+		if !exist || oldApp.Status != newApp.Status || oldApp.Message != newApp.Message || len(oldApp.Deployments) != len(newApp.Deployments) {
+			r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService Serve application %s changed from %v to %v", appName, oldApp, newApp))
+			return true
+		}
+		for deploymentName, newDeployment := range newApp.Deployments {
+			oldDeployment, exist := oldApp.Deployments[deploymentName]
+			if !exist || oldDeployment.Status != newDeployment.Status || oldDeployment.Message != newDeployment.Message {
+				r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService ServeDeploymentStatus of application %s changed from %v to %v", appName, oldDeployment, newDeployment))
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
@@ -268,6 +343,23 @@ func (r *RayServiceReconciler) inconsistentRayServiceStatuses(oldStatus rayv1alp
 		return true
 	}
 
+	if oldStatus.Reason != newStatus.Reason {
+		r.Log.Info(fmt.Sprintf("inconsistentRayServiceStatus RayService Reason changed from %s to %s", oldStatus.Reason, newStatus.Reason))
+		return true
+	}
+
+	if (oldStatus.PendingClusterStartTime == nil) != (newStatus.PendingClusterStartTime == nil) {
+		r.Log.Info("inconsistentRayServiceStatus RayService PendingClusterStartTime changed")
+		return true
+	}
+
+	if oldStatus.CurrentRevision != newStatus.CurrentRevision ||
+		oldStatus.UpdateRevision != newStatus.UpdateRevision ||
+		oldStatus.RollbackRevision != newStatus.RollbackRevision {
+		r.Log.Info("inconsistentRayServiceStatus RayService revisions changed")
+		return true
+	}
+
 	if r.inconsistentRayServiceStatus(oldStatus.ActiveServiceStatus, newStatus.ActiveServiceStatus) {
 		r.Log.Info("inconsistentRayServiceStatus RayService ActiveServiceStatus changed")
 		return true
@@ -338,11 +430,25 @@ func (r *RayServiceReconciler) reconcileRayCluster(ctx context.Context, rayServi
 		return nil, nil, err
 	}
 
+	abandoned, err := r.checkPendingClusterTimeout(ctx, rayServiceInstance, activeRayCluster, pendingRayCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if abandoned {
+		return activeRayCluster, nil, nil
+	}
+
 	if r.shouldPrepareNewRayCluster(rayServiceInstance, activeRayCluster) {
 		r.markRestart(rayServiceInstance)
 		return activeRayCluster, nil, nil
 	}
 
+	if activeRayCluster != nil && rayServiceInstance.Status.PendingServiceStatus.RayClusterName == "" {
+		if _, err = r.updateRayClusterInPlace(ctx, rayServiceInstance, activeRayCluster); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	if pendingRayCluster, err = r.createRayClusterInstanceIfNeeded(ctx, rayServiceInstance, pendingRayCluster); err != nil {
 		return nil, nil, err
 	}
@@ -361,31 +467,42 @@ func (r *RayServiceReconciler) cleanUpRayClusterInstance(ctx context.Context, ra
 	}
 
 	// Clean up RayCluster instances. Each instance is deleted 60 seconds
-	// after becoming inactive to give the ingress time to update.
-	for _, rayClusterInstance := range rayClusterList.Items {
+	// after becoming inactive to give the ingress time to update. The
+	// deletion time is kept in an annotation of the RayCluster, so that it
+	// survives the restarts of the operator.
+	for i := range rayClusterList.Items {
+		rayClusterInstance := &rayClusterList.Items[i]
 		if rayClusterInstance.Name != rayServiceInstance.Status.ActiveServiceStatus.RayClusterName && rayClusterInstance.Name != rayServiceInstance.Status.PendingServiceStatus.RayClusterName {
-			cachedTimestampObj, exists := r.RayClusterDeletionTimestamps.Get(rayClusterInstance.Name)
+			deletionTimestampStr, exists := rayClusterInstance.Annotations[common.RayClusterDeletionTimestampAnnotationKey]
 			if !exists {
 				deletionTimestamp := metav1.Now().Add(RayClusterDeletionDelayDuration)
-				r.RayClusterDeletionTimestamps.Set(rayClusterInstance.Name, deletionTimestamp)
+				patch := client.MergeFrom(rayClusterInstance.DeepCopy())
+				if rayClusterInstance.Annotations == nil {
+					rayClusterInstance.Annotations = map[string]string{}
+				}
+				rayClusterInstance.Annotations[common.RayClusterDeletionTimestampAnnotationKey] = deletionTimestamp.Format(time.RFC3339)
+				if err := r.Patch(ctx, rayClusterInstance, patch); err != nil {
+					r.Log.Error(err, "Fail to schedule the deletion of RayCluster "+rayClusterInstance.Name)
+					return err
+				}
 				r.Log.V(1).Info(fmt.Sprintf("Scheduled dangling RayCluster "+
 					"%s for deletion at %s", rayClusterInstance.Name, deletionTimestamp))
 			} else {
-				cachedTimestamp, isTimestamp := cachedTimestampObj.(time.Time)
+				deletionTimestamp, err := time.Parse(time.RFC3339, deletionTimestampStr)
 				reasonForDeletion := ""
-				if !isTimestamp {
-					reasonForDeletion = fmt.Sprintf("Deletion cache contains "+
-						"unexpected, non-timestamp object for RayCluster %s. "+
-						"Deleting cluster immediately.", rayClusterInstance.Name)
-				} else if time.Since(cachedTimestamp) > 0*time.Second {
+				if err != nil {
+					reasonForDeletion = fmt.Sprintf("Deletion annotation contains "+
+						"unexpected, non-timestamp value %s for RayCluster %s. "+
+						"Deleting cluster immediately.", deletionTimestampStr, rayClusterInstance.Name)
+				} else if time.Since(deletionTimestamp) > 0*time.Second {
 					reasonForDeletion = fmt.Sprintf("Deletion timestamp %s "+
 						"for RayCluster %s has passed. Deleting cluster "+
-						"immediately.", cachedTimestamp, rayClusterInstance.Name)
+						"immediately.", deletionTimestamp, rayClusterInstance.Name)
 				}
 
 				if reasonForDeletion != "" {
 					r.Log.V(1).Info("reconcileRayCluster", "delete Ray cluster", rayClusterInstance.Name, "reason", reasonForDeletion)
-					if err := r.Delete(ctx, &rayClusterInstance, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+					if err := r.Delete(ctx, rayClusterInstance, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
 						r.Log.Error(err, "Fail to delete RayCluster "+rayClusterInstance.Name)
 						return err
 					}
@@ -412,24 +529,49 @@ func (r *RayServiceReconciler) getRayClusterByNamespacedName(ctx context.Context
 	return rayCluster, nil
 }
 
-// cleanUpServeConfigCache cleans up the unused serve deployments config in the cached map.
-func (r *RayServiceReconciler) cleanUpServeConfigCache(rayServiceInstance *rayv1alpha1.RayService) {
-	activeConfigKey := r.generateConfigKey(rayServiceInstance, rayServiceInstance.Status.ActiveServiceStatus.RayClusterName)
-	pendingConfigKey := r.generateConfigKey(rayServiceInstance, rayServiceInstance.Status.PendingServiceStatus.RayClusterName)
-	configPrefix := r.generateConfigKeyPrefix(rayServiceInstance)
+// checkPendingClusterTimeout abandons the pending RayCluster when it isn't ready before the pending cluster timeout. The
+// pending RayCluster is deleted, and the active one keeps serving until the spec changes. It returns whether the
+// pending RayCluster was abandoned.
+func (r *RayServiceReconciler) checkPendingClusterTimeout(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, activeRayCluster *rayv1alpha1.RayCluster, pendingRayCluster *rayv1alpha1.RayCluster) (bool, error) {
+	status := &rayServiceInstance.Status
+	if status.Reason == rayv1alpha1.UpgradeFailedReason && status.UpgradeFailedGeneration != rayServiceInstance.Generation {
+		r.Log.Info("The RayService spec changed after its upgrade failed.", "upgradeFailedGeneration", status.UpgradeFailedGeneration)
+		status.Reason = ""
+		status.Message = ""
+		status.UpgradeFailedGeneration = 0
+	}
 
-	// Clean up RayCluster serve deployment configs.
-	for key := range r.ServeDeploymentConfigs.Items() {
-		if key == activeConfigKey || key == pendingConfigKey {
-			continue
-		}
-		if !strings.HasPrefix(key, configPrefix) {
-			// Skip configs owned by other RayService Instance.
-			continue
+	// The timeout spans the restarts of the pending RayCluster, until it becomes active or is abandoned.
+	if status.PendingServiceStatus.RayClusterName == "" {
+		status.PendingClusterStartTime = nil
+		return false, nil
+	}
+	if status.PendingClusterStartTime == nil {
+		now := metav1.Now()
+		status.PendingClusterStartTime = &now
+	}
+
+	// Without an active RayCluster, there is nothing to fall back to.
+	timeout := rayServiceInstance.Spec.PendingClusterTimeoutSeconds
+	if timeout == nil || activeRayCluster == nil || time.Since(status.PendingClusterStartTime.Time) < time.Duration(*timeout)*time.Second {
+		return false, nil
+	}
+
+	pendingClusterName := status.PendingServiceStatus.RayClusterName
+	if pendingRayCluster != nil {
+		r.Log.V(1).Info("checkPendingClusterTimeout", "delete Ray cluster", pendingRayCluster.Name)
+		if err := r.Delete(ctx, pendingRayCluster, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return false, err
 		}
-		r.Log.V(1).Info("cleanUpServeConfigCache", "activeConfigKey", activeConfigKey, "pendingConfigKey", pendingConfigKey, "remove key", key)
-		r.ServeDeploymentConfigs.Remove(key)
 	}
+	status.PendingServiceStatus = rayv1alpha1.RayServiceStatus{}
+	status.PendingClusterStartTime = nil
+	status.Reason = rayv1alpha1.UpgradeFailedReason
+	status.Message = fmt.Sprintf("Pending RayCluster %s was not ready in %d seconds. "+
+		"RayCluster %s keeps serving until the spec changes.", pendingClusterName, *timeout, activeRayCluster.Name)
+	status.UpgradeFailedGeneration = rayServiceInstance.Generation
+	r.Recorder.Event(rayServiceInstance, corev1.EventTypeWarning, rayv1alpha1.UpgradeFailedReason, status.Message)
+	return true, nil
 }
 
 // shouldPrepareNewRayCluster checks if we need to generate a new pending cluster.
@@ -442,6 +584,10 @@ func (r *RayServiceReconciler) shouldPrepareNewRayCluster(rayServiceInstance *ra
 			r.Log.Info("No active Ray cluster. RayService operator should prepare a new Ray cluster.")
 			return true
 		}
+		if rayServiceInstance.Status.Reason == rayv1alpha1.UpgradeFailedReason {
+			r.Log.Info("The upgrade of the RayService failed. The active Ray cluster keeps serving until the spec changes.")
+			return false
+		}
 		activeClusterHash := activeRayCluster.ObjectMeta.Annotations[common.RayServiceClusterHashKey]
 		goalClusterHash, err := generateRayClusterJsonHash(rayServiceInstance.Spec.RayClusterSpec)
 		if err != nil {
@@ -452,16 +598,22 @@ func (r *RayServiceReconciler) shouldPrepareNewRayCluster(rayServiceInstance *ra
 			return true
 		}
 
-		if activeClusterHash != goalClusterHash {
-			r.Log.Info("Active RayCluster config doesn't match goal config. " +
-				"RayService operator should prepare a new Ray cluster.\n" +
-				"* Active RayCluster config hash: " + activeClusterHash + "\n" +
-				"* Goal RayCluster config hash: " + goalClusterHash)
-		} else {
+		if activeClusterHash == goalClusterHash {
 			r.Log.Info("Active Ray cluster config matches goal config.")
+			return false
 		}
 
-		return activeClusterHash != goalClusterHash
+		if inPlace, err := isRayClusterSpecUpdatableInPlace(activeRayCluster, rayServiceInstance.Spec.RayClusterSpec); err == nil && inPlace {
+			r.Log.Info("Active RayCluster config only differs from goal config in the scaling or the number of " +
+				"worker groups. RayService operator should update the active Ray cluster in place.")
+			return false
+		}
+
+		r.Log.Info("Active RayCluster config doesn't match goal config. " +
+			"RayService operator should prepare a new Ray cluster.\n" +
+			"* Active RayCluster config hash: " + activeClusterHash + "\n" +
+			"* Goal RayCluster config hash: " + goalClusterHash)
+		return true
 	}
 
 	return false
@@ -474,6 +626,17 @@ func (r *RayServiceReconciler) createRayClusterInstanceIfNeeded(ctx context.Cont
 		return nil, nil
 	}
 
+	// Scaling the pending cluster doesn't need a new RayCluster either.
+	if pendingRayCluster != nil {
+		inPlace, err := r.updateRayClusterInPlace(ctx, rayServiceInstance, pendingRayCluster)
+		if err != nil {
+			return nil, err
+		}
+		if inPlace {
+			return pendingRayCluster, nil
+		}
+	}
+
 	// Create a new RayCluster if:
 	// 1. No RayCluster pending.
 	// 2. Config update for the pending cluster.
@@ -484,6 +647,10 @@ func (r *RayServiceReconciler) createRayClusterInstanceIfNeeded(ctx context.Cont
 	}
 
 	if pendingRayCluster == nil || !equal {
+		// The traffic must not be routed to a RayCluster that is being replaced, and the results of its
+		// application probes don't apply to the new one.
+		rayServiceInstance.Status.PendingServiceStatus.TrafficWeight = nil
+		rayServiceInstance.Status.PendingServiceStatus.ApplicationProbes = nil
 		pendingRayCluster, err = r.createRayClusterInstance(ctx, rayServiceInstance, rayServiceInstance.Status.PendingServiceStatus.RayClusterName)
 		if err != nil {
 			return nil, err
@@ -582,64 +749,110 @@ func (r *RayServiceReconciler) constructRayClusterForRayService(rayService *rayv
 	return rayCluster, nil
 }
 
+// serveConfig is the part of the RayService spec that is deployed with the Serve API. Its hash is recorded on the
+// RayCluster it is deployed to.
+type serveConfig struct {
+	DeploymentGraph rayv1alpha1.ServeDeploymentGraphSpec
+	Applications    []rayv1alpha1.ServeApplicationSpec
+	ConfigV2        string
+}
+
+func getServeConfig(rayServiceInstance *rayv1alpha1.RayService) serveConfig {
+	return serveConfig{
+		DeploymentGraph: rayServiceInstance.Spec.ServeDeploymentGraphSpec,
+		Applications:    rayServiceInstance.Spec.ServeApplications,
+		ConfigV2:        rayServiceInstance.Spec.ServeConfigV2,
+	}
+}
+
 func (r *RayServiceReconciler) checkIfNeedSubmitServeDeployment(rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster, serveStatus *rayv1alpha1.RayServiceStatus) bool {
-	// If the Serve config has not been cached, update the Serve config.
-	cacheKey := r.generateConfigKey(rayServiceInstance, rayClusterInstance.Name)
-	cachedConfigObj, exist := r.ServeDeploymentConfigs.Get(cacheKey)
+	// After a failed upgrade, the active RayCluster keeps serving its Serve config until the spec changes, since the
+	// Serve config of the abandoned upgrade may need the abandoned RayClusterSpec.
+	if rayServiceInstance.Status.Reason == rayv1alpha1.UpgradeFailedReason && rayServiceInstance.Status.UpgradeFailedGeneration == rayServiceInstance.Generation {
+		r.Log.V(1).Info("shouldUpdate",
+			"shouldUpdateServe",
+			false,
+			"reason",
+			fmt.Sprintf("The upgrade of the RayService failed, so cluster %s keeps its Serve config", rayClusterInstance.Name),
+		)
+		return false
+	}
 
+	// If the Serve config has not been submitted to the RayCluster, update the Serve config.
+	submittedHash, exist := rayClusterInstance.Annotations[common.RayServiceServeConfigHashKey]
 	if !exist {
 		r.Log.V(1).Info("shouldUpdate",
 			"shouldUpdateServe",
 			true,
 			"reason",
-			fmt.Sprintf(
-				"Nothing has been cached for cluster %s with key %s",
-				rayClusterInstance.Name,
-				cacheKey,
-			),
+			fmt.Sprintf("No Serve config has been submitted to cluster %s", rayClusterInstance.Name),
 		)
 		return true
 	}
 
-	// If the Serve config has been cached, check if it needs to be updated.
+	// If the Serve config has been submitted, check if it needs to be updated.
 	shouldUpdate := false
-	reason := fmt.Sprintf("Current Serve config matches cached Serve config, "+
+	reason := fmt.Sprintf("Current Serve config matches submitted Serve config, "+
 		"and some deployments have been deployed for cluster %s", rayClusterInstance.Name)
 
-	cachedServeConfig, isServeConfig := cachedConfigObj.(rayv1alpha1.ServeDeploymentGraphSpec)
-	if !isServeConfig {
+	currentHash, err := utils.GenerateJsonHash(getServeConfig(rayServiceInstance))
+	if err != nil {
 		shouldUpdate = true
-		reason = fmt.Sprintf("No Serve config has been cached for cluster %s with key %s", rayClusterInstance.Name, cacheKey)
-	} else if !utils.CompareJsonStruct(cachedServeConfig, rayServiceInstance.Spec.ServeDeploymentGraphSpec) {
+		reason = fmt.Sprintf("Failed to hash the current Serve config: %v", err)
+	} else if submittedHash != currentHash {
 		shouldUpdate = true
-		reason = fmt.Sprintf("Current Serve config doesn't match cached Serve config for cluster %s with key %s", rayClusterInstance.Name, cacheKey)
+		reason = fmt.Sprintf("Current Serve config doesn't match submitted Serve config for cluster %s", rayClusterInstance.Name)
 	}
 
-	r.Log.V(1).Info("shouldUpdate", "shouldUpdateServe", shouldUpdate, "reason", reason, "cachedServeConfig", cachedServeConfig, "current Serve config", rayServiceInstance.Spec.ServeDeploymentGraphSpec)
+	r.Log.V(1).Info("shouldUpdate", "shouldUpdateServe", shouldUpdate, "reason", reason, "submittedServeConfigHash", submittedHash, "current Serve config hash", currentHash)
 
 	return shouldUpdate
 }
 
-func (r *RayServiceReconciler) updateServeDeployment(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayDashboardClient utils.RayDashboardClientInterface, clusterName string) error {
-	r.Log.V(1).Info("updateServeDeployment", "config", rayServiceInstance.Spec.ServeDeploymentGraphSpec)
-	runtimeEnv := make(map[string]interface{})
-	_ = yaml.Unmarshal([]byte(rayServiceInstance.Spec.ServeDeploymentGraphSpec.RuntimeEnv), &runtimeEnv)
-	servingClusterDeployments := utils.ServingClusterDeployments{
-		ImportPath:  rayServiceInstance.Spec.ServeDeploymentGraphSpec.ImportPath,
-		RuntimeEnv:  runtimeEnv,
-		Deployments: rayDashboardClient.ConvertServeConfig(rayServiceInstance.Spec.ServeDeploymentGraphSpec.ServeConfigSpecs),
+func (r *RayServiceReconciler) updateServeDeployment(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayDashboardClient utils.RayDashboardClientInterface, rayClusterInstance *rayv1alpha1.RayCluster, serveAPI utils.ServeAPI) error {
+	if serveAPI == utils.ServeApplicationsAPI {
+		r.Log.V(1).Info("updateServeDeployment", "applications", rayServiceInstance.Spec.ServeApplications, "serveConfigV2", rayServiceInstance.Spec.ServeConfigV2)
+		grpcPort := 0
+		if rayServiceInstance.Spec.ServeGRPC != nil {
+			grpcPort = int(common.GetServeGRPCPort(*rayClusterInstance))
+		}
+		if err := rayDashboardClient.UpdateApplications(ctx, &rayServiceInstance.Spec, grpcPort); err != nil {
+			r.Log.Error(err, "fail to update applications")
+			return err
+		}
+	} else {
+		r.Log.V(1).Info("updateServeDeployment", "config", rayServiceInstance.Spec.ServeDeploymentGraphSpec)
+		runtimeEnv := make(map[string]interface{})
+		_ = yaml.Unmarshal([]byte(rayServiceInstance.Spec.ServeDeploymentGraphSpec.RuntimeEnv), &runtimeEnv)
+		servingClusterDeployments := utils.ServingClusterDeployments{
+			ImportPath:  rayServiceInstance.Spec.ServeDeploymentGraphSpec.ImportPath,
+			RuntimeEnv:  runtimeEnv,
+			Deployments: rayDashboardClient.ConvertServeConfig(rayServiceInstance.Spec.ServeDeploymentGraphSpec.ServeConfigSpecs),
+		}
+
+		deploymentJson, _ := json.Marshal(servingClusterDeployments)
+		r.Log.V(1).Info("updateServeDeployment", "json config", string(deploymentJson))
+		if err := rayDashboardClient.UpdateDeployments(ctx, rayServiceInstance.Spec.ServeDeploymentGraphSpec); err != nil {
+			r.Log.Error(err, "fail to update deployment")
+			return err
+		}
 	}
 
-	deploymentJson, _ := json.Marshal(servingClusterDeployments)
-	r.Log.V(1).Info("updateServeDeployment", "json config", string(deploymentJson))
-	if err := rayDashboardClient.UpdateDeployments(ctx, rayServiceInstance.Spec.ServeDeploymentGraphSpec); err != nil {
-		r.Log.Error(err, "fail to update deployment")
+	// The hash of the submitted Serve config is kept in an annotation of the RayCluster, so that the config isn't
+	// submitted again after the restarts of the operator.
+	serveConfigHash, err := utils.GenerateJsonHash(getServeConfig(rayServiceInstance))
+	if err != nil {
 		return err
 	}
-
-	cacheKey := r.generateConfigKey(rayServiceInstance, clusterName)
-	r.ServeDeploymentConfigs.Set(cacheKey, rayServiceInstance.Spec.ServeDeploymentGraphSpec)
-	r.Log.V(1).Info("updateServeDeployment", "message", fmt.Sprintf("Cached Serve config for Ray cluster %s with key %s", clusterName, cacheKey))
+	patch := client.MergeFrom(rayClusterInstance.DeepCopy())
+	if rayClusterInstance.Annotations == nil {
+		rayClusterInstance.Annotations = map[string]string{}
+	}
+	rayClusterInstance.Annotations[common.RayServiceServeConfigHashKey] = serveConfigHash
+	if err := r.Patch(ctx, rayClusterInstance, patch); err != nil {
+		return err
+	}
+	r.Log.V(1).Info("updateServeDeployment", "message", fmt.Sprintf("Recorded Serve config hash %s on Ray cluster %s", serveConfigHash, rayClusterInstance.Name))
 
 	return nil
 }
@@ -648,12 +861,23 @@ func (r *RayServiceReconciler) updateServeDeployment(ctx context.Context, raySer
 // updates health timestamps, and checks if the RayCluster is overall healthy.
 // It's return values should be interpreted as
 // (Serve app healthy?, Serve app ready?, error if any)
-func (r *RayServiceReconciler) getAndCheckServeStatus(ctx context.Context, dashboardClient utils.RayDashboardClientInterface, rayServiceServeStatus *rayv1alpha1.RayServiceStatus, unhealthySecondThreshold *int32) (bool, bool, error) {
+func (r *RayServiceReconciler) getAndCheckServeStatus(ctx context.Context, dashboardClient utils.RayDashboardClientInterface, rayServiceServeStatus *rayv1alpha1.RayServiceStatus, unhealthySecondThreshold *int32, serveAPI utils.ServeAPI) (bool, bool, error) {
 	serviceUnhealthySecondThreshold := ServiceUnhealthySecondThreshold
 	if unhealthySecondThreshold != nil {
 		serviceUnhealthySecondThreshold = float64(*unhealthySecondThreshold)
 	}
 
+	if serveAPI == utils.ServeApplicationsAPI {
+		applicationStatuses, err := dashboardClient.GetApplicationsStatus(ctx)
+		if err != nil {
+			r.Log.Error(err, "Failed to get Serve application statuses from dashboard!")
+			return false, false, err
+		}
+		isHealthy, isReady := r.updateApplicationStatuses(applicationStatuses, rayServiceServeStatus, serviceUnhealthySecondThreshold)
+		return isHealthy, isReady, nil
+	}
+	rayServiceServeStatus.Applications = nil
+
 	var serveStatuses *utils.ServeDeploymentStatuses
 	var err error
 	if serveStatuses, err = dashboardClient.GetDeploymentsStatus(ctx); err != nil {
@@ -713,12 +937,68 @@ func (r *RayServiceReconciler) getAndCheckServeStatus(ctx context.Context, dashb
 	return isHealthy, isReady, nil
 }
 
-func (r *RayServiceReconciler) generateConfigKey(rayServiceInstance *rayv1alpha1.RayService, clusterName string) string {
-	return r.generateConfigKeyPrefix(rayServiceInstance) + clusterName
-}
+// updateApplicationStatuses records the statuses of the Serve applications and of their deployments, and checks
+// them like getAndCheckServeStatus does for a single application: an application or a deployment that stays
+// unhealthy for longer than serviceUnhealthySecondThreshold makes the RayCluster unhealthy.
+func (r *RayServiceReconciler) updateApplicationStatuses(applicationStatuses *utils.ServeApplicationStatuses, rayServiceServeStatus *rayv1alpha1.RayServiceStatus, serviceUnhealthySecondThreshold float64) (bool, bool) {
+	isHealthy := true
+	// Serve reports no application until the config has been applied.
+	isReady := len(applicationStatuses.Applications) > 0
+	timeNow := metav1.Now()
+	// checkHealth keeps the time the application or deployment became unhealthy, and tells whether it has
+	// been unhealthy for too long.
+	checkHealth := func(healthy bool, prevHealthy bool, prevHealthLastUpdateTime *metav1.Time) (*metav1.Time, bool) {
+		if healthy || prevHealthy || prevHealthLastUpdateTime == nil {
+			return &timeNow, false
+		}
+		return prevHealthLastUpdateTime, time.Since(prevHealthLastUpdateTime.Time).Seconds() > serviceUnhealthySecondThreshold
+	}
+
+	applications := make(map[string]rayv1alpha1.AppStatus, len(applicationStatuses.Applications))
+	for appName, application := range applicationStatuses.Applications {
+		prevApplication, exist := rayServiceServeStatus.Applications[appName]
+		appStatus := rayv1alpha1.AppStatus{
+			Status:         application.Status,
+			Message:        application.Message,
+			LastUpdateTime: &timeNow,
+			Deployments:    make(map[string]rayv1alpha1.ServeDeploymentStatus, len(application.Deployments)),
+		}
+		running := application.Status == rayv1alpha1.ApplicationStatusEnum.RUNNING
+		var tooLong bool
+		appStatus.HealthLastUpdateTime, tooLong = checkHealth(running, !exist || prevApplication.Status == rayv1alpha1.ApplicationStatusEnum.RUNNING, prevApplication.HealthLastUpdateTime)
+		if tooLong {
+			isHealthy = false
+		}
+		if !running {
+			isReady = false
+		}
 
-func (r *RayServiceReconciler) generateConfigKeyPrefix(rayServiceInstance *rayv1alpha1.RayService) string {
-	return rayServiceInstance.Namespace + "/" + rayServiceInstance.Name + "/"
+		for deploymentName, deployment := range application.Deployments {
+			prevDeployment, exist := prevApplication.Deployments[deploymentName]
+			deploymentStatus := rayv1alpha1.ServeDeploymentStatus{
+				Name:           deploymentName,
+				Status:         deployment.Status,
+				Message:        deployment.Message,
+				LastUpdateTime: &timeNow,
+			}
+			healthy := deployment.Status == rayv1alpha1.DeploymentStatusEnum.HEALTHY
+			deploymentStatus.HealthLastUpdateTime, tooLong = checkHealth(healthy, !exist || prevDeployment.Status == rayv1alpha1.DeploymentStatusEnum.HEALTHY, prevDeployment.HealthLastUpdateTime)
+			if tooLong {
+				isHealthy = false
+			}
+			if !healthy {
+				isReady = false
+			}
+			appStatus.Deployments[deploymentName] = deploymentStatus
+		}
+		applications[appName] = appStatus
+	}
+
+	rayServiceServeStatus.Applications = applications
+	rayServiceServeStatus.ApplicationStatus = rayv1alpha1.AppStatus{}
+	rayServiceServeStatus.ServeStatuses = nil
+	r.Log.V(1).Info("updateApplicationStatuses", "applications", applications, "isHealthy", isHealthy, "isReady", isReady)
+	return isHealthy, isReady
 }
 
 // Return true if healthy, otherwise false.
@@ -746,6 +1026,25 @@ func (r *RayServiceReconciler) markRestart(rayServiceInstance *rayv1alpha1.RaySe
 	}
 }
 
+// restartUnhealthyCluster prepares a new RayCluster to replace the unhealthy one.
+func (r *RayServiceReconciler) restartUnhealthyCluster(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, unhealthyClusterName string) {
+	r.markRestart(rayServiceInstance)
+	r.notifyService(ctx, rayServiceInstance, rayv1alpha1.ServiceRestarting, unhealthyClusterName, "")
+}
+
+// notifyService notifies eventType with the current status of the RayService. clusterName is the RayCluster the
+// event is about, which identifies the notification. previousClusterName is the RayCluster that served the traffic
+// before an upgrade.
+func (r *RayServiceReconciler) notifyService(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, eventType rayv1alpha1.NotificationEvent, clusterName string, previousClusterName string) {
+	data := notify.ServiceData{
+		ServiceStatus:          rayServiceInstance.Status.ServiceStatus,
+		ActiveRayClusterName:   rayServiceInstance.Status.ActiveServiceStatus.RayClusterName,
+		PendingRayClusterName:  rayServiceInstance.Status.PendingServiceStatus.RayClusterName,
+		PreviousRayClusterName: previousClusterName,
+	}
+	notify.Send(ctx, r.Client, r.Recorder, rayServiceInstance.Spec.Notifications, "rayservices", rayServiceInstance, eventType, clusterName, data)
+}
+
 func (r *RayServiceReconciler) updateRayClusterInfo(rayServiceInstance *rayv1alpha1.RayService, healthyClusterName string) {
 	r.Log.V(1).Info("updateRayClusterInfo", "ActiveRayClusterName", rayServiceInstance.Status.ActiveServiceStatus.RayClusterName, "healthyClusterName", healthyClusterName)
 	if rayServiceInstance.Status.ActiveServiceStatus.RayClusterName != healthyClusterName {
@@ -754,6 +1053,292 @@ func (r *RayServiceReconciler) updateRayClusterInfo(rayServiceInstance *rayv1alp
 	}
 }
 
+// isShiftingTraffic returns whether the RayService has a TrafficShifting upgrade strategy and a pending RayCluster
+// to shift the traffic of its active RayCluster to.
+func (r *RayServiceReconciler) isShiftingTraffic(rayServiceInstance *rayv1alpha1.RayService) bool {
+	return isTrafficShiftingEnabled(rayServiceInstance) &&
+		rayServiceInstance.Status.ActiveServiceStatus.RayClusterName != "" &&
+		rayServiceInstance.Status.PendingServiceStatus.RayClusterName != ""
+}
+
+func isTrafficShiftingEnabled(rayServiceInstance *rayv1alpha1.RayService) bool {
+	return rayServiceInstance.Spec.UpgradeStrategy != nil &&
+		rayServiceInstance.Spec.UpgradeStrategy.Type == rayv1alpha1.TrafficShiftingUpgrade
+}
+
+// shiftTraffic moves the traffic to the healthy and ready pending RayCluster by one step once the previous step has
+// lasted stepIntervalSeconds. It returns true when the pending RayCluster should receive all the traffic.
+func (r *RayServiceReconciler) shiftTraffic(rayServiceInstance *rayv1alpha1.RayService) bool {
+	trafficShifting := common.GetTrafficShiftingSpec(*rayServiceInstance)
+	pendingStatus := &rayServiceInstance.Status.PendingServiceStatus
+	var weight int32
+	if pendingStatus.TrafficWeight != nil {
+		weight = *pendingStatus.TrafficWeight
+	}
+
+	lastShiftTime := rayServiceInstance.Status.LastTrafficShiftTime
+	stepInterval := time.Duration(*trafficShifting.StepIntervalSeconds) * time.Second
+	if weight > 0 && lastShiftTime != nil && time.Since(lastShiftTime.Time) < stepInterval {
+		return false
+	}
+
+	nextWeight := int32(100)
+	for _, step := range trafficShifting.Steps {
+		if step > weight && step < 100 {
+			nextWeight = step
+			break
+		}
+	}
+	now := metav1.Now()
+	rayServiceInstance.Status.LastTrafficShiftTime = &now
+	r.Recorder.Eventf(rayServiceInstance, "Normal", "ShiftedTraffic", "Routing %d%% of the traffic to RayCluster %s", nextWeight, pendingStatus.RayClusterName)
+	if nextWeight == 100 {
+		return true
+	}
+	pendingStatus.TrafficWeight = &nextWeight
+	return false
+}
+
+// reconcileTrafficRouting routes the traffic of a RayService with a TrafficShifting upgrade strategy between the
+// serve services of its active and pending RayClusters, according to the traffic weight of the pending one.
+func (r *RayServiceReconciler) reconcileTrafficRouting(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, activeRayClusterInstance *rayv1alpha1.RayCluster, pendingRayClusterInstance *rayv1alpha1.RayCluster) error {
+	status := &rayServiceInstance.Status
+	if err := r.deleteUnusedTrafficRouting(ctx, rayServiceInstance); err != nil {
+		return err
+	}
+	if !isTrafficShiftingEnabled(rayServiceInstance) {
+		status.ActiveServiceStatus.TrafficWeight = nil
+		status.PendingServiceStatus.TrafficWeight = nil
+		return nil
+	}
+
+	// reconcileServe may have promoted the pending RayCluster, so the clusters are matched by name.
+	var activeCluster, pendingCluster *rayv1alpha1.RayCluster
+	for _, cluster := range []*rayv1alpha1.RayCluster{activeRayClusterInstance, pendingRayClusterInstance} {
+		if cluster == nil {
+			continue
+		}
+		if cluster.Name == status.ActiveServiceStatus.RayClusterName {
+			activeCluster = cluster
+		} else if cluster.Name == status.PendingServiceStatus.RayClusterName && status.PendingServiceStatus.TrafficWeight != nil {
+			pendingCluster = cluster
+		}
+	}
+	if activeCluster == nil {
+		return nil
+	}
+	var pendingWeight int32
+	if pendingCluster != nil {
+		pendingWeight = *status.PendingServiceStatus.TrafficWeight
+	}
+	activeWeight := 100 - pendingWeight
+	status.ActiveServiceStatus.TrafficWeight = &activeWeight
+
+	for _, cluster := range []*rayv1alpha1.RayCluster{activeCluster, pendingCluster} {
+		if cluster == nil {
+			continue
+		}
+		if err := r.reconcileServices(ctx, rayServiceInstance, cluster, common.ClusterServingService); err != nil {
+			return err
+		}
+	}
+
+	trafficShifting := common.GetTrafficShiftingSpec(*rayServiceInstance)
+	if trafficShifting.Router == rayv1alpha1.GatewayRouter {
+		route, err := common.BuildHTTPRouteForRayService(*rayServiceInstance, *activeCluster, pendingCluster, pendingWeight)
+		if err != nil {
+			return err
+		}
+		return r.createOrUpdateRoute(ctx, rayServiceInstance, route)
+	}
+
+	ingress, err := common.BuildServeIngressForRayService(*rayServiceInstance, *activeCluster, false, 0)
+	if err != nil {
+		return err
+	}
+	if err := r.createOrUpdateServeIngress(ctx, rayServiceInstance, ingress); err != nil {
+		return err
+	}
+	if pendingCluster != nil {
+		canaryIngress, err := common.BuildServeIngressForRayService(*rayServiceInstance, *pendingCluster, true, pendingWeight)
+		if err != nil {
+			return err
+		}
+		return r.createOrUpdateServeIngress(ctx, rayServiceInstance, canaryIngress)
+	}
+	return r.deleteServeIngress(ctx, rayServiceInstance, utils.GenerateServeIngressName(rayServiceInstance.Name, true))
+}
+
+// deleteUnusedTrafficRouting deletes the objects of the traffic routers that don't route the traffic of the RayService,
+// e.g. once TrafficShifting is disabled. The Ingress is kept while serveIngress owns it.
+func (r *RayServiceReconciler) deleteUnusedTrafficRouting(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) error {
+	var router rayv1alpha1.TrafficRouter
+	if isTrafficShiftingEnabled(rayServiceInstance) {
+		router = common.GetTrafficShiftingSpec(*rayServiceInstance).Router
+	}
+	if router != rayv1alpha1.IngressRouter {
+		if rayServiceInstance.Spec.ServeIngress == nil {
+			if err := r.deleteServeIngress(ctx, rayServiceInstance, utils.GenerateServeIngressName(rayServiceInstance.Name, false)); err != nil {
+				return err
+			}
+		}
+		if err := r.deleteServeIngress(ctx, rayServiceInstance, utils.GenerateServeIngressName(rayServiceInstance.Name, true)); err != nil {
+			return err
+		}
+	}
+	if router != rayv1alpha1.GatewayRouter {
+		return r.deleteRoute(ctx, rayServiceInstance, common.HTTPRouteGroupVersionKind, utils.GenerateServeRouteName(rayServiceInstance.Name))
+	}
+	return nil
+}
+
+// deleteServeIngress deletes an Ingress of the RayService, if it exists. An Ingress with the same name that the
+// RayService doesn't control is left alone.
+func (r *RayServiceReconciler) deleteServeIngress(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, name string) error {
+	ingress := &networkingv1.Ingress{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: rayServiceInstance.Namespace}, ingress); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(ingress, rayServiceInstance) {
+		return nil
+	}
+	if err := r.Delete(ctx, ingress); client.IgnoreNotFound(err) != nil {
+		r.Log.Error(err, "Serve Ingress delete error!", "Ingress", name)
+		return err
+	}
+	return nil
+}
+
+// deleteRoute deletes an HTTPRoute or a GRPCRoute of the RayService, if it exists. A route with the same name that
+// the RayService doesn't control is left alone, and nothing is deleted if the Gateway API is not installed.
+func (r *RayServiceReconciler) deleteRoute(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, gvk schema.GroupVersionKind, name string) error {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gvk)
+	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: rayServiceInstance.Namespace}, route); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(route, rayServiceInstance) {
+		return nil
+	}
+	if err := r.Delete(ctx, route); client.IgnoreNotFound(err) != nil {
+		r.Log.Error(err, gvk.Kind+" delete error!", "Route", name)
+		return err
+	}
+	return nil
+}
+
+func (r *RayServiceReconciler) createOrUpdateServeIngress(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, ingress *networkingv1.Ingress) error {
+	existingIngress := &networkingv1.Ingress{}
+	err := r.Get(ctx, client.ObjectKey{Name: ingress.Name, Namespace: ingress.Namespace}, existingIngress)
+	if err == nil {
+		existingIngress.Labels = ingress.Labels
+		existingIngress.Annotations = ingress.Annotations
+		existingIngress.Spec = ingress.Spec
+		if updateErr := r.Update(ctx, existingIngress); updateErr != nil {
+			r.Log.Error(updateErr, "Serve Ingress Update error!", "Ingress.Error", updateErr)
+			return updateErr
+		}
+	} else if errors.IsNotFound(err) {
+		if err := ctrl.SetControllerReference(rayServiceInstance, ingress, r.Scheme); err != nil {
+			return err
+		}
+		if createErr := r.Create(ctx, ingress); createErr != nil && !errors.IsAlreadyExists(createErr) {
+			r.Log.Error(createErr, "Serve Ingress create error!", "Ingress.Error", createErr)
+			return createErr
+		}
+	} else {
+		r.Log.Error(err, "Serve Ingress get error!")
+		return err
+	}
+
+	return nil
+}
+
+// createOrUpdateRoute creates or updates an HTTPRoute or a GRPCRoute of the Gateway API.
+func (r *RayServiceReconciler) createOrUpdateRoute(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, route *unstructured.Unstructured) error {
+	kind := route.GetKind()
+	existingRoute := &unstructured.Unstructured{}
+	existingRoute.SetGroupVersionKind(route.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKey{Name: route.GetName(), Namespace: route.GetNamespace()}, existingRoute)
+	if err == nil {
+		existingRoute.SetLabels(route.GetLabels())
+		existingRoute.Object["spec"] = route.Object["spec"]
+		if updateErr := r.Update(ctx, existingRoute); updateErr != nil {
+			r.Log.Error(updateErr, kind+" Update error!", "Route.Error", updateErr)
+			return updateErr
+		}
+	} else if errors.IsNotFound(err) {
+		if err := ctrl.SetControllerReference(rayServiceInstance, route, r.Scheme); err != nil {
+			return err
+		}
+		if createErr := r.Create(ctx, route); createErr != nil && !errors.IsAlreadyExists(createErr) {
+			r.Log.Error(createErr, kind+" create error!", "Route.Error", createErr)
+			return createErr
+		}
+	} else {
+		r.Log.Error(err, kind+" get error!")
+		return err
+	}
+
+	return nil
+}
+
+// reconcileServeIngress exposes the serve service outside the Kubernetes cluster. The Ingress targets the serve
+// service, so it follows the active RayCluster without being updated at switchovers. With the Ingress router of a
+// TrafficShifting upgrade, reconcileTrafficRouting owns the Ingress instead. Otherwise, the Ingress is deleted once
+// serveIngress is unset, if the RayService controls it.
+func (r *RayServiceReconciler) reconcileServeIngress(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) error {
+	if isTrafficShiftingEnabled(rayServiceInstance) && common.GetTrafficShiftingSpec(*rayServiceInstance).Router == rayv1alpha1.IngressRouter {
+		return nil
+	}
+	if rayServiceInstance.Spec.ServeIngress == nil {
+		return r.deleteServeIngress(ctx, rayServiceInstance, utils.GenerateServeIngressName(rayServiceInstance.Name, false))
+	}
+
+	ingress, err := common.BuildIngressForServeService(*rayServiceInstance, *rayClusterInstance)
+	if err != nil {
+		return err
+	}
+	return r.createOrUpdateServeIngress(ctx, rayServiceInstance, ingress)
+}
+
+// reconcileServeGRPCRoute exposes the gRPC port of the serve service outside the Kubernetes cluster. The route
+// targets the serve service, so it follows the active RayCluster without being updated at switchovers. The Ingress
+// or the GRPCRoute that doesn't route the gRPC traffic anymore is deleted, if the RayService controls it.
+func (r *RayServiceReconciler) reconcileServeGRPCRoute(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) error {
+	ingressName := utils.GenerateServeGRPCIngressName(rayServiceInstance.Name)
+	routeName := utils.GenerateServeGRPCRouteName(rayServiceInstance.Name)
+	if rayServiceInstance.Spec.ServeGRPC == nil || rayServiceInstance.Spec.ServeGRPC.Route == nil {
+		if err := r.deleteServeIngress(ctx, rayServiceInstance, ingressName); err != nil {
+			return err
+		}
+		return r.deleteRoute(ctx, rayServiceInstance, common.GRPCRouteGroupVersionKind, routeName)
+	}
+
+	if rayServiceInstance.Spec.ServeGRPC.Route.Router == rayv1alpha1.GatewayRouter {
+		if err := r.deleteServeIngress(ctx, rayServiceInstance, ingressName); err != nil {
+			return err
+		}
+		route, err := common.BuildGRPCRouteForRayService(*rayServiceInstance, *rayClusterInstance)
+		if err != nil {
+			return err
+		}
+		return r.createOrUpdateRoute(ctx, rayServiceInstance, route)
+	}
+
+	if err := r.deleteRoute(ctx, rayServiceInstance, common.GRPCRouteGroupVersionKind, routeName); err != nil {
+		return err
+	}
+	ingress, err := common.BuildServeGRPCIngressForRayService(*rayServiceInstance, *rayClusterInstance)
+	if err != nil {
+		return err
+	}
+	return r.createOrUpdateServeIngress(ctx, rayServiceInstance, ingress)
+}
+
 // TODO: When start Ingress in RayService, we can disable the Ingress from RayCluster.
 func (r *RayServiceReconciler) reconcileIngress(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) error {
 	if rayClusterInstance.Spec.HeadGroupSpec.EnableIngress == nil || !*rayClusterInstance.Spec.HeadGroupSpec.EnableIngress {
@@ -809,6 +1394,8 @@ func (r *RayServiceReconciler) reconcileServices(ctx context.Context, rayService
 		raySvc, err = common.BuildHeadServiceForRayService(*rayServiceInstance, *rayClusterInstance)
 	} else if serviceType == common.ServingService {
 		raySvc, err = common.BuildServeServiceForRayService(*rayServiceInstance, *rayClusterInstance)
+	} else if serviceType == common.ClusterServingService {
+		raySvc, err = common.BuildServeServiceForRayCluster(*rayServiceInstance, *rayClusterInstance)
 	}
 
 	if err != nil {
@@ -831,7 +1418,12 @@ func (r *RayServiceReconciler) reconcileServices(ctx context.Context, rayService
 	} else if errors.IsNotFound(err) {
 		// Create Service
 		r.Log.V(1).Info("reconcileServices create service")
-		if err := ctrl.SetControllerReference(rayServiceInstance, raySvc, r.Scheme); err != nil {
+		// The serve service of a single RayCluster is deleted with the RayCluster.
+		var owner metav1.Object = rayServiceInstance
+		if serviceType == common.ClusterServingService {
+			owner = rayClusterInstance
+		}
+		if err := ctrl.SetControllerReference(owner, raySvc, r.Scheme); err != nil {
 			return err
 		}
 		if createErr := r.Create(ctx, raySvc); createErr != nil {
@@ -865,8 +1457,13 @@ func (r *RayServiceReconciler) updateStatusForActiveCluster(ctx context.Context,
 	rayDashboardClient := utils.GetRayDashboardClientFunc()
 	rayDashboardClient.InitClient(clientURL)
 
+	serveAPI, err := utils.GetServeAPI(&rayServiceInstance.Spec)
+	if err != nil {
+		return err
+	}
+
 	var isHealthy, isReady bool
-	if isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, rayDashboardClient, rayServiceStatus, rayServiceInstance.Spec.ServiceUnhealthySecondThreshold); err != nil {
+	if isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, rayDashboardClient, rayServiceStatus, rayServiceInstance.Spec.ServiceUnhealthySecondThreshold, serveAPI); err != nil {
 		r.updateAndCheckDashboardStatus(rayServiceStatus, false, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold)
 		return err
 	}
@@ -890,6 +1487,14 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	var clientURL string
 	var rayServiceStatus *rayv1alpha1.RayServiceStatus
 
+	serveAPI, err := utils.GetServeAPI(&rayServiceInstance.Spec)
+	if err != nil {
+		// An invalid Serve config is not a reason to restart the RayCluster.
+		r.Recorder.Event(rayServiceInstance, corev1.EventTypeWarning, string(rayv1alpha1.InvalidServeConfig), err.Error())
+		err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.InvalidServeConfig, err)
+		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, false, err
+	}
+
 	// Pick up service status to be updated.
 	if isActive {
 		rayServiceStatus = &rayServiceInstance.Status.ActiveServiceStatus
@@ -900,7 +1505,7 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	if clientURL, err = utils.FetchDashboardAgentURL(ctx, &r.Log, r.Client, rayClusterInstance); err != nil || clientURL == "" {
 		if !r.updateAndCheckDashboardStatus(rayServiceStatus, false, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold) {
 			logger.Info("Dashboard is unhealthy, restart the cluster.")
-			r.markRestart(rayServiceInstance)
+			r.restartUnhealthyCluster(ctx, rayServiceInstance, rayClusterInstance.Name)
 		}
 		err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.WaitForDashboard, err)
 		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, false, err
@@ -927,10 +1532,10 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	shouldUpdate := r.checkIfNeedSubmitServeDeployment(rayServiceInstance, rayClusterInstance, rayServiceStatus)
 
 	if shouldUpdate {
-		if err = r.updateServeDeployment(ctx, rayServiceInstance, rayDashboardClient, rayClusterInstance.Name); err != nil {
+		if err = r.updateServeDeployment(ctx, rayServiceInstance, rayDashboardClient, rayClusterInstance, serveAPI); err != nil {
 			if !r.updateAndCheckDashboardStatus(rayServiceStatus, false, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold) {
 				logger.Info("Dashboard is unhealthy, restart the cluster.")
-				r.markRestart(rayServiceInstance)
+				r.restartUnhealthyCluster(ctx, rayServiceInstance, rayClusterInstance.Name)
 			}
 			err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.WaitForServeDeploymentReady, err)
 			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, false, err
@@ -941,10 +1546,10 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	}
 
 	var isHealthy, isReady bool
-	if isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, rayDashboardClient, rayServiceStatus, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold); err != nil {
+	if isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, rayDashboardClient, rayServiceStatus, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold, serveAPI); err != nil {
 		if !r.updateAndCheckDashboardStatus(rayServiceStatus, false, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold) {
 			logger.Info("Dashboard is unhealthy, restart the cluster.")
-			r.markRestart(rayServiceInstance)
+			r.restartUnhealthyCluster(ctx, rayServiceInstance, rayClusterInstance.Name)
 		}
 		err = r.updateState(ctx, rayServiceInstance, rayv1alpha1.FailedToGetServeDeploymentStatus, err)
 		return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, false, err
@@ -952,12 +1557,29 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 
 	r.updateAndCheckDashboardStatus(rayServiceStatus, true, rayServiceInstance.Spec.DeploymentUnhealthySecondThreshold)
 
+	// Serve may report healthy applications that fail their requests.
+	if isHealthy && isReady && len(rayServiceInstance.Spec.ApplicationProbes) > 0 {
+		isHealthy, isReady = r.checkApplicationProbes(ctx, rayServiceInstance, rayClusterInstance, rayServiceStatus)
+	}
+
 	logger.Info("Check serve health", "isHealthy", isHealthy, "isReady", isReady, "isActive", isActive)
 
 	if isHealthy && isReady {
+		if !isActive && r.isShiftingTraffic(rayServiceInstance) && !r.shiftTraffic(rayServiceInstance) {
+			rayServiceInstance.Status.ServiceStatus = rayv1alpha1.ShiftingTraffic
+			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, isHealthy, isReady, nil
+		}
+		previousServiceStatus := rayServiceInstance.Status.ServiceStatus
+		previousClusterName := rayServiceInstance.Status.ActiveServiceStatus.RayClusterName
 		rayServiceInstance.Status.ServiceStatus = rayv1alpha1.Running
 		r.updateRayClusterInfo(rayServiceInstance, rayClusterInstance.Name)
+		r.markRevisionHealthy(ctx, rayServiceInstance, rayClusterInstance)
 		r.Recorder.Event(rayServiceInstance, "Normal", "Running", "The Serve applicaton is now running and healthy.")
+		if previousClusterName != "" && previousClusterName != rayServiceInstance.Status.ActiveServiceStatus.RayClusterName {
+			r.notifyService(ctx, rayServiceInstance, rayv1alpha1.ServiceUpgradeComplete, rayClusterInstance.Name, previousClusterName)
+		} else if previousServiceStatus != rayv1alpha1.Running {
+			r.notifyService(ctx, rayServiceInstance, rayv1alpha1.ServiceRunning, rayClusterInstance.Name, "")
+		}
 	} else if isHealthy && !isReady {
 		rayServiceInstance.Status.ServiceStatus = rayv1alpha1.WaitForServeDeploymentReady
 		if err := r.Status().Update(ctx, rayServiceInstance); err != nil {
@@ -966,7 +1588,7 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 		logger.Info("Mark cluster as waiting for Serve deployments", "rayCluster", rayClusterInstance)
 	} else if !isHealthy {
 		// NOTE: When isHealthy is false, isReady is guaranteed to be false.
-		r.markRestart(rayServiceInstance)
+		r.restartUnhealthyCluster(ctx, rayServiceInstance, rayClusterInstance.Name)
 		rayServiceInstance.Status.ServiceStatus = rayv1alpha1.Restarting
 		if err := r.Status().Update(ctx, rayServiceInstance); err != nil {
 			return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, false, false, err
@@ -981,37 +1603,671 @@ func (r *RayServiceReconciler) reconcileServe(ctx context.Context, rayServiceIns
 	return ctrl.Result{RequeueAfter: ServiceDefaultRequeueDuration}, isHealthy, isReady, nil
 }
 
-func (r *RayServiceReconciler) labelHealthyServePods(ctx context.Context, rayClusterInstance *rayv1alpha1.RayCluster) error {
+// checkApplicationProbes sends the requests of the application probes of the RayService that are due to the Serve
+// HTTP proxy on the head of the RayCluster. It returns whether the RayCluster is healthy, which is false once a probe
+// reaches its failure threshold, and whether it is ready, which needs the last request of every probe to succeed.
+func (r *RayServiceReconciler) checkApplicationProbes(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster, rayServiceStatus *rayv1alpha1.RayServiceStatus) (bool, bool) {
+	serveURL, urlErr := r.getServeURL(ctx, rayClusterInstance)
+	probeStatuses := make(map[string]rayv1alpha1.ApplicationProbeStatus, len(rayServiceInstance.Spec.ApplicationProbes))
+	isHealthy, isReady := true, true
+
+	for i := range rayServiceInstance.Spec.ApplicationProbes {
+		probe := &rayServiceInstance.Spec.ApplicationProbes[i]
+		periodSeconds := int32(common.DefaultApplicationProbePeriodSeconds)
+		if probe.PeriodSeconds != nil {
+			periodSeconds = *probe.PeriodSeconds
+		}
+		timeoutSeconds := int32(common.DefaultApplicationProbeTimeoutSeconds)
+		if probe.TimeoutSeconds != nil {
+			timeoutSeconds = *probe.TimeoutSeconds
+		}
+		failureThreshold := int32(common.DefaultApplicationProbeFailureThreshold)
+		if probe.FailureThreshold != nil {
+			failureThreshold = *probe.FailureThreshold
+		}
+
+		probeStatus := rayServiceStatus.ApplicationProbes[probe.Name]
+		if probeStatus.LastProbeTime == nil || time.Since(probeStatus.LastProbeTime.Time) >= time.Duration(periodSeconds)*time.Second {
+			probeErr := urlErr
+			if probeErr == nil {
+				probeErr = utils.ProbeServeApplication(ctx, serveURL, probe, time.Duration(timeoutSeconds)*time.Second)
+			}
+			now := metav1.Now()
+			probeStatus.LastProbeTime = &now
+			if probeErr == nil {
+				probeStatus.Succeeded = true
+				probeStatus.ConsecutiveFailures = 0
+				probeStatus.Message = ""
+			} else {
+				probeStatus.Succeeded = false
+				probeStatus.ConsecutiveFailures++
+				probeStatus.Message = probeErr.Error()
+				r.Log.Info("Application probe failed", "probe", probe.Name, "rayCluster", rayClusterInstance.Name, "consecutiveFailures", probeStatus.ConsecutiveFailures, "error", probeErr.Error())
+				if probeStatus.ConsecutiveFailures == failureThreshold {
+					r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "ApplicationProbeFailed",
+						"Application probe %s failed %d times on cluster %s: %s", probe.Name, failureThreshold, rayClusterInstance.Name, probeErr.Error())
+				}
+			}
+		}
+		probeStatuses[probe.Name] = probeStatus
+
+		if probeStatus.ConsecutiveFailures >= failureThreshold {
+			isHealthy = false
+		}
+		if !probeStatus.Succeeded {
+			isReady = false
+		}
+	}
+
+	rayServiceStatus.ApplicationProbes = probeStatuses
+	return isHealthy, isHealthy && isReady
+}
+
+// getServeURL returns the URL of the Serve HTTP proxy on the head of the RayCluster.
+func (r *RayServiceReconciler) getServeURL(ctx context.Context, rayClusterInstance *rayv1alpha1.RayCluster) (string, error) {
+	podList := corev1.PodList{}
+	filterLabels := client.MatchingLabels{common.RayClusterLabelKey: rayClusterInstance.Name, common.RayNodeTypeLabelKey: string(rayv1alpha1.HeadNode)}
+	if err := r.List(ctx, &podList, client.InNamespace(rayClusterInstance.Namespace), filterLabels); err != nil {
+		return "", err
+	}
+	if len(podList.Items) != 1 {
+		return "", fmt.Errorf("Found %d head pods for RayCluster %s in the namespace %s", len(podList.Items), rayClusterInstance.Name, rayClusterInstance.Namespace)
+	}
+
+	headPod := &podList.Items[0]
+	rayContainer := headPod.Spec.Containers[utils.FindRayContainerIndex(headPod.Spec)]
+	servingPort := utils.FindContainerPort(&rayContainer, common.DefaultServingPortName, common.DefaultServingPort)
+	return fmt.Sprintf("http://%s:%d", headPod.Status.PodIP, servingPort), nil
+}
+
+// labelHealthyServePods checks the Serve HTTP proxies of the pods of the RayCluster concurrently, and labels the pods
+// the serve Service routes the traffic to. The label of a pod only changes after the consecutive results of its health
+// checks reach the success or failure threshold.
+func (r *RayServiceReconciler) labelHealthyServePods(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) error {
 	allPods := corev1.PodList{}
 	filterLabels := client.MatchingLabels{common.RayClusterLabelKey: rayClusterInstance.Name}
 
 	if err := r.List(ctx, &allPods, client.InNamespace(rayClusterInstance.Namespace), filterLabels); err != nil {
 		return err
 	}
+	r.servePodHealth.prune(rayClusterInstance.Name, allPods.Items)
 
-	httpProxyClient := utils.GetRayHttpProxyClientFunc()
-	httpProxyClient.InitClient()
-	for _, pod := range allPods.Items {
-		rayContainer := pod.Spec.Containers[utils.FindRayContainerIndex(pod.Spec)]
-		servingPort := utils.FindContainerPort(&rayContainer, common.DefaultServingPortName, common.DefaultServingPort)
-		httpProxyClient.SetHostIp(pod.Status.PodIP, servingPort)
-		if pod.Labels == nil {
-			pod.Labels = make(map[string]string)
+	timeoutSeconds := int32(common.DefaultServeProxyHealthCheckTimeoutSeconds)
+	successThreshold := int32(common.DefaultServeProxyHealthCheckSuccessThreshold)
+	failureThreshold := int32(common.DefaultServeProxyHealthCheckFailureThreshold)
+	if healthCheck := rayServiceInstance.Spec.ServeProxyHealthCheck; healthCheck != nil {
+		if healthCheck.TimeoutSeconds != nil {
+			timeoutSeconds = *healthCheck.TimeoutSeconds
+		}
+		if healthCheck.SuccessThreshold != nil {
+			successThreshold = *healthCheck.SuccessThreshold
+		}
+		if healthCheck.FailureThreshold != nil {
+			failureThreshold = *healthCheck.FailureThreshold
 		}
-		if httpProxyClient.CheckHealth() == nil {
-			pod.Labels[common.RayClusterServingServiceLabelKey] = common.EnableRayClusterServingServiceTrue
+	}
+
+	workers := ServeHealthCheckConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(allPods.Items) {
+		workers = len(allPods.Items)
+	}
+	podIndices := make(chan int)
+	errs := make(chan error, len(allPods.Items))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			httpProxyClient := utils.GetRayHttpProxyClientFunc()
+			httpProxyClient.InitClient(time.Duration(timeoutSeconds) * time.Second)
+			for podIndex := range podIndices {
+				pod := &allPods.Items[podIndex]
+				rayContainer := pod.Spec.Containers[utils.FindRayContainerIndex(pod.Spec)]
+				servingPort := utils.FindContainerPort(&rayContainer, common.DefaultServingPortName, common.DefaultServingPort)
+				httpProxyClient.SetHostIp(pod.Status.PodIP, servingPort)
+				isHealthy := r.servePodHealth.record(pod, rayClusterInstance.Name, httpProxyClient.CheckHealth() == nil, successThreshold, failureThreshold)
+				if err := r.labelServePod(ctx, pod, isHealthy); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for i := range allPods.Items {
+		podIndices <- i
+	}
+	close(podIndices)
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// labelServePod patches the serving label of the pod when its health changes.
+func (r *RayServiceReconciler) labelServePod(ctx context.Context, pod *corev1.Pod, isHealthy bool) error {
+	servingLabel := common.EnableRayClusterServingServiceFalse
+	if isHealthy {
+		servingLabel = common.EnableRayClusterServingServiceTrue
+	}
+	if currentLabel, exist := pod.Labels[common.RayClusterServingServiceLabelKey]; exist && currentLabel == servingLabel {
+		return nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[common.RayClusterServingServiceLabelKey] = servingLabel
+	if err := r.Patch(ctx, pod, patch); client.IgnoreNotFound(err) != nil {
+		r.Log.Error(err, "Pod label Patch error!", "Pod", pod.Name)
+		return err
+	}
+	return nil
+}
+
+// servePodHealth is the result of the consecutive health checks of the Serve HTTP proxy of a pod.
+type servePodHealth struct {
+	clusterName   string
+	isHealthy     bool
+	successes     int32
+	failures      int32
+	lastCheckTime time.Time
+}
+
+// servePodHealthCache keeps the results of the health checks of the Serve HTTP proxies, keyed by pod UID. Its zero
+// value is ready to use.
+type servePodHealthCache struct {
+	mu      sync.Mutex
+	results map[types.UID]*servePodHealth
+}
+
+// record records the result of a health check of the pod, and returns whether the pod is healthy. A pod that hasn't
+// been checked yet keeps the health of its serving label until a threshold is reached.
+func (c *servePodHealthCache) record(pod *corev1.Pod, clusterName string, success bool, successThreshold int32, failureThreshold int32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.results == nil {
+		c.results = make(map[types.UID]*servePodHealth)
+	}
+
+	result, exist := c.results[pod.UID]
+	if !exist {
+		result = &servePodHealth{
+			clusterName: clusterName,
+			isHealthy:   pod.Labels[common.RayClusterServingServiceLabelKey] == common.EnableRayClusterServingServiceTrue,
+		}
+		c.results[pod.UID] = result
+	}
+	result.lastCheckTime = time.Now()
+	if success {
+		result.failures = 0
+		if result.successes < successThreshold {
+			result.successes++
+		}
+		if result.successes >= successThreshold {
+			result.isHealthy = true
+		}
+	} else {
+		result.successes = 0
+		if result.failures < failureThreshold {
+			result.failures++
+		}
+		if result.failures >= failureThreshold {
+			result.isHealthy = false
+		}
+	}
+	return result.isHealthy
+}
+
+// prune forgets the pods of the RayCluster that no longer exist, and the pods that haven't been checked for a while,
+// e.g. the pods of deleted RayClusters.
+func (c *servePodHealthCache) prune(clusterName string, pods []corev1.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	podUIDs := make(map[types.UID]bool, len(pods))
+	for _, pod := range pods {
+		podUIDs[pod.UID] = true
+	}
+	for uid, result := range c.results {
+		if (result.clusterName == clusterName && !podUIDs[uid]) || time.Since(result.lastCheckTime) > ServePodHealthExpiration {
+			delete(c.results, uid)
+		}
+	}
+}
+
+// isRayClusterSpecUpdatableInPlace returns whether goalSpec only differs from the spec rayClusterInstance was created
+// or last updated with in the replicas, minReplicas and maxReplicas of its worker groups, and in added worker groups.
+// The previous spec is rebuilt from goalSpec and checked against the hash annotation of the RayCluster, so that the
+// defaults set by the API server on the RayCluster don't matter.
+func isRayClusterSpecUpdatableInPlace(rayClusterInstance *rayv1alpha1.RayCluster, goalSpec rayv1alpha1.RayClusterSpec) (bool, error) {
+	currentGroups := make(map[string]rayv1alpha1.WorkerGroupSpec, len(rayClusterInstance.Spec.WorkerGroupSpecs))
+	for _, group := range rayClusterInstance.Spec.WorkerGroupSpecs {
+		currentGroups[group.GroupName] = group
+	}
+
+	previousSpec := goalSpec.DeepCopy()
+	previousSpec.WorkerGroupSpecs = nil
+	for _, goalGroup := range goalSpec.WorkerGroupSpecs {
+		currentGroup, exist := currentGroups[goalGroup.GroupName]
+		if !exist {
+			// An added worker group.
+			continue
+		}
+		delete(currentGroups, goalGroup.GroupName)
+		group := goalGroup.DeepCopy()
+		group.MinReplicas = currentGroup.MinReplicas
+		group.MaxReplicas = currentGroup.MaxReplicas
+		previousSpec.WorkerGroupSpecs = append(previousSpec.WorkerGroupSpecs, *group)
+	}
+	if len(currentGroups) > 0 {
+		// Removing a worker group needs a new RayCluster.
+		return false, nil
+	}
+
+	previousHash, err := generateRayClusterJsonHash(*previousSpec)
+	if err != nil {
+		return false, err
+	}
+	return previousHash == rayClusterInstance.Annotations[common.RayServiceClusterHashKey], nil
+}
+
+// updateRayClusterInPlace applies the scaling of the worker groups and the added worker groups of the RayClusterSpec
+// of the RayService to rayClusterInstance. The replicas of an autoscaling RayCluster are left to the autoscaler.
+// It returns false, without updating the RayCluster, if the RayClusterSpec has other changes.
+func (r *RayServiceReconciler) updateRayClusterInPlace(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) (bool, error) {
+	goalSpec := rayServiceInstance.Spec.RayClusterSpec
+	inPlace, err := isRayClusterSpecUpdatableInPlace(rayClusterInstance, goalSpec)
+	if err != nil || !inPlace {
+		return false, err
+	}
+	goalHash, err := generateRayClusterJsonHash(goalSpec)
+	if err != nil {
+		return false, err
+	}
+
+	updatedRayCluster := rayClusterInstance.DeepCopy()
+	isAutoscaling := updatedRayCluster.Spec.EnableInTreeAutoscaling != nil && *updatedRayCluster.Spec.EnableInTreeAutoscaling
+	currentGroups := make(map[string]rayv1alpha1.WorkerGroupSpec, len(updatedRayCluster.Spec.WorkerGroupSpecs))
+	for _, group := range updatedRayCluster.Spec.WorkerGroupSpecs {
+		currentGroups[group.GroupName] = group
+	}
+	updatedRayCluster.Spec.WorkerGroupSpecs = make([]rayv1alpha1.WorkerGroupSpec, 0, len(goalSpec.WorkerGroupSpecs))
+	for _, goalGroup := range goalSpec.WorkerGroupSpecs {
+		group, exist := currentGroups[goalGroup.GroupName]
+		if !exist {
+			group = *goalGroup.DeepCopy()
 		} else {
-			pod.Labels[common.RayClusterServingServiceLabelKey] = common.EnableRayClusterServingServiceFalse
+			group.MinReplicas = goalGroup.MinReplicas
+			group.MaxReplicas = goalGroup.MaxReplicas
+			if !isAutoscaling {
+				group.Replicas = goalGroup.Replicas
+			}
 		}
-		if updateErr := r.Update(ctx, &pod); updateErr != nil {
-			r.Log.Error(updateErr, "Pod label Update error!", "Pod.Error", updateErr)
-			return updateErr
+		updatedRayCluster.Spec.WorkerGroupSpecs = append(updatedRayCluster.Spec.WorkerGroupSpecs, group)
+	}
+	if updatedRayCluster.Annotations == nil {
+		updatedRayCluster.Annotations = make(map[string]string)
+	}
+	updatedRayCluster.Annotations[common.RayServiceClusterHashKey] = goalHash
+
+	if reflect.DeepEqual(updatedRayCluster.Spec, rayClusterInstance.Spec) && reflect.DeepEqual(updatedRayCluster.Annotations, rayClusterInstance.Annotations) {
+		return true, nil
+	}
+	r.Log.V(1).Info("updateRayClusterInPlace", "rayCluster", rayClusterInstance.Name, "workerGroupSpecs", updatedRayCluster.Spec.WorkerGroupSpecs)
+	if err := r.Update(ctx, updatedRayCluster); err != nil {
+		r.Log.Error(err, "Fail to update RayCluster "+rayClusterInstance.Name+" in place")
+		return false, err
+	}
+	*rayClusterInstance = *updatedRayCluster
+	r.Recorder.Eventf(rayServiceInstance, "Normal", "UpdatedRayCluster", "Updated the worker groups of RayCluster %s in place", rayClusterInstance.Name)
+	return true, nil
+}
+
+// rayServiceRevision is the config of a RayService recorded in a ControllerRevision.
+type rayServiceRevision struct {
+	RayClusterSpec           rayv1alpha1.RayClusterSpec           `json:"rayClusterConfig"`
+	ServeDeploymentGraphSpec rayv1alpha1.ServeDeploymentGraphSpec `json:"serveConfig,omitempty"`
+	ServeApplications        []rayv1alpha1.ServeApplicationSpec   `json:"serveApplications,omitempty"`
+	ServeConfigV2            string                               `json:"serveConfigV2,omitempty"`
+}
+
+func newRayServiceRevision(spec *rayv1alpha1.RayServiceSpec) rayServiceRevision {
+	return rayServiceRevision{
+		RayClusterSpec:           spec.RayClusterSpec,
+		ServeDeploymentGraphSpec: spec.ServeDeploymentGraphSpec,
+		ServeApplications:        spec.ServeApplications,
+		ServeConfigV2:            spec.ServeConfigV2,
+	}
+}
+
+func (revision rayServiceRevision) applyTo(spec *rayv1alpha1.RayServiceSpec) {
+	spec.RayClusterSpec = revision.RayClusterSpec
+	spec.ServeDeploymentGraphSpec = revision.ServeDeploymentGraphSpec
+	spec.ServeApplications = revision.ServeApplications
+	spec.ServeConfigV2 = revision.ServeConfigV2
+}
+
+// reconcileRevisions records the config of the RayService in a ControllerRevision, and rolls the RayService back when
+// a rollback is requested with an annotation or when an update doesn't become healthy before the auto rollback
+// deadline. The revision status is persisted right away, and the spec of rayServiceInstance is then replaced in memory
+// by the revision it is rolled back to, if any. As the status update returns the stored spec, it must be the last
+// update of the RayService before the spec is used.
+func (r *RayServiceReconciler) reconcileRevisions(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) error {
+	originalStatus := rayServiceInstance.Status.DeepCopy()
+
+	if err := r.handleRollbackRequest(ctx, rayServiceInstance); err != nil {
+		return err
+	}
+
+	// A new spec replaces the revision the RayService was rolled back to.
+	if rayServiceInstance.Status.RollbackRevision != "" && rayServiceInstance.Status.RollbackGeneration != rayServiceInstance.Generation {
+		r.Log.Info("The RayService spec changed after its rollback.", "rollbackRevision", rayServiceInstance.Status.RollbackRevision)
+		rayServiceInstance.Status.RollbackRevision = ""
+		rayServiceInstance.Status.RollbackGeneration = 0
+	}
+
+	goal, err := r.getGoalRevision(ctx, rayServiceInstance)
+	if err != nil {
+		return err
+	}
+
+	rolledBack, err := r.checkAutoRollback(ctx, rayServiceInstance)
+	if err != nil {
+		return err
+	}
+	if rolledBack {
+		if goal, err = r.getGoalRevision(ctx, rayServiceInstance); err != nil {
+			return err
+		}
+	}
+
+	if err = r.pruneRevisions(ctx, rayServiceInstance); err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(*originalStatus, rayServiceInstance.Status) {
+		if err = r.Status().Update(ctx, rayServiceInstance); err != nil {
+			return err
+		}
+	}
+
+	goal.applyTo(&rayServiceInstance.Spec)
+	return nil
+}
+
+// handleRollbackRequest removes the rollback annotation from the RayService and rolls it back to the revision named by
+// the annotation.
+func (r *RayServiceReconciler) handleRollbackRequest(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) error {
+	revisionName, exist := rayServiceInstance.Annotations[common.RayServiceRollbackAnnotationKey]
+	if !exist {
+		return nil
+	}
+
+	patch := client.MergeFrom(rayServiceInstance.DeepCopy())
+	delete(rayServiceInstance.Annotations, common.RayServiceRollbackAnnotationKey)
+	if err := r.Patch(ctx, rayServiceInstance, patch); err != nil {
+		return err
+	}
+
+	revision, err := r.getRevision(ctx, rayServiceInstance, revisionName)
+	if errors.IsNotFound(err) {
+		r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "RollbackFailed", "Revision %s to roll back to was not found", revisionName)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	r.rollback(rayServiceInstance, revision.Name)
+	r.Recorder.Eventf(rayServiceInstance, "Normal", "RollingBack", "Rolling back to revision %s", revision.Name)
+	return nil
+}
+
+// rollback deploys revisionName instead of the spec until the spec changes. The pending RayCluster of the update is
+// cleaned up like any other dangling RayCluster.
+func (r *RayServiceReconciler) rollback(rayServiceInstance *rayv1alpha1.RayService, revisionName string) {
+	rayServiceInstance.Status.RollbackRevision = revisionName
+	rayServiceInstance.Status.RollbackGeneration = rayServiceInstance.Generation
+	rayServiceInstance.Status.PendingServiceStatus = rayv1alpha1.RayServiceStatus{}
+}
+
+// getGoalRevision returns the config the RayService is reconciled to, which is the revision it is rolled back to, if
+// any, or its spec, and records it as the update revision.
+func (r *RayServiceReconciler) getGoalRevision(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) (rayServiceRevision, error) {
+	if rayServiceInstance.Status.RollbackRevision != "" {
+		revision, err := r.getRevision(ctx, rayServiceInstance, rayServiceInstance.Status.RollbackRevision)
+		if err == nil {
+			goal := rayServiceRevision{}
+			if err = json.Unmarshal(revision.Data.Raw, &goal); err != nil {
+				return goal, err
+			}
+			r.setUpdateRevision(rayServiceInstance, revision.Name)
+			return goal, nil
+		} else if !errors.IsNotFound(err) {
+			return rayServiceRevision{}, err
+		}
+		r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "RollbackFailed", "Revision %s to roll back to was not found", rayServiceInstance.Status.RollbackRevision)
+		rayServiceInstance.Status.RollbackRevision = ""
+		rayServiceInstance.Status.RollbackGeneration = 0
+	}
+
+	goal := newRayServiceRevision(&rayServiceInstance.Spec)
+	revision, err := r.getOrCreateRevision(ctx, rayServiceInstance, goal)
+	if err != nil {
+		return goal, err
+	}
+	r.setUpdateRevision(rayServiceInstance, revision.Name)
+	return goal, nil
+}
+
+func (r *RayServiceReconciler) setUpdateRevision(rayServiceInstance *rayv1alpha1.RayService, revisionName string) {
+	if rayServiceInstance.Status.UpdateRevision != revisionName {
+		now := metav1.Now()
+		rayServiceInstance.Status.UpdateRevision = revisionName
+		rayServiceInstance.Status.UpdateRevisionTime = &now
+	}
+}
+
+// getOrCreateRevision returns the ControllerRevision of the config of the RayService. A config that recurs becomes the
+// latest revision again.
+func (r *RayServiceReconciler) getOrCreateRevision(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, goal rayServiceRevision) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(goal)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := utils.GenerateJsonHash(goal)
+	if err != nil {
+		return nil, err
+	}
+	revisionName := fmt.Sprintf("%s-%s", rayServiceInstance.Name, strings.ToLower(hash[:10]))
+
+	revisions, err := r.listRevisions(ctx, rayServiceInstance)
+	if err != nil {
+		return nil, err
+	}
+	var latestRevision int64
+	var revision *appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Revision > latestRevision {
+			latestRevision = revisions[i].Revision
+		}
+		if revisions[i].Name == revisionName {
+			revision = &revisions[i]
+		}
+	}
+
+	if revision != nil {
+		if revisionName == rayServiceInstance.Status.UpdateRevision || revision.Revision == latestRevision {
+			return revision, nil
+		}
+		revision.Revision = latestRevision + 1
+		if revision.Annotations[common.RayServiceRevisionOutcomeAnnotationKey] == string(rayv1alpha1.RevisionFailed) {
+			revision.Annotations[common.RayServiceRevisionOutcomeAnnotationKey] = string(rayv1alpha1.RevisionPending)
+		}
+		if err = r.Update(ctx, revision); err != nil {
+			return nil, err
 		}
+		return revision, nil
+	}
+
+	revision = &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      revisionName,
+			Namespace: rayServiceInstance.Namespace,
+			Labels: map[string]string{
+				common.RayServiceLabelKey:          rayServiceInstance.Name,
+				common.KubernetesCreatedByLabelKey: common.RayServiceCreatorLabelValue,
+			},
+			Annotations: map[string]string{
+				common.RayServiceRevisionOutcomeAnnotationKey: string(rayv1alpha1.RevisionPending),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: latestRevision + 1,
 	}
+	if err = ctrl.SetControllerReference(rayServiceInstance, revision, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err = r.Create(ctx, revision); err != nil {
+		return nil, err
+	}
+	r.Recorder.Eventf(rayServiceInstance, "Normal", "CreatedRevision", "Created revision %s", revision.Name)
+	return revision, nil
+}
 
+// getRevision returns the ControllerRevision revisionName of the RayService.
+func (r *RayServiceReconciler) getRevision(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, revisionName string) (*appsv1.ControllerRevision, error) {
+	revision := &appsv1.ControllerRevision{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: rayServiceInstance.Namespace, Name: revisionName}, revision); err != nil {
+		return nil, err
+	}
+	if revision.Labels[common.RayServiceLabelKey] != rayServiceInstance.Name {
+		return nil, errors.NewNotFound(appsv1.Resource("controllerrevisions"), revisionName)
+	}
+	return revision, nil
+}
+
+func (r *RayServiceReconciler) listRevisions(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) ([]appsv1.ControllerRevision, error) {
+	revisionList := appsv1.ControllerRevisionList{}
+	filterLabels := client.MatchingLabels{common.RayServiceLabelKey: rayServiceInstance.Name}
+	if err := r.List(ctx, &revisionList, client.InNamespace(rayServiceInstance.Namespace), filterLabels); err != nil {
+		return nil, err
+	}
+	return revisionList.Items, nil
+}
+
+// pruneRevisions deletes the oldest revisions of the RayService beyond its revision history limit. The current, update
+// and rollback revisions are kept.
+func (r *RayServiceReconciler) pruneRevisions(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) error {
+	limit := common.DefaultRevisionHistoryLimit
+	if rayServiceInstance.Spec.RevisionHistoryLimit != nil {
+		limit = int(*rayServiceInstance.Spec.RevisionHistoryLimit)
+	}
+
+	revisions, err := r.listRevisions(ctx, rayServiceInstance)
+	if err != nil {
+		return err
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	excess := len(revisions) - limit
+	for i := 0; i < len(revisions) && excess > 0; i++ {
+		switch revisions[i].Name {
+		case rayServiceInstance.Status.CurrentRevision, rayServiceInstance.Status.UpdateRevision, rayServiceInstance.Status.RollbackRevision:
+			continue
+		}
+		r.Log.V(1).Info("pruneRevisions", "delete revision", revisions[i].Name)
+		if err := r.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		excess--
+	}
 	return nil
 }
 
+// checkAutoRollback marks the update revision of the RayService as failed when it doesn't become healthy before the
+// auto rollback deadline, and rolls the RayService back to its last healthy revision. It returns whether the RayService
+// was rolled back.
+func (r *RayServiceReconciler) checkAutoRollback(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService) (bool, error) {
+	autoRollback := rayServiceInstance.Spec.AutoRollback
+	status := &rayServiceInstance.Status
+	if autoRollback == nil || status.UpdateRevision == status.CurrentRevision || status.UpdateRevisionTime == nil {
+		return false, nil
+	}
+	if time.Since(status.UpdateRevisionTime.Time) < time.Duration(autoRollback.DeadlineSeconds)*time.Second {
+		return false, nil
+	}
+
+	revision, err := r.getRevision(ctx, rayServiceInstance, status.UpdateRevision)
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if revision.Annotations[common.RayServiceRevisionOutcomeAnnotationKey] == string(rayv1alpha1.RevisionFailed) {
+		return false, nil
+	}
+	if err = r.setRevisionOutcome(ctx, revision, rayv1alpha1.RevisionFailed); err != nil {
+		return false, err
+	}
+	r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "RevisionFailed",
+		"Revision %s didn't become healthy in %d seconds", revision.Name, autoRollback.DeadlineSeconds)
+
+	if status.CurrentRevision == "" {
+		return false, nil
+	}
+	r.rollback(rayServiceInstance, status.CurrentRevision)
+	r.Recorder.Eventf(rayServiceInstance, corev1.EventTypeWarning, "RolledBack", "Rolled back to revision %s", status.CurrentRevision)
+	return true, nil
+}
+
+// markRevisionHealthy makes the update revision of the RayService its current revision once it is healthy. The healthy
+// rayClusterInstance must run the update revision, and not e.g. the previous revision that the active RayCluster keeps
+// serving after a failed upgrade.
+func (r *RayServiceReconciler) markRevisionHealthy(ctx context.Context, rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) {
+	status := &rayServiceInstance.Status
+	if status.UpdateRevision == "" || status.UpdateRevision == status.CurrentRevision {
+		return
+	}
+	if !isRayClusterRunningGoal(rayServiceInstance, rayClusterInstance) {
+		return
+	}
+
+	revision, err := r.getRevision(ctx, rayServiceInstance, status.UpdateRevision)
+	if err == nil {
+		err = r.setRevisionOutcome(ctx, revision, rayv1alpha1.RevisionHealthy)
+	}
+	if client.IgnoreNotFound(err) != nil {
+		r.Log.Error(err, "Failed to mark the revision as healthy", "revision", status.UpdateRevision)
+		return
+	}
+	status.CurrentRevision = status.UpdateRevision
+}
+
+// isRayClusterRunningGoal returns whether rayClusterInstance was created or updated with the RayClusterSpec that the
+// RayService is reconciled to, and runs its Serve config.
+func isRayClusterRunningGoal(rayServiceInstance *rayv1alpha1.RayService, rayClusterInstance *rayv1alpha1.RayCluster) bool {
+	clusterHash, err := generateRayClusterJsonHash(rayServiceInstance.Spec.RayClusterSpec)
+	if err != nil || clusterHash != rayClusterInstance.Annotations[common.RayServiceClusterHashKey] {
+		return false
+	}
+	serveConfigHash, err := utils.GenerateJsonHash(getServeConfig(rayServiceInstance))
+	return err == nil && serveConfigHash == rayClusterInstance.Annotations[common.RayServiceServeConfigHashKey]
+}
+
+func (r *RayServiceReconciler) setRevisionOutcome(ctx context.Context, revision *appsv1.ControllerRevision, outcome rayv1alpha1.RevisionOutcome) error {
+	if revision.Annotations[common.RayServiceRevisionOutcomeAnnotationKey] == string(outcome) {
+		return nil
+	}
+	if revision.Annotations == nil {
+		revision.Annotations = map[string]string{}
+	}
+	revision.Annotations[common.RayServiceRevisionOutcomeAnnotationKey] = string(outcome)
+	return r.Update(ctx, revision)
+}
+
 func generateRayClusterJsonHash(rayClusterSpec rayv1alpha1.RayClusterSpec) (string, error) {
 	// Mute all fields that will not trigger new RayCluster preparation. For example,
 	// Autoscaler will update `Replicas` and `WorkersToDelete` when scaling up/down.
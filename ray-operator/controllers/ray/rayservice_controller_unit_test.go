@@ -1,18 +1,32 @@
 package ray
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/utils"
 	"github.com/ray-project/kuberay/ray-operator/pkg/client/clientset/versioned/scheme"
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -68,6 +82,131 @@ func TestCompareRayClusterJsonHash(t *testing.T) {
 	assert.True(t, equal)
 }
 
+func TestIsRayClusterSpecUpdatableInPlace(t *testing.T) {
+	spec := v1alpha1.RayClusterSpec{
+		RayVersion: "2.4.0",
+		WorkerGroupSpecs: []v1alpha1.WorkerGroupSpec{
+			{
+				GroupName:   "small-group",
+				Replicas:    pointer.Int32(1),
+				MinReplicas: pointer.Int32(1),
+				MaxReplicas: pointer.Int32(4),
+			},
+		},
+	}
+	hash, err := generateRayClusterJsonHash(spec)
+	assert.Nil(t, err)
+	cluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{common.RayServiceClusterHashKey: hash}},
+		Spec:       *spec.DeepCopy(),
+	}
+
+	// Test 1: Scaling and adding worker groups are applied in place.
+	goalSpec := spec.DeepCopy()
+	goalSpec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+	goalSpec.WorkerGroupSpecs[0].MaxReplicas = pointer.Int32(10)
+	goalSpec.WorkerGroupSpecs = append(goalSpec.WorkerGroupSpecs, v1alpha1.WorkerGroupSpec{GroupName: "large-group", Replicas: pointer.Int32(1)})
+	inPlace, err := isRayClusterSpecUpdatableInPlace(cluster, *goalSpec)
+	assert.Nil(t, err)
+	assert.True(t, inPlace)
+
+	// Test 2: Other changes of the worker groups need a new RayCluster.
+	changedSpec := goalSpec.DeepCopy()
+	changedSpec.WorkerGroupSpecs[0].RayStartParams = map[string]string{"num-cpus": "2"}
+	inPlace, err = isRayClusterSpecUpdatableInPlace(cluster, *changedSpec)
+	assert.Nil(t, err)
+	assert.False(t, inPlace)
+
+	// Test 3: Removing a worker group needs a new RayCluster.
+	changedSpec = spec.DeepCopy()
+	changedSpec.WorkerGroupSpecs = nil
+	inPlace, err = isRayClusterSpecUpdatableInPlace(cluster, *changedSpec)
+	assert.Nil(t, err)
+	assert.False(t, inPlace)
+
+	// Test 4: Changes of the head group need a new RayCluster.
+	changedSpec = goalSpec.DeepCopy()
+	changedSpec.RayVersion = "2.5.0"
+	inPlace, err = isRayClusterSpecUpdatableInPlace(cluster, *changedSpec)
+	assert.Nil(t, err)
+	assert.False(t, inPlace)
+}
+
+func TestUpdateRayClusterInPlace(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+
+	spec := v1alpha1.RayClusterSpec{
+		WorkerGroupSpecs: []v1alpha1.WorkerGroupSpec{
+			{
+				GroupName:   "small-group",
+				Replicas:    pointer.Int32(1),
+				MinReplicas: pointer.Int32(1),
+				MaxReplicas: pointer.Int32(4),
+			},
+		},
+	}
+	hash, err := generateRayClusterJsonHash(spec)
+	assert.Nil(t, err)
+	cluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rayservice-sample-raycluster-abcde",
+			Namespace:   "default",
+			Annotations: map[string]string{common.RayServiceClusterHashKey: hash},
+		},
+		Spec: *spec.DeepCopy(),
+	}
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec:       v1alpha1.RayServiceSpec{RayClusterSpec: *spec.DeepCopy()},
+	}
+	rayService.Spec.RayClusterSpec.WorkerGroupSpecs[0].Replicas = pointer.Int32(3)
+	rayService.Spec.RayClusterSpec.WorkerGroupSpecs = append(rayService.Spec.RayClusterSpec.WorkerGroupSpecs,
+		v1alpha1.WorkerGroupSpec{GroupName: "large-group", Replicas: pointer.Int32(2)})
+
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(cluster).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	assert.False(t, r.shouldPrepareNewRayCluster(rayService, cluster))
+
+	// Test 1: The worker groups are scaled and added in place, and the hash is updated.
+	inPlace, err := r.updateRayClusterInPlace(ctx, rayService, cluster)
+	assert.Nil(t, err)
+	assert.True(t, inPlace)
+	updatedCluster := &v1alpha1.RayCluster{}
+	assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: "default"}, updatedCluster))
+	assert.Len(t, updatedCluster.Spec.WorkerGroupSpecs, 2)
+	assert.Equal(t, int32(3), *updatedCluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, "large-group", updatedCluster.Spec.WorkerGroupSpecs[1].GroupName)
+	goalHash, _ := generateRayClusterJsonHash(rayService.Spec.RayClusterSpec)
+	assert.Equal(t, goalHash, updatedCluster.Annotations[common.RayServiceClusterHashKey])
+
+	// Test 2: The autoscaler keeps managing the replicas of an autoscaling RayCluster.
+	updatedCluster.Spec.EnableInTreeAutoscaling = pointer.Bool(true)
+	rayService.Spec.RayClusterSpec.EnableInTreeAutoscaling = pointer.Bool(true)
+	updatedCluster.Annotations[common.RayServiceClusterHashKey], _ = generateRayClusterJsonHash(rayService.Spec.RayClusterSpec)
+	rayService.Spec.RayClusterSpec.WorkerGroupSpecs[0].Replicas = pointer.Int32(5)
+	rayService.Spec.RayClusterSpec.WorkerGroupSpecs[0].MaxReplicas = pointer.Int32(8)
+	assert.Nil(t, fakeClient.Update(ctx, updatedCluster))
+	inPlace, err = r.updateRayClusterInPlace(ctx, rayService, updatedCluster)
+	assert.Nil(t, err)
+	assert.True(t, inPlace)
+	assert.Equal(t, int32(3), *updatedCluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, int32(8), *updatedCluster.Spec.WorkerGroupSpecs[0].MaxReplicas)
+
+	// Test 3: Other changes need a new RayCluster.
+	rayService.Spec.RayClusterSpec.RayVersion = "2.5.0"
+	inPlace, err = r.updateRayClusterInPlace(ctx, rayService, updatedCluster)
+	assert.Nil(t, err)
+	assert.False(t, inPlace)
+	assert.True(t, r.shouldPrepareNewRayCluster(rayService, updatedCluster))
+}
+
 func TestInconsistentRayServiceStatuses(t *testing.T) {
 	r := &RayServiceReconciler{
 		Log: ctrl.Log.WithName("controllers").WithName("RayService"),
@@ -180,6 +319,105 @@ func TestInconsistentRayServiceStatus(t *testing.T) {
 	assert.True(t, r.inconsistentRayServiceStatus(oldStatus, *newStatus))
 }
 
+func TestInconsistentRayServiceStatusApplications(t *testing.T) {
+	timeNow := metav1.Now()
+	oldStatus := v1alpha1.RayServiceStatus{
+		RayClusterName: "cluster-1",
+		Applications: map[string]v1alpha1.AppStatus{
+			"fruit": {
+				Status:         v1alpha1.ApplicationStatusEnum.RUNNING,
+				LastUpdateTime: &timeNow,
+				Deployments: map[string]v1alpha1.ServeDeploymentStatus{
+					"MangoStand": {Name: "MangoStand", Status: v1alpha1.DeploymentStatusEnum.HEALTHY, LastUpdateTime: &timeNow},
+				},
+			},
+		},
+	}
+
+	r := &RayServiceReconciler{
+		Log: ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+
+	// Test 1: Only LastUpdateTime is updated.
+	newStatus := oldStatus.DeepCopy()
+	newStatus.Applications["fruit"].Deployments["MangoStand"] = v1alpha1.ServeDeploymentStatus{
+		Name: "MangoStand", Status: v1alpha1.DeploymentStatusEnum.HEALTHY, LastUpdateTime: &metav1.Time{Time: timeNow.Add(1)},
+	}
+	assert.False(t, r.inconsistentRayServiceStatus(oldStatus, *newStatus))
+
+	// Test 2: The status of a deployment changed.
+	newStatus.Applications["fruit"].Deployments["MangoStand"] = v1alpha1.ServeDeploymentStatus{
+		Name: "MangoStand", Status: v1alpha1.DeploymentStatusEnum.UNHEALTHY,
+	}
+	assert.True(t, r.inconsistentRayServiceStatus(oldStatus, *newStatus))
+
+	// Test 3: An application was added.
+	newStatus = oldStatus.DeepCopy()
+	newStatus.Applications["math"] = v1alpha1.AppStatus{Status: v1alpha1.ApplicationStatusEnum.DEPLOYING}
+	assert.True(t, r.inconsistentRayServiceStatus(oldStatus, *newStatus))
+}
+
+func TestGetAndCheckServeStatusApplications(t *testing.T) {
+	r := &RayServiceReconciler{
+		Log: ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	dashboardClient := &utils.FakeRayDashboardClient{}
+	status := &v1alpha1.RayServiceStatus{}
+	ctx := context.Background()
+
+	// Test 1: No application has been deployed yet.
+	isHealthy, isReady, err := r.getAndCheckServeStatus(ctx, dashboardClient, status, nil, utils.ServeApplicationsAPI)
+	assert.Nil(t, err)
+	assert.True(t, isHealthy)
+	assert.False(t, isReady)
+
+	// Test 2: One of the applications is still deploying.
+	dashboardClient.SetApplicationsStatus(utils.ServeApplicationStatuses{
+		Applications: map[string]utils.ServeApplicationStatus{
+			"fruit": {
+				Status: v1alpha1.ApplicationStatusEnum.RUNNING,
+				Deployments: map[string]utils.ServeApplicationDeploymentStatus{
+					"MangoStand": {Name: "MangoStand", Status: v1alpha1.DeploymentStatusEnum.HEALTHY},
+				},
+			},
+			"math": {Status: v1alpha1.ApplicationStatusEnum.DEPLOYING},
+		},
+	})
+	isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, dashboardClient, status, nil, utils.ServeApplicationsAPI)
+	assert.Nil(t, err)
+	assert.True(t, isHealthy)
+	assert.False(t, isReady)
+	assert.Len(t, status.Applications, 2)
+	assert.Equal(t, v1alpha1.DeploymentStatusEnum.HEALTHY, status.Applications["fruit"].Deployments["MangoStand"].Status)
+
+	// Test 3: An application has been unhealthy for longer than the threshold.
+	dashboardClient.SetApplicationsStatus(utils.ServeApplicationStatuses{
+		Applications: map[string]utils.ServeApplicationStatus{
+			"fruit": {Status: v1alpha1.ApplicationStatusEnum.RUNNING},
+			"math":  {Status: v1alpha1.ApplicationStatusEnum.DEPLOY_FAILED},
+		},
+	})
+	math := status.Applications["math"]
+	math.HealthLastUpdateTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	status.Applications["math"] = math
+	isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, dashboardClient, status, pointer.Int32(60), utils.ServeApplicationsAPI)
+	assert.Nil(t, err)
+	assert.False(t, isHealthy)
+	assert.False(t, isReady)
+
+	// Test 4: All the applications are running.
+	dashboardClient.SetApplicationsStatus(utils.ServeApplicationStatuses{
+		Applications: map[string]utils.ServeApplicationStatus{
+			"fruit": {Status: v1alpha1.ApplicationStatusEnum.RUNNING},
+			"math":  {Status: v1alpha1.ApplicationStatusEnum.RUNNING},
+		},
+	})
+	isHealthy, isReady, err = r.getAndCheckServeStatus(ctx, dashboardClient, status, nil, utils.ServeApplicationsAPI)
+	assert.Nil(t, err)
+	assert.True(t, isHealthy)
+	assert.True(t, isReady)
+}
+
 func TestIsHeadPodRunningAndReady(t *testing.T) {
 	// Create a new scheme with CRDs, Pod, Service schemes.
 	newScheme := runtime.NewScheme()
@@ -260,3 +498,864 @@ func TestIsHeadPodRunningAndReady(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, isReady)
 }
+
+func TestShiftTraffic(t *testing.T) {
+	rayService := &v1alpha1.RayService{
+		Spec: v1alpha1.RayServiceSpec{
+			UpgradeStrategy: &v1alpha1.RayServiceUpgradeStrategy{
+				Type: v1alpha1.TrafficShiftingUpgrade,
+				TrafficShifting: &v1alpha1.TrafficShiftingSpec{
+					Steps:               []int32{10, 50},
+					StepIntervalSeconds: pointer.Int32(60),
+				},
+			},
+		},
+		Status: v1alpha1.RayServiceStatuses{
+			ActiveServiceStatus:  v1alpha1.RayServiceStatus{RayClusterName: "active"},
+			PendingServiceStatus: v1alpha1.RayServiceStatus{RayClusterName: "pending"},
+		},
+	}
+	r := &RayServiceReconciler{
+		Recorder: record.NewFakeRecorder(10),
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	assert.True(t, r.isShiftingTraffic(rayService))
+
+	// Test 1: The pending cluster just became ready and receives the first step.
+	assert.False(t, r.shiftTraffic(rayService))
+	assert.Equal(t, int32(10), *rayService.Status.PendingServiceStatus.TrafficWeight)
+
+	// Test 2: The step interval hasn't elapsed.
+	assert.False(t, r.shiftTraffic(rayService))
+	assert.Equal(t, int32(10), *rayService.Status.PendingServiceStatus.TrafficWeight)
+
+	// Test 3: The step interval has elapsed.
+	rayService.Status.LastTrafficShiftTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	assert.False(t, r.shiftTraffic(rayService))
+	assert.Equal(t, int32(50), *rayService.Status.PendingServiceStatus.TrafficWeight)
+
+	// Test 4: The last step is over, the pending cluster gets all the traffic.
+	rayService.Status.LastTrafficShiftTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	assert.True(t, r.shiftTraffic(rayService))
+
+	// Test 5: Without the TrafficShifting strategy, the traffic switches at once.
+	rayService.Spec.UpgradeStrategy.Type = v1alpha1.SwitchUpgrade
+	assert.False(t, r.isShiftingTraffic(rayService))
+}
+
+func TestReconcileTrafficRouting(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	_ = networkingv1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			UpgradeStrategy: &v1alpha1.RayServiceUpgradeStrategy{Type: v1alpha1.TrafficShiftingUpgrade},
+		},
+		Status: v1alpha1.RayServiceStatuses{
+			ActiveServiceStatus:  v1alpha1.RayServiceStatus{RayClusterName: "active"},
+			PendingServiceStatus: v1alpha1.RayServiceStatus{RayClusterName: "pending", TrafficWeight: pointer.Int32(10)},
+		},
+	}
+	activeCluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "active", Namespace: "default"},
+		Spec: v1alpha1.RayClusterSpec{
+			HeadGroupSpec: v1alpha1.HeadGroupSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.4.0"}},
+					},
+				},
+			},
+		},
+	}
+	pendingCluster := activeCluster.DeepCopy()
+	pendingCluster.Name = "pending"
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	canaryKey := client.ObjectKey{Name: utils.GenerateServeIngressName(rayService.Name, true), Namespace: "default"}
+
+	// Test 1: 10% of the traffic goes to the pending cluster.
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Equal(t, int32(90), *rayService.Status.ActiveServiceStatus.TrafficWeight)
+	for _, cluster := range []string{"active", "pending"} {
+		svc := &corev1.Service{}
+		assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: utils.GenerateServeServiceName(cluster), Namespace: "default"}, svc))
+		assert.Equal(t, cluster, svc.Spec.Selector[common.RayClusterLabelKey])
+	}
+	ingress := &networkingv1.Ingress{}
+	assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: utils.GenerateServeIngressName(rayService.Name, false), Namespace: "default"}, ingress))
+	assert.Equal(t, utils.GenerateServeServiceName("active"), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+	canaryIngress := &networkingv1.Ingress{}
+	assert.Nil(t, fakeClient.Get(ctx, canaryKey, canaryIngress))
+	assert.Equal(t, "10", canaryIngress.Annotations[common.NginxCanaryWeightAnnotationKey])
+
+	// Test 2: The pending cluster restarts, so the traffic moves back to the active cluster.
+	rayService.Status.PendingServiceStatus = v1alpha1.RayServiceStatus{RayClusterName: "pending-2"}
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, nil))
+	assert.Equal(t, int32(100), *rayService.Status.ActiveServiceStatus.TrafficWeight)
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, canaryKey, canaryIngress)))
+
+	// Test 3: The pending cluster was promoted.
+	rayService.Status.ActiveServiceStatus = v1alpha1.RayServiceStatus{RayClusterName: "pending"}
+	rayService.Status.PendingServiceStatus = v1alpha1.RayServiceStatus{}
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: utils.GenerateServeIngressName(rayService.Name, false), Namespace: "default"}, ingress))
+	assert.Equal(t, utils.GenerateServeServiceName("pending"), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+
+	// Test 4: The Gateway router replaces the Ingresses with an HTTPRoute.
+	ingressKey := client.ObjectKey{Name: utils.GenerateServeIngressName(rayService.Name, false), Namespace: "default"}
+	routeKey := client.ObjectKey{Name: utils.GenerateServeRouteName(rayService.Name), Namespace: "default"}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(common.HTTPRouteGroupVersionKind)
+	rayService.Spec.UpgradeStrategy.TrafficShifting = &v1alpha1.TrafficShiftingSpec{
+		Router:  v1alpha1.GatewayRouter,
+		Gateway: &v1alpha1.GatewayReference{Name: "gateway"},
+	}
+	rayService.Status.ActiveServiceStatus = v1alpha1.RayServiceStatus{RayClusterName: "active"}
+	rayService.Status.PendingServiceStatus = v1alpha1.RayServiceStatus{RayClusterName: "pending", TrafficWeight: pointer.Int32(10)}
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Nil(t, fakeClient.Get(ctx, routeKey, route))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, ingressKey, ingress)))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, canaryKey, canaryIngress)))
+
+	// Test 5: Once TrafficShifting is disabled, its routing objects are deleted.
+	rayService.Spec.UpgradeStrategy = nil
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Nil(t, rayService.Status.ActiveServiceStatus.TrafficWeight)
+	assert.Nil(t, rayService.Status.PendingServiceStatus.TrafficWeight)
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, routeKey, route)))
+
+	// Test 6: The Ingress is kept while serveIngress owns it.
+	rayService.Spec.ServeIngress = &v1alpha1.ServeIngressSpec{Host: "fruit.example.com"}
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, activeCluster))
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+
+	// Test 7: The objects with the same names that the RayService doesn't control are kept.
+	userIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: canaryKey.Name, Namespace: "default"}}
+	assert.Nil(t, fakeClient.Create(ctx, userIngress))
+	userRoute := &unstructured.Unstructured{}
+	userRoute.SetGroupVersionKind(common.HTTPRouteGroupVersionKind)
+	userRoute.SetName(routeKey.Name)
+	userRoute.SetNamespace("default")
+	assert.Nil(t, fakeClient.Create(ctx, userRoute))
+	assert.Nil(t, r.reconcileTrafficRouting(ctx, rayService, activeCluster, pendingCluster))
+	assert.Nil(t, fakeClient.Get(ctx, canaryKey, canaryIngress))
+	assert.Nil(t, fakeClient.Get(ctx, routeKey, route))
+}
+
+func TestReconcileServeIngress(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+	_ = networkingv1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			ServeIngress: &v1alpha1.ServeIngressSpec{Host: "fruit.example.com"},
+		},
+	}
+	cluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "active", Namespace: "default"},
+		Spec: v1alpha1.RayClusterSpec{
+			HeadGroupSpec: v1alpha1.HeadGroupSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.4.0"}},
+					},
+				},
+			},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	ingressKey := client.ObjectKey{Name: utils.GenerateServeIngressName(rayService.Name, false), Namespace: "default"}
+
+	// Test 1: The Ingress routes the traffic to the serve service of the RayService.
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	ingress := &networkingv1.Ingress{}
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	assert.Equal(t, "fruit.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, utils.GenerateServeServiceName(rayService.Name), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+
+	// Test 2: The Ingress is updated with the spec.
+	rayService.Spec.ServeIngress.Host = "mango.example.com"
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	assert.Equal(t, "mango.example.com", ingress.Spec.Rules[0].Host)
+
+	// Test 3: The Ingress router of a TrafficShifting upgrade owns the Ingress.
+	rayService.Spec.UpgradeStrategy = &v1alpha1.RayServiceUpgradeStrategy{Type: v1alpha1.TrafficShiftingUpgrade}
+	rayService.Spec.ServeIngress.Host = "fruit.example.com"
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	assert.Equal(t, "mango.example.com", ingress.Spec.Rules[0].Host)
+	rayService.Spec.ServeIngress = nil
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+
+	// Test 4: The Ingress is deleted once serveIngress is unset.
+	rayService.Spec.UpgradeStrategy = nil
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, ingressKey, ingress)))
+
+	// Test 5: An Ingress with the same name that the RayService doesn't control is kept.
+	assert.Nil(t, fakeClient.Create(ctx, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: ingressKey.Name, Namespace: "default"}}))
+	assert.Nil(t, r.reconcileServeIngress(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+}
+
+func TestReconcileServeGRPCRoute(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+	_ = networkingv1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			ServeGRPC: &v1alpha1.ServeGRPCSpec{Route: &v1alpha1.ServeGRPCRouteSpec{Host: "grpc.example.com"}},
+		},
+	}
+	cluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "active", Namespace: "default"},
+		Spec: v1alpha1.RayClusterSpec{
+			HeadGroupSpec: v1alpha1.HeadGroupSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "ray-head", Image: "rayproject/ray:2.4.0"}},
+					},
+				},
+			},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	ingressKey := client.ObjectKey{Name: utils.GenerateServeGRPCIngressName(rayService.Name), Namespace: "default"}
+	routeKey := client.ObjectKey{Name: utils.GenerateServeGRPCRouteName(rayService.Name), Namespace: "default"}
+	ingress := &networkingv1.Ingress{}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(common.GRPCRouteGroupVersionKind)
+
+	// Test 1: The Ingress router creates an Ingress.
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	assert.Equal(t, "grpc.example.com", ingress.Spec.Rules[0].Host)
+
+	// Test 2: The Gateway router replaces the Ingress with a GRPCRoute.
+	rayService.Spec.ServeGRPC.Route.Router = v1alpha1.GatewayRouter
+	rayService.Spec.ServeGRPC.Route.Gateway = &v1alpha1.GatewayReference{Name: "gateway"}
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, routeKey, route))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, ingressKey, ingress)))
+
+	// Test 3: The GRPCRoute is deleted once the route is unset.
+	rayService.Spec.ServeGRPC.Route = nil
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, routeKey, route)))
+
+	// Test 4: The Ingress is deleted once serveGRPC is unset.
+	rayService.Spec.ServeGRPC = &v1alpha1.ServeGRPCSpec{Route: &v1alpha1.ServeGRPCRouteSpec{}}
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	rayService.Spec.ServeGRPC = nil
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, ingressKey, ingress)))
+
+	// Test 5: The Ingress and the GRPCRoute with the same names that the RayService doesn't control are kept.
+	assert.Nil(t, fakeClient.Create(ctx, &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: ingressKey.Name, Namespace: "default"}}))
+	userRoute := &unstructured.Unstructured{}
+	userRoute.SetGroupVersionKind(common.GRPCRouteGroupVersionKind)
+	userRoute.SetName(routeKey.Name)
+	userRoute.SetNamespace("default")
+	assert.Nil(t, fakeClient.Create(ctx, userRoute))
+	assert.Nil(t, r.reconcileServeGRPCRoute(ctx, rayService, cluster))
+	assert.Nil(t, fakeClient.Get(ctx, ingressKey, ingress))
+	assert.Nil(t, fakeClient.Get(ctx, routeKey, route))
+}
+
+func TestReconcileRevisions(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+	_ = appsv1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default", Generation: 1},
+		Spec: v1alpha1.RayServiceSpec{
+			RayClusterSpec:       v1alpha1.RayClusterSpec{RayVersion: "2.4.0"},
+			ServeConfigV2:        "applications: []",
+			RevisionHistoryLimit: pointer.Int32(2),
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayService).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	reconcile := func(version string) *v1alpha1.RayService {
+		instance := &v1alpha1.RayService{}
+		assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: rayService.Name, Namespace: "default"}, instance))
+		instance.Spec.RayClusterSpec.RayVersion = version
+		assert.Nil(t, r.reconcileRevisions(ctx, instance))
+		return instance
+	}
+	revisionList := func() map[string]appsv1.ControllerRevision {
+		revisions, err := r.listRevisions(ctx, rayService)
+		assert.Nil(t, err)
+		revisionMap := map[string]appsv1.ControllerRevision{}
+		for _, revision := range revisions {
+			revisionMap[revision.Name] = revision
+		}
+		return revisionMap
+	}
+
+	// Test 1: The config is recorded in a pending revision, which becomes the update revision.
+	instance := reconcile("2.4.0")
+	firstRevision := instance.Status.UpdateRevision
+	assert.NotEmpty(t, firstRevision)
+	assert.NotNil(t, instance.Status.UpdateRevisionTime)
+	revisions := revisionList()
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, int64(1), revisions[firstRevision].Revision)
+	assert.Equal(t, string(v1alpha1.RevisionPending), revisions[firstRevision].Annotations[common.RayServiceRevisionOutcomeAnnotationKey])
+	assert.Equal(t, instance.Status.UpdateRevision, reconcile("2.4.0").Status.UpdateRevision)
+	assert.Len(t, revisionList(), 1)
+
+	// Test 2: A healthy revision becomes the current revision, once a RayCluster runs it. The healthy active RayCluster
+	// that keeps serving an older config after a failed upgrade doesn't make the update revision healthy.
+	staleCluster := newRayClusterForGoal(t, instance)
+	staleCluster.Annotations[common.RayServiceClusterHashKey] = "stale"
+	r.markRevisionHealthy(ctx, instance, staleCluster)
+	assert.Empty(t, instance.Status.CurrentRevision)
+	staleCluster = newRayClusterForGoal(t, instance)
+	staleCluster.Annotations[common.RayServiceServeConfigHashKey] = "stale"
+	r.markRevisionHealthy(ctx, instance, staleCluster)
+	assert.Empty(t, instance.Status.CurrentRevision)
+	r.markRevisionHealthy(ctx, instance, newRayClusterForGoal(t, instance))
+	assert.Equal(t, firstRevision, instance.Status.CurrentRevision)
+	assert.Nil(t, fakeClient.Status().Update(ctx, instance))
+	assert.Equal(t, string(v1alpha1.RevisionHealthy), revisionList()[firstRevision].Annotations[common.RayServiceRevisionOutcomeAnnotationKey])
+
+	// Test 3: A config that recurs becomes the latest revision again.
+	secondRevision := reconcile("2.5.0").Status.UpdateRevision
+	assert.NotEqual(t, firstRevision, secondRevision)
+	assert.Equal(t, firstRevision, reconcile("2.4.0").Status.UpdateRevision)
+	revisions = revisionList()
+	assert.Equal(t, int64(2), revisions[secondRevision].Revision)
+	assert.Equal(t, int64(3), revisions[firstRevision].Revision)
+
+	// Test 4: The oldest revisions beyond the limit are pruned, except the current revision.
+	reconcile("2.5.0")
+	thirdRevision := reconcile("2.6.0").Status.UpdateRevision
+	revisions = revisionList()
+	assert.Len(t, revisions, 2)
+	assert.Contains(t, revisions, firstRevision)
+	assert.Contains(t, revisions, thirdRevision)
+	assert.NotContains(t, revisions, secondRevision)
+}
+
+// newRayClusterForGoal returns a RayCluster that runs the config the RayService is reconciled to.
+func newRayClusterForGoal(t *testing.T, rayService *v1alpha1.RayService) *v1alpha1.RayCluster {
+	clusterHash, err := generateRayClusterJsonHash(rayService.Spec.RayClusterSpec)
+	assert.Nil(t, err)
+	serveConfigHash, err := utils.GenerateJsonHash(getServeConfig(rayService))
+	assert.Nil(t, err)
+	return &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rayservice-sample-raycluster-active",
+			Namespace: rayService.Namespace,
+			Annotations: map[string]string{
+				common.RayServiceClusterHashKey:     clusterHash,
+				common.RayServiceServeConfigHashKey: serveConfigHash,
+			},
+		},
+		Spec: rayService.Spec.RayClusterSpec,
+	}
+}
+
+func TestRollbackRayService(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+	_ = appsv1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default", Generation: 1},
+		Spec: v1alpha1.RayServiceSpec{
+			RayClusterSpec: v1alpha1.RayClusterSpec{RayVersion: "2.4.0"},
+			ServeConfigV2:  "applications: []",
+			AutoRollback:   &v1alpha1.AutoRollbackSpec{DeadlineSeconds: 60},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(rayService).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	getRayService := func() *v1alpha1.RayService {
+		instance := &v1alpha1.RayService{}
+		assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: rayService.Name, Namespace: "default"}, instance))
+		return instance
+	}
+
+	// The first revision is healthy, and the second one is being deployed on a pending RayCluster.
+	instance := getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	r.markRevisionHealthy(ctx, instance, newRayClusterForGoal(t, instance))
+	healthyRevision := instance.Status.CurrentRevision
+	instance.Spec.RayClusterSpec.RayVersion = "2.5.0"
+	instance.Generation = 2
+	assert.Nil(t, fakeClient.Update(ctx, instance))
+	instance = getRayService()
+	instance.Status.PendingServiceStatus.RayClusterName = "rayservice-sample-raycluster-pending"
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	failingRevision := instance.Status.UpdateRevision
+	assert.NotEqual(t, healthyRevision, failingRevision)
+
+	// Test 1: The update isn't rolled back before the deadline.
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Empty(t, instance.Status.RollbackRevision)
+	assert.Equal(t, "2.5.0", instance.Spec.RayClusterSpec.RayVersion)
+
+	// Test 2: The update fails after the deadline, and the RayService is rolled back to the healthy revision.
+	instance.Status.UpdateRevisionTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	assert.Nil(t, fakeClient.Status().Update(ctx, instance))
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Equal(t, healthyRevision, instance.Status.RollbackRevision)
+	assert.Equal(t, healthyRevision, instance.Status.UpdateRevision)
+	assert.Equal(t, int64(2), instance.Status.RollbackGeneration)
+	assert.Empty(t, instance.Status.PendingServiceStatus.RayClusterName)
+	assert.Equal(t, "2.4.0", instance.Spec.RayClusterSpec.RayVersion)
+	failed, err := r.getRevision(ctx, instance, failingRevision)
+	assert.Nil(t, err)
+	assert.Equal(t, string(v1alpha1.RevisionFailed), failed.Annotations[common.RayServiceRevisionOutcomeAnnotationKey])
+
+	// Test 3: The rollback lasts until the spec changes.
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Equal(t, "2.4.0", instance.Spec.RayClusterSpec.RayVersion)
+	instance = getRayService()
+	instance.Spec.RayClusterSpec.RayVersion = "2.6.0"
+	instance.Generation = 3
+	assert.Nil(t, fakeClient.Update(ctx, instance))
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Empty(t, instance.Status.RollbackRevision)
+	assert.Equal(t, "2.6.0", instance.Spec.RayClusterSpec.RayVersion)
+
+	// Test 4: A rollback to a named revision is requested with an annotation, which is then removed.
+	instance = getRayService()
+	instance.Annotations = map[string]string{common.RayServiceRollbackAnnotationKey: failingRevision}
+	assert.Nil(t, fakeClient.Update(ctx, instance))
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Equal(t, failingRevision, instance.Status.RollbackRevision)
+	assert.Equal(t, "2.5.0", instance.Spec.RayClusterSpec.RayVersion)
+	assert.NotContains(t, getRayService().Annotations, common.RayServiceRollbackAnnotationKey)
+
+	// Test 5: A rollback to a missing revision is ignored.
+	instance = getRayService()
+	instance.Annotations = map[string]string{common.RayServiceRollbackAnnotationKey: "missing"}
+	assert.Nil(t, fakeClient.Update(ctx, instance))
+	instance = getRayService()
+	assert.Nil(t, r.reconcileRevisions(ctx, instance))
+	assert.Equal(t, failingRevision, instance.Status.RollbackRevision)
+	assert.NotContains(t, getRayService().Annotations, common.RayServiceRollbackAnnotationKey)
+}
+
+func TestCheckPendingClusterTimeout(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+
+	spec := v1alpha1.RayClusterSpec{RayVersion: "2.4.0"}
+	hash, err := generateRayClusterJsonHash(spec)
+	assert.Nil(t, err)
+	activeCluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rayservice-sample-raycluster-active",
+			Namespace:   "default",
+			Annotations: map[string]string{common.RayServiceClusterHashKey: hash},
+		},
+		Spec: spec,
+	}
+	pendingCluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample-raycluster-pending", Namespace: "default"},
+	}
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.RayServiceSpec{
+			RayClusterSpec:               v1alpha1.RayClusterSpec{RayVersion: "2.5.0"},
+			PendingClusterTimeoutSeconds: pointer.Int32(600),
+		},
+		Status: v1alpha1.RayServiceStatuses{
+			ActiveServiceStatus:  v1alpha1.RayServiceStatus{RayClusterName: activeCluster.Name},
+			PendingServiceStatus: v1alpha1.RayServiceStatus{RayClusterName: pendingCluster.Name},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(activeCluster, pendingCluster).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+
+	// Test 1: The timeout starts with the pending RayCluster.
+	abandoned, err := r.checkPendingClusterTimeout(ctx, rayService, activeCluster, pendingCluster)
+	assert.Nil(t, err)
+	assert.False(t, abandoned)
+	assert.NotNil(t, rayService.Status.PendingClusterStartTime)
+
+	// Test 2: After the timeout, the pending RayCluster is deleted and the upgrade fails.
+	rayService.Status.PendingClusterStartTime = &metav1.Time{Time: time.Now().Add(-11 * time.Minute)}
+	abandoned, err = r.checkPendingClusterTimeout(ctx, rayService, activeCluster, pendingCluster)
+	assert.Nil(t, err)
+	assert.True(t, abandoned)
+	assert.True(t, errors.IsNotFound(fakeClient.Get(ctx, client.ObjectKey{Name: pendingCluster.Name, Namespace: "default"}, &v1alpha1.RayCluster{})))
+	assert.Empty(t, rayService.Status.PendingServiceStatus.RayClusterName)
+	assert.Nil(t, rayService.Status.PendingClusterStartTime)
+	assert.Equal(t, v1alpha1.UpgradeFailedReason, rayService.Status.Reason)
+	assert.Equal(t, int64(2), rayService.Status.UpgradeFailedGeneration)
+
+	// Test 3: The active RayCluster keeps serving until the spec changes.
+	abandoned, err = r.checkPendingClusterTimeout(ctx, rayService, activeCluster, nil)
+	assert.Nil(t, err)
+	assert.False(t, abandoned)
+	assert.False(t, r.shouldPrepareNewRayCluster(rayService, activeCluster))
+	rayService.Generation = 3
+	_, err = r.checkPendingClusterTimeout(ctx, rayService, activeCluster, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, rayService.Status.Reason)
+	assert.True(t, r.shouldPrepareNewRayCluster(rayService, activeCluster))
+
+	// Test 4: Without an active RayCluster, the pending RayCluster is kept.
+	rayService.Status.PendingServiceStatus.RayClusterName = pendingCluster.Name
+	rayService.Status.PendingClusterStartTime = &metav1.Time{Time: time.Now().Add(-11 * time.Minute)}
+	abandoned, err = r.checkPendingClusterTimeout(ctx, rayService, nil, nil)
+	assert.Nil(t, err)
+	assert.False(t, abandoned)
+}
+
+func TestCheckIfNeedSubmitServeDeployment(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			ServeApplications: []v1alpha1.ServeApplicationSpec{{Name: "fruit", ImportPath: "fruit.deployment_graph"}},
+		},
+	}
+	cluster := &v1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample-raycluster-abcde", Namespace: "default"},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(cluster).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	fakeDashboardClient := &utils.FakeRayDashboardClient{}
+	serveStatus := &v1alpha1.RayServiceStatus{}
+
+	// Test 1: The Serve config is submitted to a RayCluster without a Serve config hash.
+	assert.True(t, r.checkIfNeedSubmitServeDeployment(rayService, cluster, serveStatus))
+	assert.Nil(t, r.updateServeDeployment(ctx, rayService, fakeDashboardClient, cluster, utils.ServeApplicationsAPI))
+
+	// Test 2: The hash of the submitted Serve config is persisted on the RayCluster, e.g. for a restarted operator.
+	storedCluster := &v1alpha1.RayCluster{}
+	assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: "default"}, storedCluster))
+	assert.NotEmpty(t, storedCluster.Annotations[common.RayServiceServeConfigHashKey])
+	assert.False(t, r.checkIfNeedSubmitServeDeployment(rayService, storedCluster, serveStatus))
+
+	// Test 3: A new Serve config is submitted again.
+	rayService.Spec.ServeApplications[0].RoutePrefix = "/fruit"
+	assert.True(t, r.checkIfNeedSubmitServeDeployment(rayService, storedCluster, serveStatus))
+
+	// Test 4: The new Serve config isn't submitted to the active RayCluster after the upgrade failed, until the spec
+	// changes again.
+	rayService.Generation = 2
+	rayService.Status.Reason = v1alpha1.UpgradeFailedReason
+	rayService.Status.UpgradeFailedGeneration = 2
+	assert.False(t, r.checkIfNeedSubmitServeDeployment(rayService, storedCluster, serveStatus))
+	rayService.Generation = 3
+	assert.True(t, r.checkIfNeedSubmitServeDeployment(rayService, storedCluster, serveStatus))
+}
+
+func TestCleanUpRayClusterInstance(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(newScheme)
+
+	newCluster := func(name string, annotations map[string]string) *v1alpha1.RayCluster {
+		return &v1alpha1.RayCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Labels:      map[string]string{common.RayServiceLabelKey: "rayservice-sample"},
+				Annotations: annotations,
+			},
+		}
+	}
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Status: v1alpha1.RayServiceStatuses{
+			ActiveServiceStatus: v1alpha1.RayServiceStatus{RayClusterName: "active"},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(
+		newCluster("active", nil),
+		newCluster("dangling", nil),
+		newCluster("expired", map[string]string{common.RayClusterDeletionTimestampAnnotationKey: time.Now().Add(-time.Second).Format(time.RFC3339)}),
+		newCluster("invalid", map[string]string{common.RayClusterDeletionTimestampAnnotationKey: "soon"}),
+	).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+
+	assert.Nil(t, r.cleanUpRayClusterInstance(ctx, rayService))
+	clusters := v1alpha1.RayClusterList{}
+	assert.Nil(t, fakeClient.List(ctx, &clusters))
+	assert.Len(t, clusters.Items, 2)
+	for _, cluster := range clusters.Items {
+		switch cluster.Name {
+		case "active":
+			assert.NotContains(t, cluster.Annotations, common.RayClusterDeletionTimestampAnnotationKey)
+		case "dangling":
+			// The deletion of a dangling RayCluster is scheduled in an annotation.
+			deletionTime, err := time.Parse(time.RFC3339, cluster.Annotations[common.RayClusterDeletionTimestampAnnotationKey])
+			assert.Nil(t, err)
+			assert.True(t, deletionTime.After(time.Now()))
+		default:
+			t.Errorf("RayCluster %s should have been deleted", cluster.Name)
+		}
+	}
+}
+
+// fakeHttpProxyClient fails the health checks of the Serve HTTP proxies of unhealthyIPs.
+type fakeHttpProxyClient struct {
+	unhealthyIPs map[string]bool
+	hostIP       string
+}
+
+func (c *fakeHttpProxyClient) InitClient(timeout time.Duration) {}
+
+func (c *fakeHttpProxyClient) SetHostIp(hostIp string, port int) {
+	c.hostIP = hostIp
+}
+
+func (c *fakeHttpProxyClient) CheckHealth() error {
+	if c.unhealthyIPs[c.hostIP] {
+		return fmt.Errorf("proxy on %s is unhealthy", c.hostIP)
+	}
+	return nil
+}
+
+func TestLabelHealthyServePods(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(newScheme)
+
+	newPod := func(name string, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+				Labels:    map[string]string{common.RayClusterLabelKey: "raycluster"},
+			},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "ray-head"}}},
+			Status: corev1.PodStatus{PodIP: ip},
+		}
+	}
+	cluster := &v1alpha1.RayCluster{ObjectMeta: metav1.ObjectMeta{Name: "raycluster", Namespace: "default"}}
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			ServeProxyHealthCheck: &v1alpha1.ServeProxyHealthCheckSpec{FailureThreshold: pointer.Int32(2)},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(newPod("head", "10.0.0.1"), newPod("worker", "10.0.0.2")).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+
+	unhealthyIPs := map[string]bool{}
+	previousGetRayHttpProxyClientFunc := utils.GetRayHttpProxyClientFunc
+	defer func() { utils.GetRayHttpProxyClientFunc = previousGetRayHttpProxyClientFunc }()
+	utils.GetRayHttpProxyClientFunc = func() utils.RayHttpProxyClientInterface {
+		return &fakeHttpProxyClient{unhealthyIPs: unhealthyIPs}
+	}
+	servingLabel := func(name string) string {
+		pod := &corev1.Pod{}
+		assert.Nil(t, fakeClient.Get(ctx, client.ObjectKey{Name: name, Namespace: "default"}, pod))
+		return pod.Labels[common.RayClusterServingServiceLabelKey]
+	}
+
+	// Test 1: The healthy pods are labeled after one successful health check.
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("head"))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("worker"))
+
+	// Test 2: A pod keeps serving until its failures reach the failure threshold.
+	unhealthyIPs["10.0.0.2"] = true
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("worker"))
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.Equal(t, common.EnableRayClusterServingServiceFalse, servingLabel("worker"))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("head"))
+
+	// Test 3: A success resets the failures.
+	delete(unhealthyIPs, "10.0.0.2")
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("worker"))
+	unhealthyIPs["10.0.0.2"] = true
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.Equal(t, common.EnableRayClusterServingServiceTrue, servingLabel("worker"))
+
+	// Test 4: The results of deleted pods are forgotten.
+	assert.Nil(t, fakeClient.Delete(ctx, newPod("worker", "10.0.0.2")))
+	assert.Nil(t, r.labelHealthyServePods(ctx, rayService, cluster))
+	assert.NotContains(t, r.servePodHealth.results, types.UID("worker"))
+	assert.Contains(t, r.servePodHealth.results, types.UID("head"))
+}
+
+func TestCheckApplicationProbes(t *testing.T) {
+	newScheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(newScheme)
+
+	fruitHealthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/fruit/price" && req.Method == http.MethodPost && fruitHealthy:
+			_, _ = w.Write([]byte(`{"price": 6}`))
+		case req.URL.Path == "/fruit/price":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	port, err := strconv.Atoi(serverURL.Port())
+	assert.Nil(t, err)
+
+	cluster := &v1alpha1.RayCluster{ObjectMeta: metav1.ObjectMeta{Name: "raycluster", Namespace: "default"}}
+	headPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "raycluster-head",
+			Namespace: "default",
+			Labels:    map[string]string{common.RayClusterLabelKey: cluster.Name, common.RayNodeTypeLabelKey: string(v1alpha1.HeadNode)},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "ray-head",
+			Ports: []corev1.ContainerPort{{Name: common.DefaultServingPortName, ContainerPort: int32(port)}},
+		}}},
+		Status: corev1.PodStatus{PodIP: serverURL.Hostname()},
+	}
+	rayService := &v1alpha1.RayService{
+		ObjectMeta: metav1.ObjectMeta{Name: "rayservice-sample", Namespace: "default"},
+		Spec: v1alpha1.RayServiceSpec{
+			ApplicationProbes: []v1alpha1.ServeApplicationProbe{
+				{
+					Name:              "price",
+					Path:              "/fruit/price",
+					Method:            http.MethodPost,
+					Body:              `["MANGO", 2]`,
+					ExpectedBodyRegex: `"price": \d+`,
+					FailureThreshold:  pointer.Int32(2),
+				},
+				{
+					Name:           "missing",
+					Path:           "/missing",
+					ExpectedStatus: pointer.Int32(http.StatusNotFound),
+				},
+			},
+		},
+	}
+	fakeClient := clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(headPod).Build()
+	r := &RayServiceReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayService"),
+	}
+	ctx := context.Background()
+	status := &v1alpha1.RayServiceStatus{}
+	expireProbes := func() {
+		for name, probeStatus := range status.ApplicationProbes {
+			probeStatus.LastProbeTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			status.ApplicationProbes[name] = probeStatus
+		}
+	}
+
+	// Test 1: The RayCluster is ready when all the probes succeed.
+	isHealthy, isReady := r.checkApplicationProbes(ctx, rayService, cluster, status)
+	assert.True(t, isHealthy)
+	assert.True(t, isReady)
+	assert.True(t, status.ApplicationProbes["price"].Succeeded)
+	assert.True(t, status.ApplicationProbes["missing"].Succeeded)
+
+	// Test 2: A failed probe makes the RayCluster not ready, but healthy below its failure threshold.
+	fruitHealthy = false
+	expireProbes()
+	isHealthy, isReady = r.checkApplicationProbes(ctx, rayService, cluster, status)
+	assert.True(t, isHealthy)
+	assert.False(t, isReady)
+	assert.Equal(t, int32(1), status.ApplicationProbes["price"].ConsecutiveFailures)
+	assert.NotEmpty(t, status.ApplicationProbes["price"].Message)
+
+	// Test 3: The probes are only sent once per period.
+	isHealthy, _ = r.checkApplicationProbes(ctx, rayService, cluster, status)
+	assert.True(t, isHealthy)
+	assert.Equal(t, int32(1), status.ApplicationProbes["price"].ConsecutiveFailures)
+
+	// Test 4: The RayCluster is unhealthy once a probe reaches its failure threshold.
+	expireProbes()
+	isHealthy, isReady = r.checkApplicationProbes(ctx, rayService, cluster, status)
+	assert.False(t, isHealthy)
+	assert.False(t, isReady)
+	assert.Equal(t, int32(2), status.ApplicationProbes["price"].ConsecutiveFailures)
+
+	// Test 5: The results of removed probes are forgotten.
+	rayService.Spec.ApplicationProbes = rayService.Spec.ApplicationProbes[1:]
+	r.checkApplicationProbes(ctx, rayService, cluster, status)
+	assert.NotContains(t, status.ApplicationProbes, "price")
+}
@@ -0,0 +1,254 @@
+package ray
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+)
+
+// Definition of an index field for the RayCluster of a RayWorkerGroup
+var rayWorkerGroupClusterIndexField = "spec.rayClusterName"
+
+// RayWorkerGroupReconciler reconciles a RayWorkerGroup object
+type RayWorkerGroupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// NewRayWorkerGroupReconciler returns a new reconcile.Reconciler
+func NewRayWorkerGroupReconciler(mgr manager.Manager) *RayWorkerGroupReconciler {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &rayv1alpha1.RayWorkerGroup{}, rayWorkerGroupClusterIndexField, func(rawObj client.Object) []string {
+		workerGroup := rawObj.(*rayv1alpha1.RayWorkerGroup)
+		return []string{workerGroup.Spec.RayClusterName}
+	}); err != nil {
+		panic(err)
+	}
+
+	return &RayWorkerGroupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Log:      ctrl.Log.WithName("controllers").WithName("RayWorkerGroup"),
+		Recorder: mgr.GetEventRecorderFor("rayworkergroup-controller"),
+	}
+}
+
+// +kubebuilder:rbac:groups=ray.io,resources=rayworkergroups,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ray.io,resources=rayworkergroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ray.io,resources=rayclusters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+
+// [WARNING]: There MUST be a newline after kubebuilder markers.
+// Reconcile writes the replicas of a RayWorkerGroup, clamped by the minReplicas and maxReplicas of the worker
+// group, to the worker group of the RayCluster, and reports the worker Pods of the group for the scale subresource.
+func (r *RayWorkerGroupReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	r.Log.Info("reconciling RayWorkerGroup", "NamespacedName", request.NamespacedName)
+
+	workerGroup := &rayv1alpha1.RayWorkerGroup{}
+	if err := r.Get(ctx, request.NamespacedName, workerGroup); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !workerGroup.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	originalStatus := workerGroup.Status.DeepCopy()
+
+	workerGroup.Status.ObservedGeneration = workerGroup.Generation
+	workerGroup.Status.Selector = labels.SelectorFromSet(workerGroupSelectorLabels(workerGroup)).String()
+	replicas, err := r.countWorkerPods(ctx, workerGroup)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	workerGroup.Status.Replicas = replicas
+
+	// The RayCluster is watched, so the RayWorkerGroup is reconciled again once the worker group exists.
+	cluster := &rayv1alpha1.RayCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: workerGroup.Namespace, Name: workerGroup.Spec.RayClusterName}, cluster); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.setNotScaling(workerGroup, "RayClusterNotFound", "RayCluster %s not found", workerGroup.Spec.RayClusterName)
+		return ctrl.Result{}, r.updateStatus(ctx, workerGroup, originalStatus)
+	}
+	index := findWorkerGroup(cluster, workerGroup.Spec.GroupName)
+	if index < 0 {
+		r.setNotScaling(workerGroup, "WorkerGroupNotFound", "RayCluster %s has no worker group %s", cluster.Name, workerGroup.Spec.GroupName)
+		return ctrl.Result{}, r.updateStatus(ctx, workerGroup, originalStatus)
+	}
+	group := &cluster.Spec.WorkerGroupSpecs[index]
+
+	// Adopt the replicas of the worker group, so that the scale subresource starts from them.
+	if workerGroup.Spec.Replicas == nil {
+		current := int32(1)
+		if group.Replicas != nil {
+			current = *group.Replicas
+		}
+		workerGroup.Spec.Replicas = &current
+		return ctrl.Result{}, r.Update(ctx, workerGroup)
+	}
+
+	desired := clampWorkerGroupReplicas(group, *workerGroup.Spec.Replicas)
+	workerGroup.Status.DesiredReplicas = desired
+	if desired != *workerGroup.Spec.Replicas {
+		r.Log.Info("RayWorkerGroup replicas are out of the bounds of the worker group", "RayWorkerGroup", request.NamespacedName,
+			"replicas", *workerGroup.Spec.Replicas, "desired", desired)
+	}
+
+	if cluster.Spec.EnableInTreeAutoscaling != nil && *cluster.Spec.EnableInTreeAutoscaling {
+		// The Ray autoscaler also writes the replicas of the worker groups, so the two would fight.
+		r.setNotScaling(workerGroup, "AutoscalerEnabled",
+			"Not scaling worker group %s because RayCluster %s uses the Ray autoscaler", group.GroupName, cluster.Name)
+		return ctrl.Result{}, r.updateStatus(ctx, workerGroup, originalStatus)
+	}
+	if serviceName := cluster.Labels[common.RayServiceLabelKey]; serviceName != "" {
+		// The RayService updates the worker groups of its RayClusters from its own spec, so the two would fight.
+		r.setNotScaling(workerGroup, "RayServiceOwned",
+			"Not scaling worker group %s because RayCluster %s is managed by RayService %s", group.GroupName, cluster.Name, serviceName)
+		return ctrl.Result{}, r.updateStatus(ctx, workerGroup, originalStatus)
+	}
+	workerGroup.Status.Reason = ""
+	workerGroup.Status.Message = ""
+
+	if group.Replicas == nil || *group.Replicas != desired {
+		previous := group.Replicas
+		group.Replicas = &desired
+		if err := r.Update(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
+		if previous != nil {
+			r.Recorder.Eventf(workerGroup, corev1.EventTypeNormal, "Scaled", "Scaled worker group %s of RayCluster %s from %d to %d",
+				group.GroupName, cluster.Name, *previous, desired)
+		} else {
+			r.Recorder.Eventf(workerGroup, corev1.EventTypeNormal, "Scaled", "Scaled worker group %s of RayCluster %s to %d",
+				group.GroupName, cluster.Name, desired)
+		}
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, workerGroup, originalStatus)
+}
+
+// setNotScaling records in the status why the worker group isn't scaled. The warning event is only recorded when the
+// reason changes, since the RayWorkerGroup is reconciled whenever the Pods of its RayCluster change.
+func (r *RayWorkerGroupReconciler) setNotScaling(workerGroup *rayv1alpha1.RayWorkerGroup, reason string, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if workerGroup.Status.Reason != reason {
+		r.Recorder.Event(workerGroup, corev1.EventTypeWarning, reason, message)
+	}
+	workerGroup.Status.Reason = reason
+	workerGroup.Status.Message = message
+}
+
+// updateStatus updates the status of the RayWorkerGroup, if it changed.
+func (r *RayWorkerGroupReconciler) updateStatus(ctx context.Context, workerGroup *rayv1alpha1.RayWorkerGroup, originalStatus *rayv1alpha1.RayWorkerGroupStatus) error {
+	if reflect.DeepEqual(*originalStatus, workerGroup.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, workerGroup)
+}
+
+// SetupWithManager sets up the controller with the Manager. The RayWorkerGroups are reconciled when their RayCluster
+// or its Pods change.
+func (r *RayWorkerGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&rayv1alpha1.RayWorkerGroup{}).
+		Watches(&source.Kind{Type: &rayv1alpha1.RayCluster{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+			return r.workerGroupsOfCluster(obj.GetNamespace(), obj.GetName())
+		})).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+			if obj.GetLabels()[common.RayNodeTypeLabelKey] != string(rayv1alpha1.WorkerNode) {
+				return nil
+			}
+			return r.workerGroupsOfCluster(obj.GetNamespace(), obj.GetLabels()[common.RayClusterLabelKey])
+		})).
+		Complete(r)
+}
+
+// workerGroupsOfCluster returns the requests of the RayWorkerGroups of a RayCluster.
+func (r *RayWorkerGroupReconciler) workerGroupsOfCluster(namespace string, clusterName string) []reconcile.Request {
+	if clusterName == "" {
+		return nil
+	}
+	workerGroups := rayv1alpha1.RayWorkerGroupList{}
+	if err := r.List(context.Background(), &workerGroups, client.InNamespace(namespace),
+		client.MatchingFields{rayWorkerGroupClusterIndexField: clusterName}); err != nil {
+		r.Log.Error(err, "failed to list the RayWorkerGroups of RayCluster", "RayCluster", clusterName)
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(workerGroups.Items))
+	for _, workerGroup := range workerGroups.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: workerGroup.Namespace, Name: workerGroup.Name}})
+	}
+	return requests
+}
+
+// countWorkerPods returns the number of worker Pods of the group that are not being deleted.
+func (r *RayWorkerGroupReconciler) countWorkerPods(ctx context.Context, workerGroup *rayv1alpha1.RayWorkerGroup) (int32, error) {
+	pods := corev1.PodList{}
+	if err := r.List(ctx, &pods, client.InNamespace(workerGroup.Namespace), client.MatchingLabels(workerGroupSelectorLabels(workerGroup))); err != nil {
+		return 0, err
+	}
+	var count int32
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// workerGroupSelectorLabels returns the labels of the worker Pods of the group of a RayWorkerGroup.
+func workerGroupSelectorLabels(workerGroup *rayv1alpha1.RayWorkerGroup) map[string]string {
+	return map[string]string{
+		common.RayClusterLabelKey:   workerGroup.Spec.RayClusterName,
+		common.RayNodeGroupLabelKey: workerGroup.Spec.GroupName,
+	}
+}
+
+// findWorkerGroup returns the index of the worker group of the RayCluster, or -1 if there is none.
+func findWorkerGroup(cluster *rayv1alpha1.RayCluster, groupName string) int {
+	for i, group := range cluster.Spec.WorkerGroupSpecs {
+		if group.GroupName == groupName {
+			return i
+		}
+	}
+	return -1
+}
+
+// clampWorkerGroupReplicas clamps replicas by the minReplicas and maxReplicas of the worker group. Unset bounds don't
+// limit the replicas.
+func clampWorkerGroupReplicas(group *rayv1alpha1.WorkerGroupSpec, replicas int32) int32 {
+	minReplicas, maxReplicas := int32(0), int32(math.MaxInt32)
+	if group.MinReplicas != nil {
+		minReplicas = *group.MinReplicas
+	}
+	if group.MaxReplicas != nil {
+		maxReplicas = *group.MaxReplicas
+	}
+	if replicas > maxReplicas {
+		replicas = maxReplicas
+	}
+	if replicas < minReplicas {
+		replicas = minReplicas
+	}
+	return replicas
+}
@@ -0,0 +1,149 @@
+package ray
+
+import (
+	"context"
+	"testing"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/common"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	clientFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestWorkerPod(name string, groupName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels: map[string]string{
+				common.RayClusterLabelKey:   "raycluster-sample",
+				common.RayNodeGroupLabelKey: groupName,
+				common.RayNodeTypeLabelKey:  string(rayv1alpha1.WorkerNode),
+			},
+		},
+	}
+}
+
+func newTestRayWorkerGroupReconciler(objects ...runtime.Object) *RayWorkerGroupReconciler {
+	newScheme := runtime.NewScheme()
+	_ = rayv1alpha1.AddToScheme(newScheme)
+	_ = corev1.AddToScheme(newScheme)
+	return &RayWorkerGroupReconciler{
+		Client:   clientFake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(objects...).Build(),
+		Scheme:   newScheme,
+		Log:      ctrl.Log.WithName("controllers").WithName("RayWorkerGroup"),
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestClampWorkerGroupReplicas(t *testing.T) {
+	group := &rayv1alpha1.WorkerGroupSpec{}
+	assert.Equal(t, int32(0), clampWorkerGroupReplicas(group, 0))
+	assert.Equal(t, int32(100), clampWorkerGroupReplicas(group, 100))
+
+	group.MinReplicas = pointer.Int32(1)
+	group.MaxReplicas = pointer.Int32(5)
+	assert.Equal(t, int32(1), clampWorkerGroupReplicas(group, 0))
+	assert.Equal(t, int32(3), clampWorkerGroupReplicas(group, 3))
+	assert.Equal(t, int32(5), clampWorkerGroupReplicas(group, 10))
+}
+
+func TestReconcileRayWorkerGroup(t *testing.T) {
+	workerGroup := &rayv1alpha1.RayWorkerGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "small-group", Namespace: "default"},
+		Spec:       rayv1alpha1.RayWorkerGroupSpec{RayClusterName: "raycluster-sample", GroupName: "small-group"},
+	}
+	cluster := &rayv1alpha1.RayCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raycluster-sample", Namespace: "default"},
+		Spec: rayv1alpha1.RayClusterSpec{
+			WorkerGroupSpecs: []rayv1alpha1.WorkerGroupSpec{
+				{GroupName: "small-group", Replicas: pointer.Int32(2), MinReplicas: pointer.Int32(1), MaxReplicas: pointer.Int32(5)},
+			},
+		},
+	}
+	r := newTestRayWorkerGroupReconciler(workerGroup,
+		newTestWorkerPod("worker-1", "small-group"), newTestWorkerPod("worker-2", "small-group"), newTestWorkerPod("worker-3", "large-group"))
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: workerGroup.Name, Namespace: "default"}}
+	reconcile := func() {
+		_, err := r.Reconcile(ctx, request)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Get(ctx, request.NamespacedName, workerGroup))
+	}
+	scale := func(replicas int32) {
+		workerGroup.Spec.Replicas = &replicas
+		assert.Nil(t, r.Update(ctx, workerGroup))
+		reconcile()
+		assert.Nil(t, r.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: "default"}, cluster))
+	}
+
+	// Test 1: The status reports the worker Pods of the group, even before the RayCluster exists.
+	reconcile()
+	assert.Equal(t, int32(2), workerGroup.Status.Replicas)
+	assert.Equal(t, "ray.io/cluster=raycluster-sample,ray.io/group=small-group", workerGroup.Status.Selector)
+	assert.Nil(t, workerGroup.Spec.Replicas)
+
+	// Test 2: The RayWorkerGroup adopts the replicas of the worker group.
+	assert.Nil(t, r.Create(ctx, cluster))
+	reconcile()
+	assert.Equal(t, int32(2), *workerGroup.Spec.Replicas)
+
+	// Test 3: The replicas are written to the worker group.
+	scale(3)
+	assert.Equal(t, int32(3), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, int32(3), workerGroup.Status.DesiredReplicas)
+
+	// Test 4: The replicas are clamped by the bounds of the worker group.
+	scale(10)
+	assert.Equal(t, int32(5), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, int32(5), workerGroup.Status.DesiredReplicas)
+	scale(0)
+	assert.Equal(t, int32(1), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+
+	// Test 5: The worker groups of a RayCluster using the Ray autoscaler are left to it.
+	cluster.Spec.EnableInTreeAutoscaling = pointer.Bool(true)
+	assert.Nil(t, r.Update(ctx, cluster))
+	scale(4)
+	assert.Equal(t, int32(1), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, "AutoscalerEnabled", workerGroup.Status.Reason)
+	events := r.Recorder.(*record.FakeRecorder).Events
+	lastEvent := func() string {
+		var event string
+		for len(events) > 0 {
+			event = <-events
+		}
+		return event
+	}
+	assert.Contains(t, lastEvent(), "AutoscalerEnabled")
+
+	// Test 6: Reconciling the same state again neither updates the status nor repeats the warning.
+	resourceVersion := workerGroup.ResourceVersion
+	reconcile()
+	assert.Equal(t, resourceVersion, workerGroup.ResourceVersion)
+	assert.Len(t, events, 0)
+
+	// Test 7: The worker groups of a RayCluster of a RayService are left to the RayService.
+	cluster.Spec.EnableInTreeAutoscaling = nil
+	cluster.Labels = map[string]string{common.RayServiceLabelKey: "rayservice-sample"}
+	assert.Nil(t, r.Update(ctx, cluster))
+	scale(3)
+	assert.Equal(t, int32(1), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Equal(t, "RayServiceOwned", workerGroup.Status.Reason)
+	assert.Contains(t, lastEvent(), "RayServiceOwned")
+
+	// Test 8: The worker group is scaled again once nothing else writes its replicas.
+	cluster.Labels = nil
+	assert.Nil(t, r.Update(ctx, cluster))
+	reconcile()
+	assert.Nil(t, r.Get(ctx, types.NamespacedName{Name: cluster.Name, Namespace: "default"}, cluster))
+	assert.Equal(t, int32(3), *cluster.Spec.WorkerGroupSpecs[0].Replicas)
+	assert.Empty(t, workerGroup.Status.Reason)
+	assert.Empty(t, workerGroup.Status.Message)
+}
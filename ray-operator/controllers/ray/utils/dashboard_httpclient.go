@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -28,10 +30,30 @@ const (
 	DefaultDashboardAgentListenPortName = "dashboard-agent"
 )
 
+// RayJobWorkingDirMountPath is where the working directory of a RayJob with workingDirFrom is mounted.
+const RayJobWorkingDirMountPath = "/home/ray/rayjob-working-dir"
+
 var (
-	DeployPath = "/api/serve/deployments/"
-	StatusPath = "/api/serve/deployments/status"
-	JobPath    = "/api/jobs/"
+	DeployPath       = "/api/serve/deployments/"
+	StatusPath       = "/api/serve/deployments/status"
+	ApplicationsPath = "/api/serve/applications/"
+	JobPath          = "/api/jobs/"
+)
+
+// ServeAPI is the API of the Ray dashboard that deploys the Serve config of a RayService.
+type ServeAPI string
+
+const (
+	// ServeDeploymentsAPI deploys the single application of serveConfig.
+	ServeDeploymentsAPI ServeAPI = "deployments"
+	// ServeApplicationsAPI deploys the applications of serveApplications or serveConfigV2.
+	ServeApplicationsAPI ServeAPI = "applications"
+)
+
+// The Serve applications API is available from Ray 2.4.
+const (
+	serveApplicationsMinMajorVersion = 2
+	serveApplicationsMinMinorVersion = 4
 )
 
 // ServeConfigSpec defines the desired state of RayService, used by Ray Dashboard.
@@ -60,6 +82,51 @@ type RayActorOptionSpec struct {
 	AcceleratorType   string                 `json:"accelerator_type,omitempty"`
 }
 
+// ServeApplicationsConfig defines the request sent to the Serve applications API.
+// See https://docs.ray.io/en/master/serve/api/doc/ray.serve.schema.ServeDeploySchema.html for more details.
+type ServeApplicationsConfig struct {
+	GRPCOptions  *ServeGRPCOptions        `json:"grpc_options,omitempty"`
+	Applications []ServeApplicationConfig `json:"applications"`
+}
+
+// ServeGRPCOptions configures the gRPC proxy of a ServeApplicationsConfig.
+type ServeGRPCOptions struct {
+	Port                  int      `json:"port"`
+	GRPCServicerFunctions []string `json:"grpc_servicer_functions,omitempty"`
+}
+
+// ServeApplicationConfig defines an application of a ServeApplicationsConfig.
+type ServeApplicationConfig struct {
+	Name        string                 `json:"name"`
+	ImportPath  string                 `json:"import_path"`
+	RoutePrefix string                 `json:"route_prefix,omitempty"`
+	RuntimeEnv  map[string]interface{} `json:"runtime_env,omitempty"`
+	Args        map[string]interface{} `json:"args,omitempty"`
+	Deployments []ServeConfigSpec      `json:"deployments,omitempty"`
+}
+
+// ServeApplicationStatuses defines the current states of the Serve applications, as returned by the Serve
+// applications API.
+type ServeApplicationStatuses struct {
+	Applications map[string]ServeApplicationStatus `json:"applications,omitempty"`
+}
+
+// ServeApplicationStatus defines the current state of a Serve application and of its deployments.
+type ServeApplicationStatus struct {
+	Name        string                                      `json:"name,omitempty"`
+	Status      string                                      `json:"status,omitempty"`
+	Message     string                                      `json:"message,omitempty"`
+	RoutePrefix string                                      `json:"route_prefix,omitempty"`
+	Deployments map[string]ServeApplicationDeploymentStatus `json:"deployments,omitempty"`
+}
+
+// ServeApplicationDeploymentStatus defines the current state of a deployment of a Serve application.
+type ServeApplicationDeploymentStatus struct {
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
 // ServeDeploymentStatuses defines the current states of all Serve Deployments.
 type ServeDeploymentStatuses struct {
 	ApplicationStatus  rayv1alpha1.AppStatus               `json:"app_status,omitempty"`
@@ -80,10 +147,14 @@ type RayDashboardClientInterface interface {
 	GetDeployments(context.Context) (string, error)
 	UpdateDeployments(ctx context.Context, spec rayv1alpha1.ServeDeploymentGraphSpec) error
 	GetDeploymentsStatus(context.Context) (*ServeDeploymentStatuses, error)
+	UpdateApplications(ctx context.Context, spec *rayv1alpha1.RayServiceSpec, grpcPort int) error
+	GetApplicationsStatus(context.Context) (*ServeApplicationStatuses, error)
 	ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec
 	GetJobInfo(ctx context.Context, jobId string) (*RayJobInfo, error)
+	ListJobs(ctx context.Context) ([]RayJobInfo, error)
 	SubmitJob(ctx context.Context, rayJob *rayv1alpha1.RayJob, log *logr.Logger) (jobId string, err error)
 	StopJob(ctx context.Context, jobName string, log *logr.Logger) (err error)
+	GetJobLog(ctx context.Context, jobName string, log *logr.Logger) (*string, error)
 }
 
 // GetRayDashboardClientFunc Used for unit tests.
@@ -256,7 +327,159 @@ func (r *RayDashboardClient) GetDeploymentsStatus(ctx context.Context) (*ServeDe
 	return &serveStatuses, nil
 }
 
+// UpdateApplications deploys the applications of serveApplications or serveConfigV2 in the Ray cluster.
+// Applications that are not in the config anymore are deleted by Serve. grpcPort is the port of the Serve gRPC proxy
+// of the cluster, used with serveGRPC.
+func (r *RayDashboardClient) UpdateApplications(ctx context.Context, spec *rayv1alpha1.RayServiceSpec, grpcPort int) error {
+	config, err := GetServeApplicationsConfig(spec, grpcPort)
+	if err != nil {
+		return err
+	}
+	configJson, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.dashboardURL+ApplicationsPath, bytes.NewBuffer(configJson))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("UpdateApplications fail: %s %s", resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// GetApplicationsStatus gets the current statuses of the Serve applications and their deployments in the Ray cluster.
+func (r *RayDashboardClient) GetApplicationsStatus(ctx context.Context) (*ServeApplicationStatuses, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.dashboardURL+ApplicationsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GetApplicationsStatus fail: %s %s", resp.Status, string(body))
+	}
+
+	var applicationStatuses ServeApplicationStatuses
+	if err = json.Unmarshal(body, &applicationStatuses); err != nil {
+		return nil, fmt.Errorf("GetApplicationsStatus fail: %s", string(body))
+	}
+
+	return &applicationStatuses, nil
+}
+
+// GetServeAPI returns the Serve API that deploys the Serve config of a RayService, and an error if the Serve config
+// is invalid. serveApplications and serveConfigV2 need the Serve applications API, which is only available from
+// Ray 2.4, so they are rejected for an older rayVersion of the RayCluster.
+func GetServeAPI(spec *rayv1alpha1.RayServiceSpec) (ServeAPI, error) {
+	hasApplications := len(spec.ServeApplications) > 0
+	hasConfigV2 := spec.ServeConfigV2 != ""
+	if !hasApplications && !hasConfigV2 {
+		if spec.ServeGRPC != nil {
+			return "", fmt.Errorf("serveGRPC needs serveApplications or serveConfigV2")
+		}
+		return ServeDeploymentsAPI, nil
+	}
+	if hasApplications && hasConfigV2 {
+		return "", fmt.Errorf("serveApplications and serveConfigV2 can't be set together")
+	}
+	if spec.ServeDeploymentGraphSpec.ImportPath != "" {
+		return "", fmt.Errorf("serveConfig can't be set together with serveApplications or serveConfigV2")
+	}
+	if !IsServeApplicationsAPISupported(spec.RayClusterSpec.RayVersion) {
+		return "", fmt.Errorf("serveApplications and serveConfigV2 need Ray %d.%d or newer, but rayVersion is %s",
+			serveApplicationsMinMajorVersion, serveApplicationsMinMinorVersion, spec.RayClusterSpec.RayVersion)
+	}
+	if _, err := GetServeApplicationsConfig(spec, 0); err != nil {
+		return "", err
+	}
+	return ServeApplicationsAPI, nil
+}
+
+// IsServeApplicationsAPISupported returns whether the dashboard of the given rayVersion has the Serve applications API.
+// Like for the autoscaler, it returns false exactly when the version is successfully parsed and older than 2.4:
+// "2.4.0", "2.5", "3", "nightly" and "" return true, "2.3.1", "2.0" and "1.13.0" return false.
+func IsServeApplicationsAPISupported(rayVersion string) bool {
+	parts := strings.SplitN(rayVersion, ".", 3)
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return true
+	}
+	if major != serveApplicationsMinMajorVersion || len(parts) < 2 {
+		return major > serveApplicationsMinMajorVersion
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return true
+	}
+	return minor >= serveApplicationsMinMinorVersion
+}
+
+// GetServeApplicationsConfig returns the request of the Serve applications API for serveApplications or serveConfigV2.
+// With serveGRPC, the gRPC proxy of serveApplications listens on grpcPort.
+func GetServeApplicationsConfig(spec *rayv1alpha1.RayServiceSpec, grpcPort int) (interface{}, error) {
+	if spec.ServeConfigV2 != "" {
+		config := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(spec.ServeConfigV2), &config); err != nil {
+			return nil, fmt.Errorf("invalid serveConfigV2: %v", err)
+		}
+		if _, ok := config["applications"]; !ok {
+			return nil, fmt.Errorf("invalid serveConfigV2: it has no applications")
+		}
+		return config, nil
+	}
+
+	config := ServeApplicationsConfig{Applications: make([]ServeApplicationConfig, len(spec.ServeApplications))}
+	if spec.ServeGRPC != nil {
+		config.GRPCOptions = &ServeGRPCOptions{
+			Port:                  grpcPort,
+			GRPCServicerFunctions: spec.ServeGRPC.ServicerFunctions,
+		}
+	}
+	for i, app := range spec.ServeApplications {
+		runtimeEnv := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(app.RuntimeEnv), &runtimeEnv); err != nil {
+			return nil, fmt.Errorf("invalid runtimeEnv of Serve application %s: %v", app.Name, err)
+		}
+		args := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(app.Args), &args); err != nil {
+			return nil, fmt.Errorf("invalid args of Serve application %s: %v", app.Name, err)
+		}
+		config.Applications[i] = ServeApplicationConfig{
+			Name:        app.Name,
+			ImportPath:  app.ImportPath,
+			RoutePrefix: app.RoutePrefix,
+			RuntimeEnv:  runtimeEnv,
+			Args:        args,
+			Deployments: convertServeConfig(app.ServeConfigSpecs),
+		}
+	}
+	return config, nil
+}
+
 func (r *RayDashboardClient) ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec {
+	return convertServeConfig(specs)
+}
+
+func convertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec {
 	serveConfigToSend := make([]ServeConfigSpec, len(specs))
 
 	for i, config := range specs {
@@ -301,22 +524,39 @@ func (r *RayDashboardClient) ConvertServeConfig(specs []rayv1alpha1.ServeConfigS
 // RayJobInfo is the response of "ray job status" api.
 // Reference to https://docs.ray.io/en/latest/cluster/jobs-package-ref.html#jobinfo.
 type RayJobInfo struct {
-	JobStatus  rayv1alpha1.JobStatus `json:"status,omitempty"`
-	Entrypoint string                `json:"entrypoint,omitempty"`
-	Message    string                `json:"message,omitempty"`
-	ErrorType  *string               `json:"error_type,omitempty"`
-	StartTime  int64                 `json:"start_time,omitempty"`
-	EndTime    int64                 `json:"end_time,omitempty"`
-	Metadata   map[string]string     `json:"metadata,omitempty"`
+	JobStatus      rayv1alpha1.JobStatus  `json:"status,omitempty"`
+	Entrypoint     string                 `json:"entrypoint,omitempty"`
+	Message        string                 `json:"message,omitempty"`
+	ErrorType      *string                `json:"error_type,omitempty"`
+	StartTime      int64                  `json:"start_time,omitempty"`
+	EndTime        int64                  `json:"end_time,omitempty"`
+	Metadata       map[string]string      `json:"metadata,omitempty"`
+	JobId          string                 `json:"job_id,omitempty"`
+	SubmissionId   string                 `json:"submission_id,omitempty"`
+	DriverInfo     *RayJobDriverInfo      `json:"driver_info,omitempty"`
+	DriverNodeId   string                 `json:"driver_node_id,omitempty"`
+	DriverExitCode *int32                 `json:"driver_exit_code,omitempty"`
+	RuntimeEnv     map[string]interface{} `json:"runtime_env,omitempty"`
+}
+
+// RayJobDriverInfo describes the driver of a Ray job.
+// Reference to https://docs.ray.io/en/latest/cluster/running-applications/job-submission/doc/ray.job_submission.DriverInfo.html.
+type RayJobDriverInfo struct {
+	Id            string `json:"id,omitempty"`
+	NodeIpAddress string `json:"node_ip_address,omitempty"`
+	Pid           string `json:"pid,omitempty"`
 }
 
 // RayJobRequest is the request body to submit.
 // Reference to https://docs.ray.io/en/latest/cluster/jobs-package-ref.html#jobsubmissionclient.
 type RayJobRequest struct {
-	Entrypoint string                 `json:"entrypoint"`
-	JobId      string                 `json:"job_id,omitempty"`
-	RuntimeEnv map[string]interface{} `json:"runtime_env,omitempty"`
-	Metadata   map[string]string      `json:"metadata,omitempty"`
+	Entrypoint          string                 `json:"entrypoint"`
+	JobId               string                 `json:"job_id,omitempty"`
+	RuntimeEnv          map[string]interface{} `json:"runtime_env,omitempty"`
+	Metadata            map[string]string      `json:"metadata,omitempty"`
+	EntrypointNumCpus   *float64               `json:"entrypoint_num_cpus,omitempty"`
+	EntrypointNumGpus   *float64               `json:"entrypoint_num_gpus,omitempty"`
+	EntrypointResources map[string]float64     `json:"entrypoint_resources,omitempty"`
 }
 
 type RayJobResponse struct {
@@ -327,6 +567,10 @@ type RayJobStopResponse struct {
 	Stopped bool `json:"stopped"`
 }
 
+type RayJobLogsResponse struct {
+	Logs string `json:"logs,omitempty"`
+}
+
 func (r *RayDashboardClient) GetJobInfo(ctx context.Context, jobId string) (*RayJobInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", r.dashboardURL+JobPath+jobId, nil)
 	if err != nil {
@@ -357,6 +601,33 @@ func (r *RayDashboardClient) GetJobInfo(ctx context.Context, jobId string) (*Ray
 	return &jobInfo, nil
 }
 
+// ListJobs returns all the Ray jobs known by the dashboard, including the ones that are not submitted through
+// the Ray Jobs API, which have no submission ID.
+func (r *RayDashboardClient) ListJobs(ctx context.Context) ([]RayJobInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.dashboardURL+JobPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobInfos []RayJobInfo
+	if err = json.Unmarshal(body, &jobInfos); err != nil {
+		// Maybe body is not valid json, raise an error with the body.
+		return nil, fmt.Errorf("ListJobs fail: %s", string(body))
+	}
+	return jobInfos, nil
+}
+
 func (r *RayDashboardClient) SubmitJob(ctx context.Context, rayJob *rayv1alpha1.RayJob, log *logr.Logger) (jobId string, err error) {
 	request, err := ConvertRayJobToReq(rayJob)
 	if err != nil {
@@ -426,24 +697,70 @@ func (r *RayDashboardClient) StopJob(ctx context.Context, jobName string, log *l
 	return nil
 }
 
-func ConvertRayJobToReq(rayJob *rayv1alpha1.RayJob) (*RayJobRequest, error) {
-	req := &RayJobRequest{
-		Entrypoint: rayJob.Spec.Entrypoint,
-		Metadata:   rayJob.Spec.Metadata,
-		JobId:      rayJob.Status.JobId,
-	}
-	if len(rayJob.Spec.RuntimeEnv) == 0 {
-		return req, nil
+// GetJobLog returns the driver logs of the Ray job. It returns nil if the job does not exist.
+func (r *RayDashboardClient) GetJobLog(ctx context.Context, jobName string, log *logr.Logger) (*string, error) {
+	log.Info("Get ray job log", "rayJob", jobName)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.dashboardURL+JobPath+jobName+"/logs", nil)
+	if err != nil {
+		return nil, err
 	}
-	decodeBytes, err := base64.StdEncoding.DecodeString(rayJob.Spec.RuntimeEnv)
+
+	resp, err := r.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to decode runtimeEnv: %v: %v", rayJob.Spec.RuntimeEnv, err)
+		return nil, err
 	}
-	var runtimeEnv map[string]interface{}
-	err = json.Unmarshal(decodeBytes, &runtimeEnv)
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal runtimeEnv: %v: %v", decodeBytes, err)
+		return nil, err
+	}
+
+	var jobLog RayJobLogsResponse
+	if err = json.Unmarshal(body, &jobLog); err != nil {
+		// Maybe body is not valid json, raise an error with the body.
+		return nil, fmt.Errorf("GetJobLog fail: %s", string(body))
+	}
+
+	return &jobLog.Logs, nil
+}
+
+func ConvertRayJobToReq(rayJob *rayv1alpha1.RayJob) (*RayJobRequest, error) {
+	req := &RayJobRequest{
+		Entrypoint:        rayJob.Spec.Entrypoint,
+		Metadata:          rayJob.Spec.Metadata,
+		JobId:             rayJob.Status.JobId,
+		EntrypointNumCpus: rayJob.Spec.EntrypointNumCpus,
+		EntrypointNumGpus: rayJob.Spec.EntrypointNumGpus,
+	}
+	if len(rayJob.Spec.EntrypointResources) > 0 {
+		if err := json.Unmarshal([]byte(rayJob.Spec.EntrypointResources), &req.EntrypointResources); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal entrypointResources: %v: %v", rayJob.Spec.EntrypointResources, err)
+		}
+	}
+	if len(rayJob.Spec.RuntimeEnv) != 0 {
+		decodeBytes, err := base64.StdEncoding.DecodeString(rayJob.Spec.RuntimeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to decode runtimeEnv: %v: %v", rayJob.Spec.RuntimeEnv, err)
+		}
+		var runtimeEnv map[string]interface{}
+		err = json.Unmarshal(decodeBytes, &runtimeEnv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal runtimeEnv: %v: %v", decodeBytes, err)
+		}
+		req.RuntimeEnv = runtimeEnv
+	}
+	if rayJob.Spec.WorkingDirFrom != nil {
+		// The working directory is mounted into the submitter pod, see common.AddWorkingDirVolume.
+		if req.RuntimeEnv == nil {
+			req.RuntimeEnv = make(map[string]interface{})
+		}
+		req.RuntimeEnv["working_dir"] = RayJobWorkingDirMountPath
 	}
-	req.RuntimeEnv = runtimeEnv
 	return req, nil
 }
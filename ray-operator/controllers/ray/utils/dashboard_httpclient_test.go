@@ -52,6 +52,44 @@ var _ = Describe("RayFrameworkGenerator", func() {
 		Expect(err).To(BeNil())
 		Expect(len(rayJobRequest.RuntimeEnv)).To(Equal(4))
 		Expect(rayJobRequest.RuntimeEnv["working_dir"]).To(Equal("./"))
+		Expect(rayJobRequest.EntrypointNumCpus).To(BeNil())
+		Expect(rayJobRequest.EntrypointResources).To(BeNil())
+	})
+
+	It("Test ConvertRayJobToReq with workingDirFrom", func() {
+		rayJob.Spec.WorkingDirFrom = &rayv1alpha1.WorkingDirSource{
+			ConfigMap: &rayv1alpha1.ConfigMapWorkingDirSource{Name: "job-scripts"},
+		}
+		rayJobRequest, err := ConvertRayJobToReq(rayJob)
+		Expect(err).To(BeNil())
+		Expect(rayJobRequest.RuntimeEnv["working_dir"]).To(Equal(RayJobWorkingDirMountPath))
+		Expect(rayJobRequest.RuntimeEnv["eager_install"]).To(Equal(false))
+
+		rayJob.Spec.RuntimeEnv = ""
+		rayJobRequest, err = ConvertRayJobToReq(rayJob)
+		Expect(err).To(BeNil())
+		Expect(rayJobRequest.RuntimeEnv).To(Equal(map[string]interface{}{"working_dir": RayJobWorkingDirMountPath}))
+	})
+
+	It("Test ConvertRayJobToReq with entrypoint resources", func() {
+		numCpus, numGpus := 2.5, 1.0
+		rayJob.Spec.EntrypointNumCpus = &numCpus
+		rayJob.Spec.EntrypointNumGpus = &numGpus
+		rayJob.Spec.EntrypointResources = `{"worker_node": 1, "accelerator": 0.5}`
+		rayJobRequest, err := ConvertRayJobToReq(rayJob)
+		Expect(err).To(BeNil())
+		Expect(*rayJobRequest.EntrypointNumCpus).To(Equal(2.5))
+		Expect(*rayJobRequest.EntrypointNumGpus).To(Equal(1.0))
+		Expect(rayJobRequest.EntrypointResources).To(Equal(map[string]float64{"worker_node": 1, "accelerator": 0.5}))
+
+		body, err := json.Marshal(rayJobRequest)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(ContainSubstring(`"entrypoint_num_cpus":2.5`))
+		Expect(string(body)).To(ContainSubstring(`"entrypoint_resources":{"accelerator":0.5,"worker_node":1}`))
+
+		rayJob.Spec.EntrypointResources = `{"worker_node": "one"}`
+		_, err = ConvertRayJobToReq(rayJob)
+		Expect(err).NotTo(BeNil())
 	})
 
 	It("Test submitting/getting rayJob", func() {
@@ -96,6 +134,26 @@ var _ = Describe("RayFrameworkGenerator", func() {
 		Expect(err.Error()).To(ContainSubstring("Ray misbehaved"))
 	})
 
+	It("Test list jobs", func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+JobPath,
+			func(req *http.Request) (*http.Response, error) {
+				body := []RayJobInfo{
+					{SubmissionId: expectJobId, JobId: "02000000", JobStatus: rayv1alpha1.JobStatusRunning},
+					{JobId: "03000000", JobStatus: rayv1alpha1.JobStatusSucceeded},
+				}
+				bodyBytes, _ := json.Marshal(body)
+				return httpmock.NewBytesResponse(200, bodyBytes), nil
+			})
+
+		jobInfos, err := rayDashboardClient.ListJobs(context.TODO())
+		Expect(err).To(BeNil())
+		Expect(len(jobInfos)).To(Equal(2))
+		Expect(jobInfos[0].SubmissionId).To(Equal(expectJobId))
+		Expect(jobInfos[1].JobId).To(Equal("03000000"))
+	})
+
 	It("Test stop job", func() {
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
@@ -138,4 +196,145 @@ var _ = Describe("RayFrameworkGenerator", func() {
 		err := rayDashboardClient.StopJob(context.TODO(), "stop-job-1", &ctrl.Log)
 		Expect(err).To(BeNil())
 	})
+
+	It("Test get job log", func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+JobPath+"log-job-1/logs",
+			func(req *http.Request) (*http.Response, error) {
+				body := &RayJobLogsResponse{
+					Logs: "hello\nworld\n",
+				}
+				bodyBytes, _ := json.Marshal(body)
+				return httpmock.NewBytesResponse(200, bodyBytes), nil
+			})
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+JobPath+"missing-job/logs",
+			httpmock.NewStringResponder(404, "Job missing-job does not exist"))
+
+		logs, err := rayDashboardClient.GetJobLog(context.TODO(), "log-job-1", &ctrl.Log)
+		Expect(err).To(BeNil())
+		Expect(*logs).To(Equal("hello\nworld\n"))
+
+		logs, err = rayDashboardClient.GetJobLog(context.TODO(), "missing-job", &ctrl.Log)
+		Expect(err).To(BeNil())
+		Expect(logs).To(BeNil())
+	})
+
+	It("Test updating/getting Serve applications", func() {
+		spec := &rayv1alpha1.RayServiceSpec{
+			ServeApplications: []rayv1alpha1.ServeApplicationSpec{
+				{Name: "fruit", ImportPath: "fruit.deployment_graph", RoutePrefix: "/fruit", Args: "price: 3"},
+				{Name: "math", ImportPath: "conditional_dag.serve_dag", RoutePrefix: "/calc"},
+			},
+		}
+		var received ServeApplicationsConfig
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder("PUT", rayDashboardClient.dashboardURL+ApplicationsPath,
+			func(req *http.Request) (*http.Response, error) {
+				if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+					return httpmock.NewStringResponse(400, err.Error()), nil
+				}
+				return httpmock.NewStringResponse(200, ""), nil
+			})
+		httpmock.RegisterResponder("GET", rayDashboardClient.dashboardURL+ApplicationsPath,
+			func(req *http.Request) (*http.Response, error) {
+				body := &ServeApplicationStatuses{
+					Applications: map[string]ServeApplicationStatus{
+						"fruit": {
+							Name:   "fruit",
+							Status: rayv1alpha1.ApplicationStatusEnum.RUNNING,
+							Deployments: map[string]ServeApplicationDeploymentStatus{
+								"MangoStand": {Name: "MangoStand", Status: rayv1alpha1.DeploymentStatusEnum.HEALTHY},
+							},
+						},
+					},
+				}
+				bodyBytes, _ := json.Marshal(body)
+				return httpmock.NewBytesResponse(200, bodyBytes), nil
+			})
+
+		err := rayDashboardClient.UpdateApplications(context.TODO(), spec, 9000)
+		Expect(err).To(BeNil())
+		Expect(len(received.Applications)).To(Equal(2))
+		Expect(received.Applications[0].RoutePrefix).To(Equal("/fruit"))
+		Expect(received.Applications[0].Args).To(Equal(map[string]interface{}{"price": float64(3)}))
+		Expect(received.Applications[1].ImportPath).To(Equal("conditional_dag.serve_dag"))
+
+		applicationStatuses, err := rayDashboardClient.GetApplicationsStatus(context.TODO())
+		Expect(err).To(BeNil())
+		Expect(applicationStatuses.Applications["fruit"].Status).To(Equal(rayv1alpha1.ApplicationStatusEnum.RUNNING))
+		Expect(applicationStatuses.Applications["fruit"].Deployments["MangoStand"].Status).To(Equal(rayv1alpha1.DeploymentStatusEnum.HEALTHY))
+	})
+
+	It("Test GetServeAPI", func() {
+		spec := &rayv1alpha1.RayServiceSpec{
+			ServeDeploymentGraphSpec: rayv1alpha1.ServeDeploymentGraphSpec{ImportPath: "fruit.deployment_graph"},
+			RayClusterSpec:           rayv1alpha1.RayClusterSpec{RayVersion: "2.3.0"},
+		}
+		serveAPI, err := GetServeAPI(spec)
+		Expect(err).To(BeNil())
+		Expect(serveAPI).To(Equal(ServeDeploymentsAPI))
+
+		// The deployments API has no gRPC proxy.
+		spec.ServeGRPC = &rayv1alpha1.ServeGRPCSpec{}
+		_, err = GetServeAPI(spec)
+		Expect(err).NotTo(BeNil())
+		spec.ServeGRPC = nil
+
+		// serveConfig can't be mixed with multiple applications.
+		spec.ServeConfigV2 = "applications:\n  - name: fruit\n    import_path: fruit.deployment_graph\n"
+		_, err = GetServeAPI(spec)
+		Expect(err).NotTo(BeNil())
+
+		// Multiple applications need Ray 2.4.
+		spec.ServeDeploymentGraphSpec = rayv1alpha1.ServeDeploymentGraphSpec{}
+		_, err = GetServeAPI(spec)
+		Expect(err).NotTo(BeNil())
+
+		spec.RayClusterSpec.RayVersion = "2.4.0"
+		serveAPI, err = GetServeAPI(spec)
+		Expect(err).To(BeNil())
+		Expect(serveAPI).To(Equal(ServeApplicationsAPI))
+
+		spec.ServeApplications = []rayv1alpha1.ServeApplicationSpec{{Name: "fruit", ImportPath: "fruit.deployment_graph"}}
+		_, err = GetServeAPI(spec)
+		Expect(err).NotTo(BeNil())
+
+		spec.ServeConfigV2 = ""
+		serveAPI, err = GetServeAPI(spec)
+		Expect(err).To(BeNil())
+		Expect(serveAPI).To(Equal(ServeApplicationsAPI))
+
+		spec.ServeApplications = nil
+		spec.ServeConfigV2 = "import_path: fruit.deployment_graph"
+		_, err = GetServeAPI(spec)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("Test GetServeApplicationsConfig with serveGRPC", func() {
+		spec := &rayv1alpha1.RayServiceSpec{
+			ServeApplications: []rayv1alpha1.ServeApplicationSpec{{Name: "fruit", ImportPath: "fruit.deployment_graph"}},
+		}
+		config, err := GetServeApplicationsConfig(spec, 9000)
+		Expect(err).To(BeNil())
+		Expect(config.(ServeApplicationsConfig).GRPCOptions).To(BeNil())
+
+		spec.ServeGRPC = &rayv1alpha1.ServeGRPCSpec{ServicerFunctions: []string{"fruit_pb2_grpc.add_FruitServicer_to_server"}}
+		config, err = GetServeApplicationsConfig(spec, 9001)
+		Expect(err).To(BeNil())
+		Expect(*config.(ServeApplicationsConfig).GRPCOptions).To(Equal(ServeGRPCOptions{
+			Port:                  9001,
+			GRPCServicerFunctions: []string{"fruit_pb2_grpc.add_FruitServicer_to_server"},
+		}))
+	})
+
+	It("Test IsServeApplicationsAPISupported", func() {
+		for _, version := range []string{"2.4.0", "2.5", "3.0.0", "nightly", ""} {
+			Expect(IsServeApplicationsAPISupported(version)).To(BeTrue(), version)
+		}
+		for _, version := range []string{"2.3.1", "2.0", "1.13.0"} {
+			Expect(IsServeApplicationsAPISupported(version)).To(BeFalse(), version)
+		}
+	})
 })
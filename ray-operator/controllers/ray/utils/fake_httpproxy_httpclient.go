@@ -15,9 +15,9 @@ type FakeRayHttpProxyClient struct {
 	httpProxyURL string
 }
 
-func (r *FakeRayHttpProxyClient) InitClient() {
+func (r *FakeRayHttpProxyClient) InitClient(timeout time.Duration) {
 	r.client = http.Client{
-		Timeout: 20 * time.Millisecond,
+		Timeout: timeout,
 	}
 }
 
@@ -12,9 +12,12 @@ import (
 )
 
 type FakeRayDashboardClient struct {
-	client        http.Client
-	dashboardURL  string
-	serveStatuses ServeDeploymentStatuses
+	client              http.Client
+	dashboardURL        string
+	serveStatuses       ServeDeploymentStatuses
+	applicationStatuses ServeApplicationStatuses
+	applicationsConfig  interface{}
+	jobInfos            []RayJobInfo
 }
 
 var _ RayDashboardClientInterface = (*FakeRayDashboardClient)(nil)
@@ -37,6 +40,19 @@ func (r *FakeRayDashboardClient) GetDeploymentsStatus(_ context.Context) (*Serve
 	return &r.serveStatuses, nil
 }
 
+func (r *FakeRayDashboardClient) UpdateApplications(_ context.Context, spec *rayv1alpha1.RayServiceSpec, grpcPort int) error {
+	config, err := GetServeApplicationsConfig(spec, grpcPort)
+	if err != nil {
+		return err
+	}
+	r.applicationsConfig = config
+	return nil
+}
+
+func (r *FakeRayDashboardClient) GetApplicationsStatus(_ context.Context) (*ServeApplicationStatuses, error) {
+	return &r.applicationStatuses, nil
+}
+
 func (r *FakeRayDashboardClient) ConvertServeConfig(specs []rayv1alpha1.ServeConfigSpec) []ServeConfigSpec {
 	serveConfigToSend := make([]ServeConfigSpec, len(specs))
 
@@ -83,10 +99,32 @@ func (r *FakeRayDashboardClient) SetServeStatus(status ServeDeploymentStatuses)
 	r.serveStatuses = status
 }
 
+func (r *FakeRayDashboardClient) SetApplicationsStatus(statuses ServeApplicationStatuses) {
+	r.applicationStatuses = statuses
+}
+
+// GetApplicationsConfig returns the config of the last UpdateApplications.
+func (r *FakeRayDashboardClient) GetApplicationsConfig() interface{} {
+	return r.applicationsConfig
+}
+
+func (r *FakeRayDashboardClient) SetJobInfos(jobInfos []RayJobInfo) {
+	r.jobInfos = jobInfos
+}
+
 func (r *FakeRayDashboardClient) GetJobInfo(_ context.Context, jobId string) (*RayJobInfo, error) {
+	for i := range r.jobInfos {
+		if r.jobInfos[i].SubmissionId == jobId || r.jobInfos[i].JobId == jobId {
+			return &r.jobInfos[i], nil
+		}
+	}
 	return nil, nil
 }
 
+func (r *FakeRayDashboardClient) ListJobs(_ context.Context) ([]RayJobInfo, error) {
+	return r.jobInfos, nil
+}
+
 func (r *FakeRayDashboardClient) SubmitJob(_ context.Context, rayJob *rayv1alpha1.RayJob, log *logr.Logger) (jobId string, err error) {
 	return "", nil
 }
@@ -94,3 +132,8 @@ func (r *FakeRayDashboardClient) SubmitJob(_ context.Context, rayJob *rayv1alpha
 func (r *FakeRayDashboardClient) StopJob(_ context.Context, jobName string, log *logr.Logger) (err error) {
 	return nil
 }
+
+func (r *FakeRayDashboardClient) GetJobLog(_ context.Context, jobName string, log *logr.Logger) (*string, error) {
+	lg := "log message"
+	return &lg, nil
+}
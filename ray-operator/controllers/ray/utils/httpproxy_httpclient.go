@@ -1,16 +1,22 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
+
+	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
 )
 
 const healthCheckPath = "/-/healthz"
 
 type RayHttpProxyClientInterface interface {
-	InitClient()
+	InitClient(timeout time.Duration)
 	CheckHealth() error
 	SetHostIp(hostIp string, port int)
 }
@@ -27,9 +33,9 @@ type RayHttpProxyClient struct {
 	httpProxyURL string
 }
 
-func (r *RayHttpProxyClient) InitClient() {
+func (r *RayHttpProxyClient) InitClient(timeout time.Duration) {
 	r.client = http.Client{
-		Timeout: 20 * time.Millisecond,
+		Timeout: timeout,
 	}
 }
 
@@ -56,3 +62,52 @@ func (r *RayHttpProxyClient) CheckHealth() error {
 
 	return nil
 }
+
+// maxProbeBodyBytes is the maximum size of the body of a response read by an application probe.
+const maxProbeBodyBytes = 1 << 20
+
+// ProbeServeApplication sends the request of the application probe to the Serve HTTP proxy at serveURL, and checks
+// the response against the expected status code and body.
+func ProbeServeApplication(ctx context.Context, serveURL string, probe *rayv1alpha1.ServeApplicationProbe, timeout time.Duration) error {
+	method := probe.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, serveURL+probe.Path, strings.NewReader(probe.Body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxProbeBodyBytes))
+	if err != nil {
+		return err
+	}
+	if probe.ExpectedStatus != nil {
+		if resp.StatusCode != int(*probe.ExpectedStatus) {
+			return fmt.Errorf("%s %s returned %s instead of %d", method, probe.Path, resp.Status, *probe.ExpectedStatus)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %s", method, probe.Path, resp.Status)
+	}
+
+	if probe.ExpectedBodyRegex != "" {
+		bodyRegex, err := regexp.Compile(probe.ExpectedBodyRegex)
+		if err != nil {
+			return fmt.Errorf("invalid expectedBodyRegex %s: %v", probe.ExpectedBodyRegex, err)
+		}
+		if !bodyRegex.Match(body) {
+			return fmt.Errorf("%s %s returned a body that doesn't match %s", method, probe.Path, probe.ExpectedBodyRegex)
+		}
+	}
+
+	return nil
+}
@@ -165,6 +165,29 @@ func GenerateServeServiceLabel(serviceName string) string {
 	return fmt.Sprintf("%s-%s", serviceName, ServeName)
 }
 
+// GenerateServeIngressName generates the name of the ingress routing the serve traffic of a RayService.
+func GenerateServeIngressName(serviceName string, canary bool) string {
+	if canary {
+		return fmt.Sprintf("%s-%s-%s", serviceName, ServeName, "canary-ingress")
+	}
+	return fmt.Sprintf("%s-%s-%s", serviceName, ServeName, "ingress")
+}
+
+// GenerateServeRouteName generates the name of the HTTPRoute routing the serve traffic of a RayService.
+func GenerateServeRouteName(serviceName string) string {
+	return fmt.Sprintf("%s-%s-%s", serviceName, ServeName, "route")
+}
+
+// GenerateServeGRPCIngressName generates the name of the ingress routing the gRPC serve traffic of a RayService.
+func GenerateServeGRPCIngressName(serviceName string) string {
+	return fmt.Sprintf("%s-%s-%s", serviceName, ServeName, "grpc-ingress")
+}
+
+// GenerateServeGRPCRouteName generates the name of the GRPCRoute routing the gRPC serve traffic of a RayService.
+func GenerateServeGRPCRouteName(serviceName string) string {
+	return fmt.Sprintf("%s-%s-%s", serviceName, ServeName, "grpc-route")
+}
+
 // GenerateIngressName generates an ingress name from cluster name
 func GenerateIngressName(clusterName string) string {
 	return fmt.Sprintf("%s-%s-%s", clusterName, rayiov1alpha1.HeadNode, "ingress")
@@ -11,6 +11,7 @@ import (
 
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray"
 	"github.com/ray-project/kuberay/ray-operator/controllers/ray/batchscheduler"
+	"github.com/ray-project/kuberay/ray-operator/controllers/ray/logsink"
 
 	"go.uber.org/zap/zapcore"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -18,7 +19,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	k8szap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	rayv1alpha1 "github.com/ray-project/kuberay/ray-operator/apis/ray/v1alpha1"
@@ -67,6 +71,10 @@ func main() {
 		"Synchronize logs to local file")
 	flag.BoolVar(&ray.EnableBatchScheduler, "enable-batch-scheduler", false,
 		"Enable batch scheduler. Currently is volcano, which supports gang scheduler policy.")
+	flag.IntVar(&ray.ServeHealthCheckConcurrency, "serve-health-check-concurrency", ray.ServeHealthCheckConcurrency,
+		"The number of Serve HTTP proxies of a RayCluster checked at the same time.")
+	flag.StringVar(&logsink.PVCMountRoot, "driver-logs-pvc-root", logsink.PVCMountRoot,
+		"Directory under which the PersistentVolumeClaims used to persist RayJob driver logs are mounted, one sub-directory per claim.")
 
 	opts := k8szap.Options{
 		Development: true,
@@ -125,6 +133,7 @@ func main() {
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "ray-operator-leader",
 		Namespace:              watchNamespace,
+		NewClient:              newClient,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -143,6 +152,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "RayJob")
 		os.Exit(1)
 	}
+	if err = ray.NewRayCronJobReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RayCronJob")
+		os.Exit(1)
+	}
+	if err = ray.NewRayWorkerGroupReconciler(mgr).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RayWorkerGroup")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -160,3 +177,18 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// newClient creates a client that also reads unstructured objects, e.g. the routes of the Gateway API, from the cache,
+// since the RayService controller gets them at every reconciliation.
+func newClient(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+	c, err := client.New(config, options)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewDelegatingClient(client.NewDelegatingClientInput{
+		CacheReader:       cache,
+		Client:            c,
+		UncachedObjects:   uncachedObjects,
+		CacheUnstructured: true,
+	})
+}
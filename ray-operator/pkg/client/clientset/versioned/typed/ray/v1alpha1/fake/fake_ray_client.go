@@ -16,6 +16,10 @@ func (c *FakeRayV1alpha1) RayClusters(namespace string) v1alpha1.RayClusterInter
 	return &FakeRayClusters{c, namespace}
 }
 
+func (c *FakeRayV1alpha1) RayCronJobs(namespace string) v1alpha1.RayCronJobInterface {
+	return &FakeRayCronJobs{c, namespace}
+}
+
 func (c *FakeRayV1alpha1) RayJobs(namespace string) v1alpha1.RayJobInterface {
 	return &FakeRayJobs{c, namespace}
 }
@@ -24,6 +28,10 @@ func (c *FakeRayV1alpha1) RayServices(namespace string) v1alpha1.RayServiceInter
 	return &FakeRayServices{c, namespace}
 }
 
+func (c *FakeRayV1alpha1) RayWorkerGroups(namespace string) v1alpha1.RayWorkerGroupInterface {
+	return &FakeRayWorkerGroups{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeRayV1alpha1) RESTClient() rest.Interface {
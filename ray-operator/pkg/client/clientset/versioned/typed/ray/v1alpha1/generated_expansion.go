@@ -4,6 +4,10 @@ package v1alpha1
 
 type RayClusterExpansion interface{}
 
+type RayCronJobExpansion interface{}
+
 type RayJobExpansion interface{}
 
 type RayServiceExpansion interface{}
+
+type RayWorkerGroupExpansion interface{}
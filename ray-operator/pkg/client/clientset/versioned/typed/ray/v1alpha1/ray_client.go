@@ -13,8 +13,10 @@ import (
 type RayV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	RayClustersGetter
+	RayCronJobsGetter
 	RayJobsGetter
 	RayServicesGetter
+	RayWorkerGroupsGetter
 }
 
 // RayV1alpha1Client is used to interact with features provided by the ray.io group.
@@ -26,6 +28,10 @@ func (c *RayV1alpha1Client) RayClusters(namespace string) RayClusterInterface {
 	return newRayClusters(c, namespace)
 }
 
+func (c *RayV1alpha1Client) RayCronJobs(namespace string) RayCronJobInterface {
+	return newRayCronJobs(c, namespace)
+}
+
 func (c *RayV1alpha1Client) RayJobs(namespace string) RayJobInterface {
 	return newRayJobs(c, namespace)
 }
@@ -34,6 +40,10 @@ func (c *RayV1alpha1Client) RayServices(namespace string) RayServiceInterface {
 	return newRayServices(c, namespace)
 }
 
+func (c *RayV1alpha1Client) RayWorkerGroups(namespace string) RayWorkerGroupInterface {
+	return newRayWorkerGroups(c, namespace)
+}
+
 // NewForConfig creates a new RayV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
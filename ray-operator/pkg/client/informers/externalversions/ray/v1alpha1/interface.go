@@ -10,10 +10,14 @@ import (
 type Interface interface {
 	// RayClusters returns a RayClusterInformer.
 	RayClusters() RayClusterInformer
+	// RayCronJobs returns a RayCronJobInformer.
+	RayCronJobs() RayCronJobInformer
 	// RayJobs returns a RayJobInformer.
 	RayJobs() RayJobInformer
 	// RayServices returns a RayServiceInformer.
 	RayServices() RayServiceInformer
+	// RayWorkerGroups returns a RayWorkerGroupInformer.
+	RayWorkerGroups() RayWorkerGroupInformer
 }
 
 type version struct {
@@ -32,6 +36,11 @@ func (v *version) RayClusters() RayClusterInformer {
 	return &rayClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// RayCronJobs returns a RayCronJobInformer.
+func (v *version) RayCronJobs() RayCronJobInformer {
+	return &rayCronJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // RayJobs returns a RayJobInformer.
 func (v *version) RayJobs() RayJobInformer {
 	return &rayJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -41,3 +50,8 @@ func (v *version) RayJobs() RayJobInformer {
 func (v *version) RayServices() RayServiceInformer {
 	return &rayServiceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RayWorkerGroups returns a RayWorkerGroupInformer.
+func (v *version) RayWorkerGroups() RayWorkerGroupInformer {
+	return &rayWorkerGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -10,6 +10,14 @@ type RayClusterListerExpansion interface{}
 // RayClusterNamespaceLister.
 type RayClusterNamespaceListerExpansion interface{}
 
+// RayCronJobListerExpansion allows custom methods to be added to
+// RayCronJobLister.
+type RayCronJobListerExpansion interface{}
+
+// RayCronJobNamespaceListerExpansion allows custom methods to be added to
+// RayCronJobNamespaceLister.
+type RayCronJobNamespaceListerExpansion interface{}
+
 // RayJobListerExpansion allows custom methods to be added to
 // RayJobLister.
 type RayJobListerExpansion interface{}
@@ -25,3 +33,11 @@ type RayServiceListerExpansion interface{}
 // RayServiceNamespaceListerExpansion allows custom methods to be added to
 // RayServiceNamespaceLister.
 type RayServiceNamespaceListerExpansion interface{}
+
+// RayWorkerGroupListerExpansion allows custom methods to be added to
+// RayWorkerGroupLister.
+type RayWorkerGroupListerExpansion interface{}
+
+// RayWorkerGroupNamespaceListerExpansion allows custom methods to be added to
+// RayWorkerGroupNamespaceLister.
+type RayWorkerGroupNamespaceListerExpansion interface{}